@@ -1,21 +1,145 @@
 package api
 
 import (
+	"context"
+	"os"
 	"sketch/db"
+	"sketch/internal/auth"
 	"sketch/internal/canvas"
+	"sketch/internal/ratelimit"
 	"sketch/internal/routing"
+	"sketch/internal/workspace"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
 )
 
 func Start() {
 	router := routing.NewRouter()
 	connection := db.GetConnection()
-	repository := canvas.NewRepository(connection)
+	keys := auth.NewService(auth.NewRepository(connection), os.Getenv("AUTH_ADMIN_TOKEN"))
+	workspaces := workspace.NewService(workspace.NewRepository(connection))
+	router.Use(auth.Middleware(keys))
+	router.Use(ratelimit.Middleware(ratelimit.NewLimiter(ratelimit.PerMinute(envInt("RATE_LIMIT_REQUESTS"))), ratelimit.One))
+	router.Use(workspace.Middleware(workspaces))
+	outbox := canvas.NewOutbox(connection)
+	// The revisions are written inside the transaction of the outbox, with the
+	// change and its event, and the canvases saved inside the one counting
+	// them against the quota of their owner.
+	quota := envInt("CANVAS_QUOTA")
+	repository := canvas.NewQuotaRepository(
+		canvas.NewOutboxRevisionRepository(canvas.NewRevisionRepository(newRepository(connection), connection), outbox),
+		connection, quota)
 	drawer := canvas.NewDrawer()
-	service := canvas.NewService(repository, drawer)
+	symbols := canvas.NewSymbolRepository(connection)
+	broker := canvas.NewBroker()
+	webhooks := canvas.NewWebhookRepository(connection)
+	webhookService := canvas.NewWebhookService(webhooks)
+	// The outbox records the events with the changes and the relay publishes
+	// them, so the services have nothing left to publish.
+	events := canvas.Publishers{}
+	relay := canvas.NewRelay(outbox, map[string]canvas.Sink{
+		"log":      canvas.NewLogSink(),
+		"broker":   canvas.NewPublisherSink(broker),
+		"webhooks": webhookService,
+	})
+	service := canvas.NewService(repository, drawer, symbols, events)
+	links := canvas.NewLinkService(repository, canvas.NewLinkRepository(connection), os.Getenv("SHARE_LINK_SECRET"))
+	accessService := canvas.NewAccessService(repository, canvas.NewAccessRepository(connection))
 	handler := canvas.NewHandler(service)
+	symbolHandler := canvas.NewSymbolHandler(canvas.NewSymbolService(repository, drawer, symbols, accessService, events))
+	templateHandler := canvas.NewTemplateHandler(canvas.NewTemplateService(canvas.NewTemplateRepository(connection), service))
+	eventHandler := canvas.NewEventHandler(service, broker)
+	webhookHandler := canvas.NewWebhookHandler(webhookService)
+	keyHandler := auth.NewHandler(keys)
+	workspaceHandler := workspace.NewHandler(workspaces)
+	access := canvas.NewAccessHandler(accessService, links)
+	linkHandler := canvas.NewLinkHandler(links)
+	quotas := canvas.NewQuotaHandler(canvas.NewQuotaService(repository, quota))
+	create := func(next routing.Handle) routing.Handle {
+		return access.Create(quotas.Create(next))
+	}
+	// Renders also take the area of their canvas from a limit of their own,
+	// shared by the draws of the requests, the templates and the websockets.
+	renderLimiter := ratelimit.NewLimiter(ratelimit.PerMinute(envInt("RATE_LIMIT_RENDER_CELLS")))
+	renders := ratelimit.Middleware(renderLimiter, handler.RenderCost)
+	templateRenders := ratelimit.Middleware(renderLimiter, templateHandler.RenderCost)
+	collaborationHandler := canvas.NewCollaborationHandler(canvas.NewHub(service), renderLimiter, envList("WEBSOCKET_ALLOWED_ORIGINS"))
+	animationHandler := canvas.NewAnimationHandler(canvas.NewAnimationService(repository, canvas.NewAnimationRepository(connection), drawer, symbols, events))
+
+	router.Get("/", access.Read(handler.Show))
+	router.Post("/", create(renders(handler.Draw)))
+	router.Get("/:id", access.Read(handler.GetById))
+	router.Post("/:id", access.Modify(renders(handler.Edit)))
+	router.Delete("/:id", access.Delete(handler.Delete))
+	router.Post("/:id/crop", create(handler.Crop))
+	router.Post("/:id/shares", access.Share)
+	router.Get("/:id/shares", access.GetShares)
+	router.Delete("/:id/shares/:key", access.Unshare)
+	router.Post("/:id/links", linkHandler.Create)
+	router.Get("/:id/links", linkHandler.GetLinks)
+	router.Delete("/:id/links/:link", linkHandler.Revoke)
+	router.Get("/:id/events", access.Read(eventHandler.Events))
+	router.Get("/:id/collaborate", access.Modify(collaborationHandler.Collaborate))
+	router.Get("/:id/frames/:n", access.Read(animationHandler.GetFrame))
+	router.Get("/:id/play", access.Read(animationHandler.Play))
+	router.Get("/:id/stream", access.Read(animationHandler.Stream))
+	router.Get("/:id/gif", access.Read(animationHandler.GIF))
+	router.Post("/import", create(handler.Import))
+	router.Post("/analyze", handler.Analyze)
+	router.Post("/convert", create(handler.Convert))
+	router.Post("/diagrams", create(handler.Diagram))
+	router.Post("/diagrams/dot", create(handler.DiagramDOT))
+	router.Post("/sequences", create(handler.Sequence))
+	router.Post("/animations", create(animationHandler.Save))
+	router.Post("/symbols", access.Create(symbolHandler.Save))
+	router.Get("/symbols/:name", symbolHandler.GetByName)
+	router.Post("/templates", access.Create(templateHandler.Save))
+	router.Get("/templates/:name", templateHandler.GetByName)
+	router.Post("/templates/:name/render", create(templateRenders(templateHandler.Render)))
+	router.Post("/webhooks", auth.Required(webhookHandler.Save))
+	router.Get("/webhooks/:id", auth.Required(webhookHandler.GetByID))
+	router.Delete("/webhooks/:id", auth.Required(webhookHandler.Delete))
+	router.Get("/webhooks/:id/deliveries", auth.Required(webhookHandler.Deliveries))
+	router.Post("/keys", keyHandler.Save)
+	router.Get("/keys", auth.Required(keyHandler.List))
+	router.Delete("/keys/:id", auth.Required(keyHandler.Delete))
+	router.Post("/workspaces", auth.Required(workspaceHandler.Save))
+	router.Get("/workspaces", auth.Required(workspaceHandler.List))
+	router.Get("/workspaces/:id/members", workspaceHandler.Members)
+	router.Post("/workspaces/:id/members", workspaceHandler.SaveMember)
+	router.Delete("/workspaces/:id/members/:owner", workspaceHandler.RemoveMember)
 
-	router.Get("/", handler.Show)
-	router.Post("/", handler.Draw)
-	router.Get("/:id", handler.GetById)
+	go relay.Run(context.Background())
+	go canvas.NewWebhookWorker(webhooks, canvas.NewWebhookClient()).Run(context.Background())
 	router.Run()
 }
+
+func newRepository(connection *sqlx.DB) canvas.Repository {
+	switch os.Getenv("CANVAS_STORAGE") {
+	case "chunked":
+		return canvas.NewChunkedRepository(connection)
+	case "events":
+		return canvas.NewStreamRepository(connection)
+	}
+	return canvas.NewRepository(connection)
+}
+
+// envList returns the comma separated values of the variable, none when it is
+// empty.
+func envList(name string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// envInt returns the number in the variable, zero when it has none.
+func envInt(name string) int {
+	value, _ := strconv.Atoi(os.Getenv(name))
+	return value
+}
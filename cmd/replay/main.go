@@ -0,0 +1,79 @@
+// Replay rebuilds a canvas stored with CANVAS_STORAGE=events as it was at any
+// point in time, using the same database variables as the api. Canvases of a
+// workspace need its id in -workspace.
+//
+//	go run ./cmd/replay -id <id> -at 2024-05-01T12:00:00Z
+//	go run ./cmd/replay -id <id> -events
+//	go run ./cmd/replay -id <id> -at 2024-05-01T12:00:00Z -restore
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sketch/db"
+	"sketch/internal/canvas"
+	"sketch/internal/workspace"
+	"time"
+)
+
+func main() {
+	id := flag.String("id", "", "id of the canvas")
+	at := flag.String("at", "", "RFC 3339 time to rebuild the canvas at, now by default")
+	inWorkspace := flag.String("workspace", "", "id of the workspace of the canvas, if it is in one")
+	events := flag.Bool("events", false, "list the events of the canvas instead")
+	restore := flag.Bool("restore", false, "save the rebuilt drawing as the current one")
+	flag.Parse()
+
+	if err := run(*id, *at, *inWorkspace, *events, *restore); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(id, at, inWorkspace string, events, restore bool) error {
+	if id == "" {
+		return fmt.Errorf("-id is required")
+	}
+
+	when := time.Now().UTC()
+	if at != "" {
+		parsed, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			return fmt.Errorf("-at must be an RFC 3339 time: %w", err)
+		}
+		when = parsed.UTC()
+	}
+
+	ctx := workspace.WithScope(context.Background(), workspace.Scope{WorkspaceID: inWorkspace, Role: workspace.RoleAdmin})
+	connection := db.GetConnection()
+	repository := canvas.NewStreamRepository(connection)
+
+	if events {
+		stream, err := repository.GetEvents(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, event := range stream {
+			fmt.Printf("%d\t%s\t%s\t%d spans\n", event.Version, event.Kind, event.CreatedAt.Format(time.RFC3339), len(event.Patch.Spans))
+		}
+		return nil
+	}
+
+	replayed, err := repository.Replay(ctx, id, when)
+	if err != nil {
+		return fmt.Errorf("failed to replay '%s': %w", id, err)
+	}
+
+	if restore {
+		// Going through the outbox lets the api publish the change.
+		current := canvas.NewOutboxRepository(repository, canvas.NewOutbox(connection))
+		if err := current.Update(ctx, replayed); err != nil {
+			return fmt.Errorf("failed to restore '%s': %w", id, err)
+		}
+	}
+
+	fmt.Println(replayed.Drawing)
+	return nil
+}
@@ -1,6 +1,219 @@
 create table drawings
 (
-    id         varchar(36) not null primary key,
+    id           varchar(36) not null primary key,
+    owner_id     varchar(36) not null default '',
+    workspace_id varchar(36) not null default '',
+    drawing      text        not null,
+    version      integer     not null default 1,
+    created_at   timestamp   not null
+);
+
+create table chunked_drawings
+(
+    id           varchar(36) not null primary key,
+    owner_id     varchar(36) not null default '',
+    workspace_id varchar(36) not null default '',
+    width        integer     not null,
+    height       integer     not null,
+    version      integer     not null default 1,
+    created_at   timestamp   not null
+);
+
+create table drawing_chunks
+(
+    drawing_id varchar(36) not null references chunked_drawings (id) on delete cascade,
+    chunk_x    integer     not null,
+    chunk_y    integer     not null,
+    content    text        not null,
+    primary key (drawing_id, chunk_x, chunk_y)
+);
+
+create table symbols
+(
+    owner_id     varchar(36) not null default '',
+    workspace_id varchar(36) not null default '',
+    name         varchar(64) not null,
+    requests     jsonb,
+    drawing      text,
+    updated_at   timestamp   not null,
+    primary key (owner_id, workspace_id, name)
+);
+
+create table drawing_operations
+(
+    drawing_id varchar(36) not null primary key,
+    requests   jsonb       not null
+);
+
+create table symbol_links
+(
+    owner_id     varchar(36) not null default '',
+    workspace_id varchar(36) not null default '',
+    symbol_name  varchar(64) not null,
+    drawing_id   varchar(36) not null,
+    primary key (owner_id, workspace_id, symbol_name, drawing_id),
+    foreign key (owner_id, workspace_id, symbol_name) references symbols (owner_id, workspace_id, name) on delete cascade
+);
+
+create table templates
+(
+    owner_id     varchar(36) not null default '',
+    workspace_id varchar(36) not null default '',
+    name         varchar(64) not null,
+    variables    jsonb       not null,
+    requests     jsonb       not null,
+    updated_at   timestamp   not null,
+    primary key (owner_id, workspace_id, name)
+);
+
+create table drawing_frames
+(
+    drawing_id varchar(36) not null,
+    position   integer     not null,
+    drawing    text        not null,
+    duration   integer     not null,
+    primary key (drawing_id, position)
+);
+
+create table drawing_revisions
+(
+    id         bigserial   not null primary key,
+    drawing_id varchar(36) not null,
+    drawing    text        not null,
+    created_at timestamp   not null
+);
+
+create table webhooks
+(
+    id         varchar(36)   not null primary key,
+    owner_id   varchar(36)   not null default '',
+    url        varchar(2048) not null,
+    events     jsonb         not null,
+    secret     text          not null,
+    created_at timestamp     not null
+);
+
+create table webhook_deliveries
+(
+    id              varchar(36) not null primary key,
+    webhook_id      varchar(36) not null references webhooks (id) on delete cascade,
+    event_id        varchar(36) not null,
+    event_type      varchar(32) not null,
+    payload         jsonb       not null,
+    status          varchar(16) not null,
+    attempts        integer     not null default 0,
+    response_status integer     not null default 0,
+    error           text        not null default '',
+    next_attempt_at timestamp   not null,
+    created_at      timestamp   not null,
+    updated_at      timestamp   not null,
+    unique (webhook_id, event_id)
+);
+
+create index webhook_deliveries_due on webhook_deliveries (status, next_attempt_at);
+
+create table outbox_events
+(
+    position     bigserial   not null primary key,
+    id           varchar(36) not null unique,
+    event_type   varchar(32) not null,
+    canvas_id    varchar(36) not null,
+    owner_id     varchar(36) not null default '',
+    drawing      text        not null,
+    created_at   timestamp   not null,
+    locked_until timestamp,
+    published_at timestamp
+);
+
+create index outbox_events_pending on outbox_events (position) where published_at is null;
+
+create table outbox_deliveries
+(
+    event_id     varchar(36) not null references outbox_events (id) on delete cascade,
+    sink         varchar(32) not null,
+    delivered_at timestamp   not null,
+    primary key (event_id, sink)
+);
+
+create table canvas_streams
+(
+    id           varchar(36) not null primary key,
+    owner_id     varchar(36) not null default '',
+    workspace_id varchar(36) not null default '',
+    version      integer     not null,
+    deleted      boolean     not null default false,
+    created_at   timestamp   not null
+);
+
+create table canvas_stream_events
+(
+    drawing_id varchar(36) not null references canvas_streams (id),
+    version    integer     not null,
+    kind       varchar(16) not null,
+    patch      jsonb       not null,
+    created_at timestamp   not null,
+    primary key (drawing_id, version)
+);
+
+create index canvas_stream_events_time on canvas_stream_events (drawing_id, created_at);
+
+create table canvas_snapshots
+(
+    drawing_id varchar(36) not null references canvas_streams (id),
+    version    integer     not null,
     drawing    text        not null,
+    created_at timestamp   not null,
+    primary key (drawing_id, version)
+);
+
+create table api_keys
+(
+    id         varchar(36) not null primary key,
+    owner_id   varchar(36) not null,
+    name       varchar(64) not null,
+    hash       varchar(64) not null unique,
+    created_at timestamp   not null,
+    revoked_at timestamp
+);
+
+create index api_keys_owner on api_keys (owner_id);
+
+create table canvas_shares
+(
+    drawing_id varchar(36) not null,
+    key_id     varchar(36) not null references api_keys (id) on delete cascade,
+    created_at timestamp   not null,
+    primary key (drawing_id, key_id)
+);
+
+create table workspaces
+(
+    id         varchar(36) not null primary key,
+    name       varchar(64) not null,
     created_at timestamp   not null
-);
\ No newline at end of file
+);
+
+create table workspace_members
+(
+    workspace_id varchar(36) not null references workspaces (id) on delete cascade,
+    owner_id     varchar(36) not null,
+    role         varchar(16) not null,
+    created_at   timestamp   not null,
+    primary key (workspace_id, owner_id)
+);
+
+create index workspace_members_owner on workspace_members (owner_id);
+
+create table canvas_links
+(
+    id           varchar(36) not null primary key,
+    drawing_id   varchar(36) not null,
+    workspace_id varchar(36) not null default '',
+    permission   varchar(8)  not null,
+    created_by   varchar(36) not null,
+    expires_at   timestamp   not null,
+    created_at   timestamp   not null,
+    revoked_at   timestamp
+);
+
+create index canvas_links_drawing on canvas_links (drawing_id);
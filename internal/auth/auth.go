@@ -0,0 +1,101 @@
+// Package auth identifies the clients of the API by their API keys. Keys
+// belong to an owner, and every key of an owner acts on its behalf.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"sketch/internal/errors"
+	"strings"
+	"time"
+)
+
+const (
+	// keyPrefix tells API keys apart from other secrets, in logs or code.
+	keyPrefix = "sk_"
+	keySize   = 32
+
+	maxKeyNameLength = 64
+)
+
+var (
+	ErrInvalidKey         = errors.Error("invalid api key")
+	ErrKeyRequired        = errors.Error("an api key is required")
+	ErrKeyNotFound        = errors.Error("api key not found")
+	ErrInvalidKeyName     = errors.Error("key name must have between 1 and 64 characters")
+	ErrAdminTokenRequired = errors.Error("a valid admin token is required to create a new owner")
+)
+
+type (
+	// Key is an API key. Only the hash of the secret is stored, the secret
+	// itself is only shown once, when the key is created.
+	Key struct {
+		ID        string     `json:"id" db:"id"`
+		OwnerID   string     `json:"owner_id" db:"owner_id"`
+		Name      string     `json:"name" db:"name"`
+		Hash      string     `json:"-" db:"hash"`
+		CreatedAt time.Time  `json:"created_at" db:"created_at"`
+		RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	}
+
+	KeyRequest struct {
+		Name string `json:"name"`
+		// AdminToken allows creating the first key of a new owner.
+		AdminToken string `json:"-"`
+	}
+
+	// NewKey is a key just created, with its secret.
+	NewKey struct {
+		Key
+		Secret string `json:"key"`
+	}
+
+	// Principal is who makes a request.
+	Principal struct {
+		KeyID   string
+		OwnerID string
+	}
+
+	principalKey struct{}
+)
+
+func (r KeyRequest) Validate() error {
+	if name := strings.TrimSpace(r.Name); name == "" || len(name) > maxKeyNameLength {
+		return ErrInvalidKeyName
+	}
+	return nil
+}
+
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns who makes the request, when it was authenticated.
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+	return principal, ok
+}
+
+// OwnerID returns the owner making the request, or an empty string for
+// anonymous requests.
+func OwnerID(ctx context.Context) string {
+	principal, _ := FromContext(ctx)
+	return principal.OwnerID
+}
+
+func generateSecret() (string, error) {
+	secret := make([]byte, keySize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// HashKey returns how the secret of a key is stored.
+func HashKey(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
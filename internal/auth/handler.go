@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sketch/internal/routing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// Save creates a key for the owner of the request. Anonymous requests create
+// a new owner, with the admin token when the server has one. The response is
+// the only one showing the key.
+func (c *Handler) Save(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+	request, err := routing.FromJSON[KeyRequest](r)
+	if err != nil {
+		return fmt.Errorf("failed to get json body: %w", err)
+	}
+
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	request.AdminToken = r.Header.Get(HeaderAdminToken)
+	key, err := c.service.Create(r.Context(), request)
+
+	if errors.Is(err, ErrAdminTokenRequired) {
+		return routing.Unauthorized(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, key)
+}
+
+// List returns every key of the owner, the revoked ones included.
+func (c *Handler) List(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+	keys, err := c.service.List(r.Context())
+
+	if errors.Is(err, ErrKeyRequired) {
+		return routing.Unauthorized(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, keys)
+}
+
+func (c *Handler) Delete(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	err := c.service.Revoke(r.Context(), params.ByName("id"))
+
+	if errors.Is(err, ErrKeyRequired) {
+		return routing.Unauthorized(w, err)
+	}
+
+	if errors.Is(err, ErrKeyNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
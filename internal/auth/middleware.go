@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"sketch/internal/routing"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	HeaderAPIKey     = "X-API-Key"
+	HeaderAdminToken = "X-Admin-Token"
+)
+
+// Middleware authenticates the requests sending an API key, either as a bearer
+// token or in the X-API-Key header. Requests without a key go on anonymously,
+// those with an invalid one are refused.
+func Middleware(service Service) routing.Middleware {
+	return func(next routing.Handle) routing.Handle {
+		return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+			secret := requestKey(r)
+			if secret == "" {
+				return next(w, r, params)
+			}
+
+			principal, err := service.Authenticate(r.Context(), secret)
+			if errors.Is(err, ErrInvalidKey) {
+				return routing.Unauthorized(w, err)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			return next(w, r.WithContext(WithPrincipal(r.Context(), principal)), params)
+		}
+	}
+}
+
+// Required refuses the anonymous requests to the handler.
+func Required(next routing.Handle) routing.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+		if _, ok := FromContext(r.Context()); !ok {
+			return routing.Unauthorized(w, ErrKeyRequired)
+		}
+		return next(w, r, params)
+	}
+}
+
+func requestKey(r *http.Request) string {
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		scheme, token, _ := strings.Cut(authorization, " ")
+		if strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return r.Header.Get(HeaderAPIKey)
+}
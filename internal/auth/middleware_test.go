@@ -0,0 +1,107 @@
+package auth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sketch/internal/auth"
+	mock_auth "sketch/internal/auth/mocks"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	next := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+		principal, _ := auth.FromContext(r.Context())
+		_, _ = w.Write([]byte(principal.OwnerID))
+		return nil
+	}
+
+	t.Run("when the request has a bearer token, should authenticate it", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_auth.NewMockService(ctrl)
+		req := httptest.NewRequest(http.MethodGet, "/123", nil)
+		req.Header.Set("Authorization", "Bearer sk_123")
+
+		serviceMock.EXPECT().Authenticate(gomock.Any(), "sk_123").Return(auth.Principal{KeyID: "1", OwnerID: "owner"}, nil)
+
+		err := auth.Middleware(serviceMock)(next)(w, req, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "owner", w.Body.String())
+	})
+
+	t.Run("when the request has an api key header, should authenticate it", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_auth.NewMockService(ctrl)
+		req := httptest.NewRequest(http.MethodGet, "/123", nil)
+		req.Header.Set("X-API-Key", "sk_123")
+
+		serviceMock.EXPECT().Authenticate(gomock.Any(), "sk_123").Return(auth.Principal{KeyID: "1", OwnerID: "owner"}, nil)
+
+		err := auth.Middleware(serviceMock)(next)(w, req, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "owner", w.Body.String())
+	})
+
+	t.Run("when the request has no key, should go on anonymously", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_auth.NewMockService(ctrl)
+		req := httptest.NewRequest(http.MethodGet, "/123", nil)
+
+		err := auth.Middleware(serviceMock)(next)(w, req, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("when the key is invalid, should return unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_auth.NewMockService(ctrl)
+		req := httptest.NewRequest(http.MethodGet, "/123", nil)
+		req.Header.Set("X-API-Key", "sk_123")
+
+		serviceMock.EXPECT().Authenticate(gomock.Any(), "sk_123").Return(auth.Principal{}, auth.ErrInvalidKey)
+
+		err := auth.Middleware(serviceMock)(next)(w, req, nil)
+
+		assert.ErrorIs(t, err, auth.ErrInvalidKey)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestRequired(t *testing.T) {
+	next := func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) error {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+
+	t.Run("when the request is anonymous, should return unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+		err := auth.Required(next)(w, req, nil)
+
+		assert.ErrorIs(t, err, auth.ErrKeyRequired)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("when the request is authenticated, should handle it", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{KeyID: "1", OwnerID: "owner"}))
+
+		err := auth.Required(next)(w, req, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	goerrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	Repository interface {
+		Save(ctx context.Context, key Key) error
+		GetByHash(ctx context.Context, hash string) (Key, error)
+		GetByOwner(ctx context.Context, ownerID string) ([]Key, error)
+		Revoke(ctx context.Context, ownerID string, id string) error
+	}
+
+	repository struct {
+		db *sqlx.DB
+	}
+)
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Save(ctx context.Context, key Key) error {
+	const query = "insert into api_keys (id, owner_id, name, hash, created_at) values (:id, :owner_id, :name, :hash, :created_at)"
+	if _, err := r.db.NamedExecContext(ctx, query, key); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+// GetByHash returns the key with the hash, unless it was revoked.
+func (r *repository) GetByHash(ctx context.Context, hash string) (Key, error) {
+	const query = "select id, owner_id, name, hash, created_at, revoked_at from api_keys " +
+		"where hash = $1 and revoked_at is null"
+	var key Key
+	if err := r.db.GetContext(ctx, &key, query, hash); err != nil {
+		if goerrors.Is(err, sql.ErrNoRows) {
+			return key, ErrKeyNotFound
+		}
+
+		return key, fmt.Errorf("database err: %w", err)
+	}
+	return key, nil
+}
+
+func (r *repository) GetByOwner(ctx context.Context, ownerID string) ([]Key, error) {
+	const query = "select id, owner_id, name, hash, created_at, revoked_at from api_keys " +
+		"where owner_id = $1 order by created_at"
+	keys := make([]Key, 0)
+	if err := r.db.SelectContext(ctx, &keys, query, ownerID); err != nil {
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *repository) Revoke(ctx context.Context, ownerID string, id string) error {
+	const query = "update api_keys set revoked_at = $3 where id = $1 and owner_id = $2 and revoked_at is null"
+	result, err := r.db.ExecContext(ctx, query, id, ownerID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrKeyNotFound
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package auth_test
+
+import (
+	"context"
+	"database/sql"
+	"sketch/internal/auth"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_GetByHash(t *testing.T) {
+	const query = "select id, owner_id, name, hash, created_at, revoked_at from api_keys where hash = $1 and revoked_at is null"
+	setup := func() (auth.Repository, sqlmock.Sqlmock) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		return auth.NewRepository(sqlx.NewDb(mockDB, "sqlmock")), mock
+	}
+
+	t.Run("when the key exists, should return it", func(t *testing.T) {
+		repository, mock := setup()
+
+		mock.ExpectQuery(query).
+			WithArgs("hash").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "owner_id", "name", "hash"}).AddRow("1", "owner", "ci", "hash"))
+
+		key, err := repository.GetByHash(context.Background(), "hash")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "owner", key.OwnerID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when there is no key, should return not found", func(t *testing.T) {
+		repository, mock := setup()
+
+		mock.ExpectQuery(query).WithArgs("hash").WillReturnError(sql.ErrNoRows)
+
+		_, err := repository.GetByHash(context.Background(), "hash")
+
+		assert.ErrorIs(t, err, auth.ErrKeyNotFound)
+	})
+}
+
+func TestRepository_Revoke(t *testing.T) {
+	const query = "update api_keys set revoked_at = $3 where id = $1 and owner_id = $2 and revoked_at is null"
+
+	t.Run("when the owner has no such key, should return not found", func(t *testing.T) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		repository := auth.NewRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+		mock.ExpectExec(query).
+			WithArgs("1", "owner", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repository.Revoke(context.Background(), "owner", "1")
+
+		assert.ErrorIs(t, err, auth.ErrKeyNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	goerrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	Service interface {
+		// Create makes a new key for the owner of the request, or for a new
+		// owner when the request is anonymous.
+		Create(ctx context.Context, request KeyRequest) (*NewKey, error)
+		Authenticate(ctx context.Context, secret string) (Principal, error)
+		List(ctx context.Context) ([]Key, error)
+		Revoke(ctx context.Context, id string) error
+	}
+
+	service struct {
+		repository Repository
+		adminToken string
+	}
+)
+
+// NewService needs the admin token to create new owners. Anyone may create
+// one when it is empty.
+func NewService(repository Repository, adminToken string) Service {
+	return &service{
+		repository: repository,
+		adminToken: adminToken,
+	}
+}
+
+func (s service) Create(ctx context.Context, request KeyRequest) (*NewKey, error) {
+	ownerID := OwnerID(ctx)
+	if ownerID == "" {
+		if s.adminToken != "" && subtle.ConstantTimeCompare([]byte(request.AdminToken), []byte(s.adminToken)) != 1 {
+			return nil, ErrAdminTokenRequired
+		}
+		ownerID = uuid.New().String()
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	key := Key{
+		ID:        uuid.New().String(),
+		OwnerID:   ownerID,
+		Name:      strings.TrimSpace(request.Name),
+		Hash:      HashKey(secret),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.repository.Save(ctx, key); err != nil {
+		return nil, fmt.Errorf("error saving key: %w", err)
+	}
+	return &NewKey{Key: key, Secret: secret}, nil
+}
+
+func (s service) Authenticate(ctx context.Context, secret string) (Principal, error) {
+	if !strings.HasPrefix(secret, keyPrefix) {
+		return Principal{}, ErrInvalidKey
+	}
+
+	key, err := s.repository.GetByHash(ctx, HashKey(secret))
+	if goerrors.Is(err, ErrKeyNotFound) {
+		return Principal{}, ErrInvalidKey
+	}
+
+	if err != nil {
+		return Principal{}, fmt.Errorf("failed to authenticate: %w", err)
+	}
+	return Principal{KeyID: key.ID, OwnerID: key.OwnerID}, nil
+}
+
+func (s service) List(ctx context.Context) ([]Key, error) {
+	principal, ok := FromContext(ctx)
+	if !ok {
+		return nil, ErrKeyRequired
+	}
+
+	keys, err := s.repository.GetByOwner(ctx, principal.OwnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (s service) Revoke(ctx context.Context, id string) error {
+	principal, ok := FromContext(ctx)
+	if !ok {
+		return ErrKeyRequired
+	}
+
+	if err := s.repository.Revoke(ctx, principal.OwnerID, id); err != nil {
+		return fmt.Errorf("failed to revoke key '%s': %w", id, err)
+	}
+	return nil
+}
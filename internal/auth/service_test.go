@@ -0,0 +1,136 @@
+package auth_test
+
+import (
+	"context"
+	"database/sql"
+	"sketch/internal/auth"
+	mock_auth "sketch/internal/auth/mocks"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Create(t *testing.T) {
+	t.Run("when the request is anonymous, should create the key of a new owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_auth.NewMockRepository(ctrl)
+		service := auth.NewService(repositoryMock, "")
+		ctx := context.Background()
+
+		var saved auth.Key
+		repositoryMock.EXPECT().Save(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, key auth.Key) error {
+			saved = key
+			return nil
+		})
+
+		key, err := service.Create(ctx, auth.KeyRequest{Name: " ci "})
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(key.Secret, "sk_"))
+		assert.NotEmpty(t, key.OwnerID)
+		assert.Equal(t, "ci", key.Name)
+		assert.Equal(t, auth.HashKey(key.Secret), saved.Hash)
+	})
+
+	t.Run("when the request is authenticated, should create a key for the same owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_auth.NewMockRepository(ctrl)
+		service := auth.NewService(repositoryMock, "admin")
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+
+		repositoryMock.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+
+		key, err := service.Create(ctx, auth.KeyRequest{Name: "ci"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "owner", key.OwnerID)
+	})
+
+	t.Run("when the admin token does not match, should not create a new owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_auth.NewMockRepository(ctrl)
+		service := auth.NewService(repositoryMock, "admin")
+
+		key, err := service.Create(context.Background(), auth.KeyRequest{Name: "ci", AdminToken: "nimda"})
+
+		assert.ErrorIs(t, err, auth.ErrAdminTokenRequired)
+		assert.Nil(t, key)
+	})
+}
+
+func TestService_Authenticate(t *testing.T) {
+	t.Run("when the key exists, should return its owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_auth.NewMockRepository(ctrl)
+		service := auth.NewService(repositoryMock, "")
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().GetByHash(ctx, auth.HashKey("sk_123")).Return(auth.Key{ID: "1", OwnerID: "owner"}, nil)
+
+		principal, err := service.Authenticate(ctx, "sk_123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, auth.Principal{KeyID: "1", OwnerID: "owner"}, principal)
+	})
+
+	t.Run("when the key does not exist or was revoked, should return invalid key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_auth.NewMockRepository(ctrl)
+		service := auth.NewService(repositoryMock, "")
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().GetByHash(ctx, gomock.Any()).Return(auth.Key{}, auth.ErrKeyNotFound)
+
+		_, err := service.Authenticate(ctx, "sk_123")
+
+		assert.ErrorIs(t, err, auth.ErrInvalidKey)
+	})
+
+	t.Run("when the key does not look like one, should not look it up", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := auth.NewService(mock_auth.NewMockRepository(ctrl), "")
+
+		_, err := service.Authenticate(context.Background(), "123")
+
+		assert.ErrorIs(t, err, auth.ErrInvalidKey)
+	})
+
+	t.Run("when there is an error looking the key up, should return it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_auth.NewMockRepository(ctrl)
+		service := auth.NewService(repositoryMock, "")
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().GetByHash(ctx, gomock.Any()).Return(auth.Key{}, sql.ErrConnDone)
+
+		_, err := service.Authenticate(ctx, "sk_123")
+
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+	})
+}
+
+func TestService_Revoke(t *testing.T) {
+	t.Run("should only revoke the keys of the owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_auth.NewMockRepository(ctrl)
+		service := auth.NewService(repositoryMock, "")
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+
+		repositoryMock.EXPECT().Revoke(ctx, "owner", "2").Return(auth.ErrKeyNotFound)
+
+		err := service.Revoke(ctx, "2")
+
+		assert.ErrorIs(t, err, auth.ErrKeyNotFound)
+	})
+
+	t.Run("when the request is anonymous, should require a key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := auth.NewService(mock_auth.NewMockRepository(ctrl), "")
+
+		err := service.Revoke(context.Background(), "2")
+
+		assert.ErrorIs(t, err, auth.ErrKeyRequired)
+	})
+}
@@ -0,0 +1,155 @@
+package banner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sketch/internal/errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	signature = "flf2a"
+
+	ruleEqual        = 1
+	ruleUnderscore   = 2
+	ruleHierarchy    = 4
+	ruleOppositePair = 8
+	ruleBigX         = 16
+	ruleHardblank    = 32
+	layoutKerning    = 64
+	layoutSmushing   = 128
+)
+
+var (
+	ErrInvalidFont = errors.Error("invalid figlet font")
+)
+
+// Font is a FIGlet font, as described in the figfont.txt specification.
+type Font struct {
+	Height    int
+	Hardblank rune
+	// Layout holds the horizontal bits of the full layout: the smushing rules
+	// plus layoutKerning or layoutSmushing. Zero means full width.
+	Layout int
+	glyphs map[rune][]string
+}
+
+// ParseFont reads a .flf font: the header, the comments, the required
+// characters 32 to 126, the optional Deutsch characters and any code-tagged
+// character after them.
+func ParseFont(reader io.Reader) (*Font, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	if !scanner.Scan() {
+		return nil, ErrInvalidFont
+	}
+	font, comments, err := parseHeader(scanner.Text())
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < comments; i++ {
+		if !scanner.Scan() {
+			return nil, fmt.Errorf("%w: missing comment lines", ErrInvalidFont)
+		}
+	}
+
+	readGlyph := func() ([]string, bool) {
+		lines := make([]string, 0, font.Height)
+		for len(lines) < font.Height && scanner.Scan() {
+			lines = append(lines, trimEndmark(scanner.Text()))
+		}
+		return lines, len(lines) == font.Height
+	}
+
+	for code := rune(32); code <= 126; code++ {
+		glyph, ok := readGlyph()
+		if !ok {
+			return nil, fmt.Errorf("%w: missing character %d", ErrInvalidFont, code)
+		}
+		font.glyphs[code] = glyph
+	}
+
+	for _, code := range []rune{196, 214, 220, 228, 246, 252, 223} {
+		glyph, ok := readGlyph()
+		if !ok {
+			return font, nil
+		}
+		font.glyphs[code] = glyph
+	}
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		code, err := strconv.ParseInt(fields[0], 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid code tag '%s'", ErrInvalidFont, fields[0])
+		}
+		glyph, ok := readGlyph()
+		if !ok {
+			return nil, fmt.Errorf("%w: missing lines for character %d", ErrInvalidFont, code)
+		}
+		font.glyphs[rune(code)] = glyph
+	}
+
+	return font, scanner.Err()
+}
+
+func parseHeader(header string) (*Font, int, error) {
+	fields := strings.Fields(header)
+	if len(fields) < 6 || !strings.HasPrefix(fields[0], signature) || len(fields[0]) <= len(signature) {
+		return nil, 0, fmt.Errorf("%w: bad header", ErrInvalidFont)
+	}
+
+	numbers := make([]int, 0, len(fields)-1)
+	for _, field := range fields[1:] {
+		number, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: bad header", ErrInvalidFont)
+		}
+		numbers = append(numbers, number)
+	}
+
+	height, oldLayout, comments := numbers[0], numbers[3], numbers[4]
+	if height < 1 {
+		return nil, 0, fmt.Errorf("%w: bad height", ErrInvalidFont)
+	}
+
+	font := &Font{
+		Height:    height,
+		Hardblank: []rune(fields[0][len(signature):])[0],
+		glyphs:    make(map[rune][]string),
+	}
+
+	// The full layout, when present, replaces the old layout.
+	if len(numbers) >= 7 {
+		font.Layout = numbers[6] & (layoutSmushing | layoutKerning | 63)
+	} else if oldLayout == 0 {
+		font.Layout = layoutKerning
+	} else if oldLayout > 0 {
+		font.Layout = layoutSmushing | (oldLayout & 63)
+	}
+	return font, comments, nil
+}
+
+// trimEndmark removes the endmark, the last character of the line, and any
+// repetition of it.
+func trimEndmark(line string) string {
+	line = strings.TrimRight(line, "\r")
+	if line == "" {
+		return line
+	}
+	runes := []rune(line)
+	endmark := runes[len(runes)-1]
+	return strings.TrimRight(line, string(endmark))
+}
+
+func (f *Font) glyph(char rune) ([]string, bool) {
+	glyph, ok := f.glyphs[char]
+	return glyph, ok
+}
@@ -0,0 +1,77 @@
+package banner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeFont builds a font of height 2 where every required character,
+// Deutsch ones included, is a blank, with the given glyphs replacing some of them.
+func newFakeFont(header string, glyphs map[rune][2]string, extra string) string {
+	lines := []string{header, "a comment"}
+	for code := rune(32); code <= 126; code++ {
+		glyph, ok := glyphs[code]
+		if !ok {
+			glyph = [2]string{"", ""}
+		}
+		lines = append(lines, glyph[0]+"@", glyph[1]+"@@")
+	}
+	for i := 0; i < 7; i++ {
+		lines = append(lines, "@", "@@")
+	}
+	return strings.Join(lines, "\n") + extra
+}
+
+func TestParseFont(t *testing.T) {
+	t.Run("when the font is valid, should read its glyphs and layout", func(t *testing.T) {
+		source := newFakeFont("flf2a$ 2 2 4 -1 1 0 145", map[rune][2]string{'A': {"/\\", "||"}}, "\n0x263A smiley\n:)@\n(:@@\n")
+
+		font, err := ParseFont(strings.NewReader(source))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, font.Height)
+		assert.Equal(t, '$', font.Hardblank)
+		assert.Equal(t, layoutSmushing|ruleEqual|ruleBigX, font.Layout)
+		assert.Equal(t, []string{"/\\", "||"}, font.glyphs['A'])
+		assert.Equal(t, []string{":)", "(:"}, font.glyphs['☺'])
+	})
+
+	t.Run("when the font has only the old layout, should convert it", func(t *testing.T) {
+		font, err := ParseFont(strings.NewReader(newFakeFont("flf2a$ 2 2 4 0 1", nil, "")))
+
+		assert.NoError(t, err)
+		assert.Equal(t, layoutKerning, font.Layout)
+	})
+
+	t.Run("when the header is invalid, should return an error", func(t *testing.T) {
+		_, err := ParseFont(strings.NewReader("flf2 2 2 4 0 1\n"))
+
+		assert.ErrorIs(t, err, ErrInvalidFont)
+	})
+
+	t.Run("when characters are missing, should return an error", func(t *testing.T) {
+		_, err := ParseFont(strings.NewReader("flf2a$ 2 2 4 0 0\n @\n @@\n"))
+
+		assert.ErrorIs(t, err, ErrInvalidFont)
+		assert.ErrorContains(t, err, "missing character 33")
+	})
+}
+
+func TestLoad(t *testing.T) {
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			font, err := Load(name)
+
+			assert.NoError(t, err)
+			assert.Len(t, font.Render("Hello", LayoutDefault), font.Height)
+		})
+	}
+
+	t.Run("unknown font, should return an error", func(t *testing.T) {
+		_, err := Load("comic-sans")
+
+		assert.ErrorIs(t, err, ErrUnknownFont)
+	})
+}
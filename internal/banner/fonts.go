@@ -0,0 +1,74 @@
+package banner
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sketch/internal/errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	DefaultFont = "mini"
+)
+
+var (
+	ErrUnknownFont = errors.Error("unknown banner font")
+
+	//go:embed fonts/*.flf
+	embedded embed.FS
+
+	loadFonts sync.Once
+	fonts     map[string]*Font
+	fontsErr  error
+)
+
+// Load returns one of the fonts embedded in the binary by its name.
+func Load(name string) (*Font, error) {
+	loadFonts.Do(func() {
+		fonts, fontsErr = parseEmbedded()
+	})
+	if fontsErr != nil {
+		return nil, fontsErr
+	}
+
+	if name == "" {
+		name = DefaultFont
+	}
+	font, ok := fonts[name]
+	if !ok {
+		return nil, fmt.Errorf("%w '%s', use one of: %s", ErrUnknownFont, name, strings.Join(Names(), ", "))
+	}
+	return font, nil
+}
+
+// Names lists the embedded fonts.
+func Names() []string {
+	entries, _ := embedded.ReadDir("fonts")
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".flf"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseEmbedded() (map[string]*Font, error) {
+	parsed := make(map[string]*Font)
+	for _, name := range Names() {
+		file, err := embedded.Open(path.Join("fonts", name+".flf"))
+		if err != nil {
+			return nil, err
+		}
+
+		font, err := ParseFont(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("font '%s': %w", name, err)
+		}
+		parsed[name] = font
+	}
+	return parsed, nil
+}
@@ -0,0 +1,261 @@
+package banner
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	LayoutDefault  Layout = ""
+	LayoutFull     Layout = "full"
+	LayoutKerning  Layout = "kerning"
+	LayoutSmushing Layout = "smushing"
+)
+
+type (
+	// Layout is how the characters are put side by side: full width keeps
+	// every column of them, kerning moves them until they touch and smushing
+	// moves them one column further, merging the touching characters.
+	Layout string
+
+	renderer struct {
+		font   *Font
+		layout int
+		lines  [][]rune
+	}
+)
+
+func (l Layout) IsValid() bool {
+	switch l {
+	case LayoutDefault, LayoutFull, LayoutKerning, LayoutSmushing:
+		return true
+	}
+	return false
+}
+
+// Render writes the text with the font, returning one string per line.
+// Characters missing in the font are skipped and hardblanks become spaces.
+func (f *Font) Render(text string, layout Layout) []string {
+	r := renderer{font: f, layout: f.layoutFor(layout), lines: make([][]rune, f.Height)}
+	for _, char := range text {
+		glyph, ok := f.glyph(char)
+		if !ok {
+			continue
+		}
+		r.add(glyph)
+	}
+
+	result := make([]string, len(r.lines))
+	for i, line := range r.lines {
+		result[i] = strings.TrimRight(strings.ReplaceAll(string(line), string(f.Hardblank), " "), " ")
+	}
+	return result
+}
+
+// Width returns the most columns the text takes once rendered, its
+// characters side by side at full width, without rendering it.
+func (f *Font) Width(text string) int {
+	width := 0
+	for _, char := range text {
+		glyph, ok := f.glyph(char)
+		if !ok {
+			continue
+		}
+
+		widest := 0
+		for _, line := range glyph {
+			if length := utf8.RuneCountInString(line); length > widest {
+				widest = length
+			}
+		}
+		width += widest
+	}
+	return width
+}
+
+func (f *Font) layoutFor(layout Layout) int {
+	rules := f.Layout & 63
+	switch layout {
+	case LayoutFull:
+		return 0
+	case LayoutKerning:
+		return layoutKerning
+	case LayoutSmushing:
+		return layoutSmushing | rules
+	}
+	return f.Layout
+}
+
+func (r *renderer) add(glyph []string) {
+	runes := make([][]rune, len(glyph))
+	width := 0
+	for i, line := range glyph {
+		runes[i] = []rune(line)
+		if len(runes[i]) > width {
+			width = len(runes[i])
+		}
+	}
+
+	overlap := r.overlap(runes, width)
+	for row := range r.lines {
+		line := r.lines[row]
+		var part []rune
+		if row < len(runes) {
+			part = runes[row]
+		}
+		for len(part) < width {
+			part = append(part, ' ')
+		}
+
+		for k := 0; k < overlap; k++ {
+			column := len(line) - overlap + k
+			if column < 0 {
+				continue
+			}
+			line[column] = r.smush(line[column], part[k])
+		}
+		r.lines[row] = append(line, part[overlap:]...)
+	}
+}
+
+// overlap returns how many columns the glyph can move over the current
+// lines, following the figlet algorithm: the gap between the last visible
+// character of each line and the first of the glyph, plus one when smushing
+// the characters that would touch is allowed.
+func (r *renderer) overlap(glyph [][]rune, width int) int {
+	if r.layout&(layoutKerning|layoutSmushing) == 0 || len(r.lines[0]) == 0 {
+		return 0
+	}
+
+	amount := width
+	for row, line := range r.lines {
+		var part []rune
+		if row < len(glyph) {
+			part = glyph[row]
+		}
+
+		charStart := 0
+		for charStart < len(part) && part[charStart] == ' ' {
+			charStart++
+		}
+		lineEnd := len(line) - 1
+		for lineEnd > 0 && line[lineEnd] == ' ' {
+			lineEnd--
+		}
+
+		current := charStart + len(line) - 1 - lineEnd
+		if left := line[lineEnd]; left == ' ' {
+			current++
+		} else if charStart < len(part) && r.canSmush(left, part[charStart]) {
+			current++
+		}
+
+		if current < amount {
+			amount = current
+		}
+	}
+
+	if amount > len(r.lines[0]) {
+		amount = len(r.lines[0])
+	}
+	return amount
+}
+
+func (r *renderer) canSmush(left, right rune) bool {
+	if r.layout&layoutSmushing == 0 {
+		return false
+	}
+	_, ok := r.smushRule(left, right)
+	return ok
+}
+
+// smush merges two characters sharing a column. Blanks always give way.
+func (r *renderer) smush(left, right rune) rune {
+	if left == ' ' {
+		return right
+	}
+	if right == ' ' {
+		return left
+	}
+	if merged, ok := r.smushRule(left, right); ok {
+		return merged
+	}
+	return right
+}
+
+func (r *renderer) smushRule(left, right rune) (rune, bool) {
+	hardblank := r.font.Hardblank
+	rules := r.layout & 63
+
+	// Universal smushing: the right character wins, except over hardblanks.
+	if rules == 0 {
+		if left == hardblank {
+			return right, true
+		}
+		if right == hardblank {
+			return left, true
+		}
+		return right, true
+	}
+
+	if left == hardblank || right == hardblank {
+		if left == right && rules&ruleHardblank != 0 {
+			return left, true
+		}
+		return 0, false
+	}
+
+	if rules&ruleEqual != 0 && left == right {
+		return left, true
+	}
+
+	if rules&ruleUnderscore != 0 {
+		const borders = "|/\\[]{}()<>"
+		if left == '_' && strings.ContainsRune(borders, right) {
+			return right, true
+		}
+		if right == '_' && strings.ContainsRune(borders, left) {
+			return left, true
+		}
+	}
+
+	if rules&ruleHierarchy != 0 {
+		classes := []string{"|", "/\\", "[]", "{}", "()", "<>"}
+		leftClass, rightClass := -1, -1
+		for i, class := range classes {
+			if strings.ContainsRune(class, left) {
+				leftClass = i
+			}
+			if strings.ContainsRune(class, right) {
+				rightClass = i
+			}
+		}
+		if leftClass >= 0 && rightClass >= 0 && leftClass != rightClass {
+			if leftClass > rightClass {
+				return left, true
+			}
+			return right, true
+		}
+	}
+
+	pair := string([]rune{left, right})
+	if rules&ruleOppositePair != 0 {
+		switch pair {
+		case "[]", "][", "{}", "}{", "()", ")(":
+			return '|', true
+		}
+	}
+
+	if rules&ruleBigX != 0 {
+		switch pair {
+		case "/\\":
+			return '|', true
+		case "\\/":
+			return 'Y', true
+		case "><":
+			return 'X', true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,93 @@
+package banner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFont_Render(t *testing.T) {
+	source := newFakeFont("flf2a$ 2 2 4 -1 1 0 159", map[rune][2]string{
+		'/':  {"  /", " / "},
+		'\\': {"\\  ", " \\ "},
+		'|':  {"| ", "| "},
+		'_':  {"  ", "__"},
+		'I':  {"I$", "I$"},
+	}, "")
+	font, err := ParseFont(strings.NewReader(source))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		text     string
+		layout   Layout
+		expected []string
+	}{
+		{
+			name:     "full width, should keep every column",
+			text:     "/\\",
+			layout:   LayoutFull,
+			expected: []string{"  /\\", " /  \\"},
+		},
+		{
+			name:     "kerning, should move characters until they touch",
+			text:     "/\\",
+			layout:   LayoutKerning,
+			expected: []string{"  /\\", " /  \\"},
+		},
+		{
+			name:     "smushing with the big x rule, should merge slashes",
+			text:     "/\\",
+			layout:   LayoutSmushing,
+			expected: []string{"  |", " / \\"},
+		},
+		{
+			name:     "smushing with the underscore rule, should keep the border",
+			text:     "_|",
+			layout:   LayoutSmushing,
+			expected: []string{" |", "_|"},
+		},
+		{
+			name:     "hardblanks, should block kerning and render as spaces",
+			text:     "II",
+			layout:   LayoutKerning,
+			expected: []string{"I I", "I I"},
+		},
+		{
+			name:     "characters missing in the font, should be skipped",
+			text:     "/é\\",
+			layout:   LayoutFull,
+			expected: []string{"  /\\", " /  \\"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, font.Render(tc.text, tc.layout))
+		})
+	}
+}
+
+func TestFont_Width(t *testing.T) {
+	source := newFakeFont("flf2a$ 2 2 4 -1 1 0 159", map[rune][2]string{
+		'/':  {"  /", " / "},
+		'\\': {"\\  ", " \\ "},
+		'|':  {"| ", "| "},
+	}, "")
+	font, err := ParseFont(strings.NewReader(source))
+	assert.NoError(t, err)
+
+	t.Run("when the text is rendered, should be at least as wide", func(t *testing.T) {
+		for _, layout := range []Layout{LayoutFull, LayoutKerning, LayoutSmushing} {
+			rendered := font.Render("/\\|", layout)
+			for _, line := range rendered {
+				assert.LessOrEqual(t, len(line), font.Width("/\\|"))
+			}
+		}
+	})
+
+	t.Run("when the font misses characters, should skip them", func(t *testing.T) {
+		assert.Equal(t, 3, font.Width("/?"))
+	})
+}
@@ -0,0 +1,34 @@
+package canvas
+
+import (
+	"sketch/internal/errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrForbidden       = errors.Error("only the owner of the canvas and the keys it is shared with may change it")
+	ErrNotOwner        = errors.Error("only the owner of the canvas may share it")
+	ErrInvalidShareKey = errors.Error("key_id is required")
+	ErrShareNotFound   = errors.Error("the canvas is not shared with the key")
+)
+
+type (
+	// Share lets a key of another owner change a canvas.
+	Share struct {
+		DrawingID string    `json:"drawing_id" db:"drawing_id"`
+		KeyID     string    `json:"key_id" db:"key_id"`
+		CreatedAt time.Time `json:"created_at" db:"created_at"`
+	}
+
+	ShareRequest struct {
+		KeyID string `json:"key_id"`
+	}
+)
+
+func (r ShareRequest) Validate() error {
+	if strings.TrimSpace(r.KeyID) == "" {
+		return ErrInvalidShareKey
+	}
+	return nil
+}
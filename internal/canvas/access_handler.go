@@ -0,0 +1,149 @@
+package canvas
+
+import (
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"sketch/internal/auth"
+	"sketch/internal/routing"
+	"sketch/internal/workspace"
+)
+
+type AccessHandler struct {
+	service AccessService
+	links   LinkService
+}
+
+func NewAccessHandler(service AccessService, links LinkService) *AccessHandler {
+	return &AccessHandler{
+		service: service,
+		links:   links,
+	}
+}
+
+// Create wraps the handlers creating canvases, so that only those allowed get
+// to them.
+func (c *AccessHandler) Create(next routing.Handle) routing.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+		if err := accessError(w, c.service.CanCreate(r.Context())); err != nil {
+			return err
+		}
+		return next(w, r, params)
+	}
+}
+
+// Read wraps the handlers showing the canvas of the id param, or of the id
+// query param for the page, so that only those allowed, or with a share
+// token, get to them. The page without a canvas has nothing to check.
+func (c *AccessHandler) Read(next routing.Handle) routing.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+		id := params.ByName("id")
+		if id == "" {
+			id = r.URL.Query().Get("id")
+		}
+
+		if id == "" {
+			return next(w, r, params)
+		}
+
+		r, err := openLink(r, c.links, id)
+		if err := accessError(w, err); err != nil {
+			return err
+		}
+
+		if err := accessError(w, c.service.CanRead(r.Context(), id)); err != nil {
+			return err
+		}
+		return next(w, r, params)
+	}
+}
+
+// Modify wraps the handlers changing the canvas of the id param, so that only
+// those allowed, or with an edit share token, get to them.
+func (c *AccessHandler) Modify(next routing.Handle) routing.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+		id := params.ByName("id")
+		r, err := openLink(r, c.links, id)
+		if err := accessError(w, err); err != nil {
+			return err
+		}
+
+		if err := accessError(w, c.service.CanModify(r.Context(), id)); err != nil {
+			return err
+		}
+		return next(w, r, params)
+	}
+}
+
+// Delete wraps the handlers deleting the canvas of the id param, so that only
+// those allowed get to them. Share links are not opened, as none of them
+// allows it.
+func (c *AccessHandler) Delete(next routing.Handle) routing.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+		if err := accessError(w, c.service.CanDelete(r.Context(), params.ByName("id"))); err != nil {
+			return err
+		}
+		return next(w, r, params)
+	}
+}
+
+// Share lets a key of another owner change the canvas.
+func (c *AccessHandler) Share(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	request, err := routing.FromJSON[ShareRequest](r)
+	if err != nil {
+		return fmt.Errorf("failed to get json body: %w", err)
+	}
+
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	share, err := c.service.Share(r.Context(), params.ByName("id"), request)
+	if err := accessError(w, err); err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, share)
+}
+
+func (c *AccessHandler) GetShares(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	shares, err := c.service.GetShares(r.Context(), params.ByName("id"))
+	if err := accessError(w, err); err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, shares)
+}
+
+func (c *AccessHandler) Unshare(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	err := c.service.Unshare(r.Context(), params.ByName("id"), params.ByName("key"))
+
+	if errors.Is(err, ErrShareNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err := accessError(w, err); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// accessError writes the response of the errors telling the request is not
+// allowed, and returns the error.
+func accessError(w http.ResponseWriter, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrNotFound):
+		return routing.NotFound(w, err)
+	case errors.Is(err, auth.ErrKeyRequired), errors.Is(err, ErrInvalidToken), errors.Is(err, ErrTokenExpired):
+		return routing.Unauthorized(w, err)
+	case errors.Is(err, ErrForbidden), errors.Is(err, ErrNotOwner), errors.Is(err, ErrReadOnlyLink),
+		errors.Is(err, workspace.ErrForbidden):
+		return routing.Forbidden(w, err)
+	}
+	return err
+}
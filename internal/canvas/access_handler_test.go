@@ -0,0 +1,274 @@
+package canvas_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sketch/internal/auth"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessHandler_Modify(t *testing.T) {
+	next := func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) error {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	params := httprouter.Params{{Key: "id", Value: "123"}}
+
+	t.Run("when the request may change the canvas, should handle it", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockAccessService(ctrl)
+		handler := canvas.NewAccessHandler(serviceMock, nil)
+		req := httptest.NewRequest(http.MethodPost, "/123", nil)
+
+		serviceMock.EXPECT().CanModify(gomock.Any(), "123").Return(nil)
+
+		err := handler.Modify(next)(w, req, params)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("when the key may not change the canvas, should return forbidden", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockAccessService(ctrl)
+		handler := canvas.NewAccessHandler(serviceMock, nil)
+		req := httptest.NewRequest(http.MethodDelete, "/123", nil)
+
+		serviceMock.EXPECT().CanModify(gomock.Any(), "123").Return(canvas.ErrForbidden)
+
+		err := handler.Modify(next)(w, req, params)
+
+		assert.ErrorIs(t, err, canvas.ErrForbidden)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("when the request has no key, should return unauthorized", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockAccessService(ctrl)
+		handler := canvas.NewAccessHandler(serviceMock, nil)
+		req := httptest.NewRequest(http.MethodDelete, "/123", nil)
+
+		serviceMock.EXPECT().CanModify(gomock.Any(), "123").Return(auth.ErrKeyRequired)
+
+		err := handler.Modify(next)(w, req, params)
+
+		assert.ErrorIs(t, err, auth.ErrKeyRequired)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("when the canvas does not exist, should return not found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockAccessService(ctrl)
+		handler := canvas.NewAccessHandler(serviceMock, nil)
+		req := httptest.NewRequest(http.MethodPost, "/123", nil)
+
+		serviceMock.EXPECT().CanModify(gomock.Any(), "123").Return(canvas.ErrNotFound)
+
+		err := handler.Modify(next)(w, req, params)
+
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestAccessHandler_Read(t *testing.T) {
+	next := func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) error {
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+	setup := func(t *testing.T) (*canvas.AccessHandler, *mock_canvas.MockAccessService, *mock_canvas.MockLinkService) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockAccessService(ctrl)
+		linksMock := mock_canvas.NewMockLinkService(ctrl)
+		return canvas.NewAccessHandler(serviceMock, linksMock), serviceMock, linksMock
+	}
+	params := httprouter.Params{{Key: "id", Value: "123"}}
+
+	t.Run("when the request may see the canvas, should handle it", func(t *testing.T) {
+		handler, serviceMock, _ := setup(t)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/123", nil)
+
+		serviceMock.EXPECT().CanRead(gomock.Any(), "123").Return(nil)
+
+		assert.NoError(t, handler.Read(next)(w, req, params))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("when the key may not see the canvas, should return forbidden", func(t *testing.T) {
+		handler, serviceMock, _ := setup(t)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/123", nil)
+
+		serviceMock.EXPECT().CanRead(gomock.Any(), "123").Return(canvas.ErrForbidden)
+
+		assert.ErrorIs(t, handler.Read(next)(w, req, params), canvas.ErrForbidden)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("when the token opens the canvas, should check it with the context of the token", func(t *testing.T) {
+		handler, serviceMock, linksMock := setup(t)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/123?token=abc", nil)
+		opened := canvas.WithLink(context.Background(), canvas.Link{DrawingID: "123", Permission: canvas.PermissionRead})
+
+		linksMock.EXPECT().Open(gomock.Any(), "123", "abc").Return(opened, nil)
+		serviceMock.EXPECT().CanRead(opened, "123").Return(nil)
+
+		assert.NoError(t, handler.Read(next)(w, req, params))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("when the token is in the header, should use it", func(t *testing.T) {
+		handler, serviceMock, linksMock := setup(t)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/123", nil)
+		req.Header.Set(canvas.HeaderToken, "abc")
+
+		linksMock.EXPECT().Open(gomock.Any(), "123", "abc").Return(context.Background(), nil)
+		serviceMock.EXPECT().CanRead(gomock.Any(), "123").Return(nil)
+
+		assert.NoError(t, handler.Read(next)(w, req, params))
+	})
+
+	t.Run("when the token is expired, should return a 401 without checking the canvas", func(t *testing.T) {
+		handler, _, linksMock := setup(t)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/123?token=abc", nil)
+
+		linksMock.EXPECT().Open(gomock.Any(), "123", "abc").Return(nil, canvas.ErrTokenExpired)
+
+		assert.ErrorIs(t, handler.Read(next)(w, req, params), canvas.ErrTokenExpired)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("when the page has the id in the query, should check that canvas", func(t *testing.T) {
+		handler, serviceMock, _ := setup(t)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/?id=123", nil)
+
+		serviceMock.EXPECT().CanRead(gomock.Any(), "123").Return(nil)
+
+		assert.NoError(t, handler.Read(next)(w, req, nil))
+	})
+
+	t.Run("when the page has no canvas, should handle it", func(t *testing.T) {
+		handler, _, _ := setup(t)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		assert.NoError(t, handler.Read(next)(w, req, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestAccessHandler_ReadWithLinks(t *testing.T) {
+	next := func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) error {
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+	setup := func(t *testing.T) (*canvas.AccessHandler, *mock_canvas.MockRepository, *mock_canvas.MockAccessRepository, *mock_canvas.MockLinkRepository) {
+		links, repositoryMock, linksMock := newLinkService(t)
+		accessMock := mock_canvas.NewMockAccessRepository(gomock.NewController(t))
+		handler := canvas.NewAccessHandler(canvas.NewAccessService(repositoryMock, accessMock), links)
+		return handler, repositoryMock, accessMock, linksMock
+	}
+	params := httprouter.Params{{Key: "id", Value: "123"}}
+	link := canvas.Link{ID: "link", DrawingID: "123", Permission: canvas.PermissionRead, ExpiresAt: time.Now().Add(time.Hour)}
+	token, err := canvas.SignLink([]byte(linkSecret), link)
+	assert.NoError(t, err)
+	other := auth.Principal{KeyID: "2", OwnerID: "other"}
+
+	t.Run("when someone else reads the canvas without a token, should forbid it", func(t *testing.T) {
+		handler, repositoryMock, accessMock, _ := setup(t)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/123", nil)
+		req = req.WithContext(auth.WithPrincipal(req.Context(), other))
+
+		repositoryMock.EXPECT().GetOwner(gomock.Any(), "123").Return("owner", nil)
+		accessMock.EXPECT().IsShared(gomock.Any(), "123", "2").Return(false, nil)
+
+		assert.ErrorIs(t, handler.Read(next)(w, req, params), canvas.ErrForbidden)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("when someone else reads the canvas with a read link, should allow it", func(t *testing.T) {
+		handler, repositoryMock, _, linksMock := setup(t)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/123?token="+token, nil)
+		req = req.WithContext(auth.WithPrincipal(req.Context(), other))
+
+		linksMock.EXPECT().GetLink(gomock.Any(), "link").Return(link, nil)
+		repositoryMock.EXPECT().GetOwner(gomock.Any(), "123").Return("owner", nil)
+
+		assert.NoError(t, handler.Read(next)(w, req, params))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("when someone else reads the canvas with a revoked link, should reject it", func(t *testing.T) {
+		handler, _, _, linksMock := setup(t)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/123?token="+token, nil)
+		req = req.WithContext(auth.WithPrincipal(req.Context(), other))
+		revoked := link
+		revokedAt := time.Now()
+		revoked.RevokedAt = &revokedAt
+
+		linksMock.EXPECT().GetLink(gomock.Any(), "link").Return(revoked, nil)
+
+		assert.ErrorIs(t, handler.Read(next)(w, req, params), canvas.ErrInvalidToken)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestAccessHandler_Delete(t *testing.T) {
+	next := func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) error {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	setup := func(t *testing.T) (*canvas.AccessHandler, *mock_canvas.MockRepository) {
+		links, repositoryMock, _ := newLinkService(t)
+		accessMock := mock_canvas.NewMockAccessRepository(gomock.NewController(t))
+		return canvas.NewAccessHandler(canvas.NewAccessService(repositoryMock, accessMock), links), repositoryMock
+	}
+	params := httprouter.Params{{Key: "id", Value: "123"}}
+	link := canvas.Link{ID: "link", DrawingID: "123", Permission: canvas.PermissionEdit, ExpiresAt: time.Now().Add(time.Hour)}
+	token, err := canvas.SignLink([]byte(linkSecret), link)
+	assert.NoError(t, err)
+
+	t.Run("when someone else deletes the canvas with an edit link, should forbid it", func(t *testing.T) {
+		handler, repositoryMock := setup(t)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/123?token="+token, nil)
+		req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{KeyID: "2", OwnerID: "other"}))
+
+		repositoryMock.EXPECT().GetOwner(gomock.Any(), "123").Return("owner", nil)
+
+		assert.ErrorIs(t, handler.Delete(next)(w, req, params), canvas.ErrNotOwner)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("when the owner deletes the canvas, should handle it", func(t *testing.T) {
+		handler, repositoryMock := setup(t)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/123", nil)
+		req = req.WithContext(auth.WithPrincipal(req.Context(), auth.Principal{KeyID: "1", OwnerID: "owner"}))
+
+		repositoryMock.EXPECT().GetOwner(gomock.Any(), "123").Return("owner", nil)
+
+		assert.NoError(t, handler.Delete(next)(w, req, params))
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
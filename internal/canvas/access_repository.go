@@ -0,0 +1,68 @@
+package canvas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	AccessRepository interface {
+		SaveShare(ctx context.Context, share Share) error
+		GetShares(ctx context.Context, drawingID string) ([]Share, error)
+		IsShared(ctx context.Context, drawingID string, keyID string) (bool, error)
+		DeleteShare(ctx context.Context, drawingID string, keyID string) error
+	}
+
+	accessRepository struct {
+		db *sqlx.DB
+	}
+)
+
+func NewAccessRepository(db *sqlx.DB) AccessRepository {
+	return &accessRepository{
+		db: db,
+	}
+}
+
+// SaveShare keeps the first share when the canvas is shared with the key again.
+func (r *accessRepository) SaveShare(ctx context.Context, share Share) error {
+	const query = "insert into canvas_shares (drawing_id, key_id, created_at) values (:drawing_id, :key_id, :created_at) " +
+		"on conflict (drawing_id, key_id) do nothing"
+	if _, err := r.db.NamedExecContext(ctx, query, share); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+func (r *accessRepository) GetShares(ctx context.Context, drawingID string) ([]Share, error) {
+	const query = "select drawing_id, key_id, created_at from canvas_shares where drawing_id = $1 order by created_at"
+	shares := make([]Share, 0)
+	if err := r.db.SelectContext(ctx, &shares, query, drawingID); err != nil {
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+	return shares, nil
+}
+
+func (r *accessRepository) IsShared(ctx context.Context, drawingID string, keyID string) (bool, error) {
+	const query = "select exists (select 1 from canvas_shares where drawing_id = $1 and key_id = $2)"
+	var shared bool
+	if err := r.db.GetContext(ctx, &shared, query, drawingID, keyID); err != nil {
+		return false, fmt.Errorf("database err: %w", err)
+	}
+	return shared, nil
+}
+
+func (r *accessRepository) DeleteShare(ctx context.Context, drawingID string, keyID string) error {
+	const query = "delete from canvas_shares where drawing_id = $1 and key_id = $2"
+	result, err := r.db.ExecContext(ctx, query, drawingID, keyID)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrShareNotFound
+	}
+	return nil
+}
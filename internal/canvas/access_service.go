@@ -0,0 +1,202 @@
+package canvas
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"sketch/internal/auth"
+	"sketch/internal/workspace"
+	"strings"
+	"time"
+)
+
+type (
+	// AccessService decides who may read and change a canvas: in a workspace,
+	// its viewers may read it and its editors and admins change it, elsewhere
+	// its owner and the keys it is shared with. Canvases without an owner stay
+	// open to anyone, and share links open the others.
+	AccessService interface {
+		CanCreate(ctx context.Context) error
+		CanRead(ctx context.Context, id string) error
+		CanModify(ctx context.Context, id string) error
+		CanDelete(ctx context.Context, id string) error
+		Share(ctx context.Context, id string, request ShareRequest) (*Share, error)
+		GetShares(ctx context.Context, id string) ([]Share, error)
+		Unshare(ctx context.Context, id string, keyID string) error
+	}
+
+	accessService struct {
+		repository Repository
+		access     AccessRepository
+	}
+)
+
+func NewAccessService(repository Repository, access AccessRepository) AccessService {
+	return &accessService{
+		repository: repository,
+		access:     access,
+	}
+}
+
+// CanCreate tells whether the request may create canvases, in its workspace
+// if it has one.
+func (s accessService) CanCreate(ctx context.Context) error {
+	if _, ok := auth.FromContext(ctx); !ok {
+		return auth.ErrKeyRequired
+	}
+	return workspace.Check(ctx, workspace.RoleEditor)
+}
+
+// CanRead tells whether the request may see the canvas, which any share link
+// to it allows.
+func (s accessService) CanRead(ctx context.Context, id string) error {
+	ownerID, err := s.repository.GetOwner(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s': %w", id, err)
+	}
+
+	if link, ok := LinkFromContext(ctx); ok && link.DrawingID == id {
+		return nil
+	}
+
+	if workspace.ID(ctx) != "" {
+		return workspace.Check(ctx, workspace.RoleViewer)
+	}
+
+	return s.checkShared(ctx, id, ownerID)
+}
+
+func (s accessService) CanModify(ctx context.Context, id string) error {
+	ownerID, err := s.repository.GetOwner(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s': %w", id, err)
+	}
+
+	if link, ok := LinkFromContext(ctx); ok && link.DrawingID == id {
+		if link.Permission != PermissionEdit {
+			return ErrReadOnlyLink
+		}
+		return nil
+	}
+
+	if workspace.ID(ctx) != "" {
+		return workspace.Check(ctx, workspace.RoleEditor)
+	}
+
+	return s.checkShared(ctx, id, ownerID)
+}
+
+// CanDelete tells whether the request may delete the canvas: in a workspace
+// its editors may, elsewhere only its owner. Share links and shared keys
+// never do.
+func (s accessService) CanDelete(ctx context.Context, id string) error {
+	ownerID, err := s.repository.GetOwner(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s': %w", id, err)
+	}
+
+	if workspace.ID(ctx) != "" {
+		return workspace.Check(ctx, workspace.RoleEditor)
+	}
+
+	if ownerID == "" {
+		return nil
+	}
+
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return auth.ErrKeyRequired
+	}
+
+	if principal.OwnerID != ownerID {
+		return ErrNotOwner
+	}
+	return nil
+}
+
+// checkShared fails unless the canvas has no owner, or the request comes from
+// its owner or a key it is shared with.
+func (s accessService) checkShared(ctx context.Context, id string, ownerID string) error {
+	if ownerID == "" {
+		return nil
+	}
+
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return auth.ErrKeyRequired
+	}
+
+	if principal.OwnerID == ownerID {
+		return nil
+	}
+
+	shared, err := s.access.IsShared(ctx, id, principal.KeyID)
+	if err != nil {
+		return fmt.Errorf("failed to get the shares of '%s': %w", id, err)
+	}
+
+	if !shared {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func (s accessService) Share(ctx context.Context, id string, request ShareRequest) (*Share, error) {
+	if err := s.checkOwner(ctx, id); err != nil {
+		return nil, err
+	}
+
+	share := Share{DrawingID: id, KeyID: strings.TrimSpace(request.KeyID), CreatedAt: time.Now().UTC()}
+	if err := s.access.SaveShare(ctx, share); err != nil {
+		return nil, fmt.Errorf("failed to share '%s': %w", id, err)
+	}
+	return &share, nil
+}
+
+func (s accessService) GetShares(ctx context.Context, id string) ([]Share, error) {
+	if err := s.checkOwner(ctx, id); err != nil {
+		return nil, err
+	}
+
+	shares, err := s.access.GetShares(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the shares of '%s': %w", id, err)
+	}
+	return shares, nil
+}
+
+func (s accessService) Unshare(ctx context.Context, id string, keyID string) error {
+	if err := s.checkOwner(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.access.DeleteShare(ctx, id, keyID); err != nil {
+		return fmt.Errorf("failed to unshare '%s': %w", id, err)
+	}
+	return nil
+}
+
+// checkOwner fails unless the request comes from the owner of the canvas.
+func (s accessService) checkOwner(ctx context.Context, id string) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return auth.ErrKeyRequired
+	}
+
+	ownerID, err := s.repository.GetOwner(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s': %w", id, err)
+	}
+
+	if ownerID == "" || ownerID != principal.OwnerID {
+		return ErrNotOwner
+	}
+	return nil
+}
+
+// isDenied tells whether the error refuses the access, rather than failing to
+// check it.
+func isDenied(err error) bool {
+	return goerrors.Is(err, ErrNotFound) || goerrors.Is(err, ErrForbidden) || goerrors.Is(err, ErrNotOwner) ||
+		goerrors.Is(err, ErrReadOnlyLink) || goerrors.Is(err, auth.ErrKeyRequired) || goerrors.Is(err, workspace.ErrForbidden)
+}
@@ -0,0 +1,316 @@
+package canvas_test
+
+import (
+	"context"
+	"sketch/internal/auth"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"sketch/internal/workspace"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessService_CanModify(t *testing.T) {
+	setup := func(t *testing.T) (canvas.AccessService, *mock_canvas.MockRepository, *mock_canvas.MockAccessRepository) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		accessMock := mock_canvas.NewMockAccessRepository(ctrl)
+		return canvas.NewAccessService(repositoryMock, accessMock), repositoryMock, accessMock
+	}
+	owner := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+	other := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "2", OwnerID: "other"})
+
+	t.Run("when the key belongs to the owner, should allow it", func(t *testing.T) {
+		service, repositoryMock, _ := setup(t)
+
+		repositoryMock.EXPECT().GetOwner(owner, "123").Return("owner", nil)
+
+		assert.NoError(t, service.CanModify(owner, "123"))
+	})
+
+	t.Run("when the canvas is shared with the key, should allow it", func(t *testing.T) {
+		service, repositoryMock, accessMock := setup(t)
+
+		repositoryMock.EXPECT().GetOwner(other, "123").Return("owner", nil)
+		accessMock.EXPECT().IsShared(other, "123", "2").Return(true, nil)
+
+		assert.NoError(t, service.CanModify(other, "123"))
+	})
+
+	t.Run("when the canvas is not shared with the key, should forbid it", func(t *testing.T) {
+		service, repositoryMock, accessMock := setup(t)
+
+		repositoryMock.EXPECT().GetOwner(other, "123").Return("owner", nil)
+		accessMock.EXPECT().IsShared(other, "123", "2").Return(false, nil)
+
+		assert.ErrorIs(t, service.CanModify(other, "123"), canvas.ErrForbidden)
+	})
+
+	t.Run("when the request is anonymous, should require a key", func(t *testing.T) {
+		service, repositoryMock, _ := setup(t)
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("owner", nil)
+
+		assert.ErrorIs(t, service.CanModify(ctx, "123"), auth.ErrKeyRequired)
+	})
+
+	t.Run("when the canvas has no owner, should allow anyone", func(t *testing.T) {
+		service, repositoryMock, _ := setup(t)
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("", nil)
+
+		assert.NoError(t, service.CanModify(ctx, "123"))
+	})
+
+	t.Run("when the request is in a workspace, should let its editors change any canvas", func(t *testing.T) {
+		service, repositoryMock, _ := setup(t)
+		ctx := workspace.WithScope(other, workspace.Scope{WorkspaceID: "ws", Role: workspace.RoleEditor})
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("owner", nil)
+
+		assert.NoError(t, service.CanModify(ctx, "123"))
+	})
+
+	t.Run("when the request is in a workspace as a viewer, should forbid it", func(t *testing.T) {
+		service, repositoryMock, _ := setup(t)
+		ctx := workspace.WithScope(owner, workspace.Scope{WorkspaceID: "ws", Role: workspace.RoleViewer})
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("owner", nil)
+
+		assert.ErrorIs(t, service.CanModify(ctx, "123"), workspace.ErrForbidden)
+	})
+
+	t.Run("when the request has an edit link of the canvas, should allow it", func(t *testing.T) {
+		service, repositoryMock, _ := setup(t)
+		ctx := canvas.WithLink(context.Background(), canvas.Link{DrawingID: "123", Permission: canvas.PermissionEdit})
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("owner", nil)
+
+		assert.NoError(t, service.CanModify(ctx, "123"))
+	})
+
+	t.Run("when the request has a read link of the canvas, should forbid it", func(t *testing.T) {
+		service, repositoryMock, _ := setup(t)
+		ctx := canvas.WithLink(other, canvas.Link{DrawingID: "123", Permission: canvas.PermissionRead})
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("owner", nil)
+
+		assert.ErrorIs(t, service.CanModify(ctx, "123"), canvas.ErrReadOnlyLink)
+	})
+
+	t.Run("when the canvas does not exist, should return not found", func(t *testing.T) {
+		service, repositoryMock, _ := setup(t)
+
+		repositoryMock.EXPECT().GetOwner(owner, "123").Return("", canvas.ErrNotFound)
+
+		assert.ErrorIs(t, service.CanModify(owner, "123"), canvas.ErrNotFound)
+	})
+}
+
+func TestAccessService_CanDelete(t *testing.T) {
+	setup := func(t *testing.T) (canvas.AccessService, *mock_canvas.MockRepository) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		return canvas.NewAccessService(repositoryMock, mock_canvas.NewMockAccessRepository(ctrl)), repositoryMock
+	}
+	owner := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+	other := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "2", OwnerID: "other"})
+
+	t.Run("when the key belongs to the owner, should allow it", func(t *testing.T) {
+		service, repositoryMock := setup(t)
+
+		repositoryMock.EXPECT().GetOwner(owner, "123").Return("owner", nil)
+
+		assert.NoError(t, service.CanDelete(owner, "123"))
+	})
+
+	t.Run("when the key is not the owner, should forbid it even if the canvas is shared with it", func(t *testing.T) {
+		service, repositoryMock := setup(t)
+
+		repositoryMock.EXPECT().GetOwner(other, "123").Return("owner", nil)
+
+		assert.ErrorIs(t, service.CanDelete(other, "123"), canvas.ErrNotOwner)
+	})
+
+	t.Run("when the request has an edit link of the canvas, should still require a key", func(t *testing.T) {
+		service, repositoryMock := setup(t)
+		ctx := canvas.WithLink(context.Background(), canvas.Link{DrawingID: "123", Permission: canvas.PermissionEdit})
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("owner", nil)
+
+		assert.ErrorIs(t, service.CanDelete(ctx, "123"), auth.ErrKeyRequired)
+	})
+
+	t.Run("when the request is in a workspace, should let its editors delete any canvas", func(t *testing.T) {
+		service, repositoryMock := setup(t)
+		ctx := workspace.WithScope(other, workspace.Scope{WorkspaceID: "ws", Role: workspace.RoleEditor})
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("owner", nil)
+
+		assert.NoError(t, service.CanDelete(ctx, "123"))
+	})
+
+	t.Run("when the request is in a workspace as a viewer, should forbid it", func(t *testing.T) {
+		service, repositoryMock := setup(t)
+		ctx := workspace.WithScope(owner, workspace.Scope{WorkspaceID: "ws", Role: workspace.RoleViewer})
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("owner", nil)
+
+		assert.ErrorIs(t, service.CanDelete(ctx, "123"), workspace.ErrForbidden)
+	})
+
+	t.Run("when the canvas does not exist, should return not found", func(t *testing.T) {
+		service, repositoryMock := setup(t)
+
+		repositoryMock.EXPECT().GetOwner(owner, "123").Return("", canvas.ErrNotFound)
+
+		assert.ErrorIs(t, service.CanDelete(owner, "123"), canvas.ErrNotFound)
+	})
+}
+
+func TestAccessService_CanRead(t *testing.T) {
+	setup := func(t *testing.T) (canvas.AccessService, *mock_canvas.MockRepository, *mock_canvas.MockAccessRepository) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		accessMock := mock_canvas.NewMockAccessRepository(ctrl)
+		return canvas.NewAccessService(repositoryMock, accessMock), repositoryMock, accessMock
+	}
+	owner := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+	other := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "2", OwnerID: "other"})
+
+	t.Run("when the key belongs to the owner, should allow it", func(t *testing.T) {
+		service, repositoryMock, _ := setup(t)
+
+		repositoryMock.EXPECT().GetOwner(owner, "123").Return("owner", nil)
+
+		assert.NoError(t, service.CanRead(owner, "123"))
+	})
+
+	t.Run("when the canvas is shared with the key, should allow it", func(t *testing.T) {
+		service, repositoryMock, accessMock := setup(t)
+
+		repositoryMock.EXPECT().GetOwner(other, "123").Return("owner", nil)
+		accessMock.EXPECT().IsShared(other, "123", "2").Return(true, nil)
+
+		assert.NoError(t, service.CanRead(other, "123"))
+	})
+
+	t.Run("when the canvas is not shared with the key, should forbid it", func(t *testing.T) {
+		service, repositoryMock, accessMock := setup(t)
+
+		repositoryMock.EXPECT().GetOwner(other, "123").Return("owner", nil)
+		accessMock.EXPECT().IsShared(other, "123", "2").Return(false, nil)
+
+		assert.ErrorIs(t, service.CanRead(other, "123"), canvas.ErrForbidden)
+	})
+
+	t.Run("when the request is anonymous, should require a key", func(t *testing.T) {
+		service, repositoryMock, _ := setup(t)
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("owner", nil)
+
+		assert.ErrorIs(t, service.CanRead(ctx, "123"), auth.ErrKeyRequired)
+	})
+
+	t.Run("when the canvas has no owner, should allow anyone", func(t *testing.T) {
+		service, repositoryMock, _ := setup(t)
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("", nil)
+
+		assert.NoError(t, service.CanRead(ctx, "123"))
+	})
+
+	t.Run("when the request is in a workspace as a viewer, should allow it", func(t *testing.T) {
+		service, repositoryMock, _ := setup(t)
+		ctx := workspace.WithScope(other, workspace.Scope{WorkspaceID: "ws", Role: workspace.RoleViewer})
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("owner", nil)
+
+		assert.NoError(t, service.CanRead(ctx, "123"))
+	})
+
+	t.Run("when the request has a read link of the canvas, should allow it", func(t *testing.T) {
+		service, repositoryMock, _ := setup(t)
+		ctx := canvas.WithLink(context.Background(), canvas.Link{DrawingID: "123", Permission: canvas.PermissionRead})
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("owner", nil)
+
+		assert.NoError(t, service.CanRead(ctx, "123"))
+	})
+
+	t.Run("when the request has a link of another canvas, should forbid it", func(t *testing.T) {
+		service, repositoryMock, accessMock := setup(t)
+		ctx := canvas.WithLink(other, canvas.Link{DrawingID: "456", Permission: canvas.PermissionRead})
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("owner", nil)
+		accessMock.EXPECT().IsShared(ctx, "123", "2").Return(false, nil)
+
+		assert.ErrorIs(t, service.CanRead(ctx, "123"), canvas.ErrForbidden)
+	})
+
+	t.Run("when the canvas does not exist, should return not found", func(t *testing.T) {
+		service, repositoryMock, _ := setup(t)
+
+		repositoryMock.EXPECT().GetOwner(owner, "123").Return("", canvas.ErrNotFound)
+
+		assert.ErrorIs(t, service.CanRead(owner, "123"), canvas.ErrNotFound)
+	})
+}
+
+func TestAccessService_CanCreate(t *testing.T) {
+	service := canvas.NewAccessService(nil, nil)
+	principal := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+
+	t.Run("when the request has a key, should allow it", func(t *testing.T) {
+		assert.NoError(t, service.CanCreate(principal))
+	})
+
+	t.Run("when the request is anonymous, should require a key", func(t *testing.T) {
+		assert.ErrorIs(t, service.CanCreate(context.Background()), auth.ErrKeyRequired)
+	})
+
+	t.Run("when the request is in a workspace as a viewer, should forbid it", func(t *testing.T) {
+		ctx := workspace.WithScope(principal, workspace.Scope{WorkspaceID: "ws", Role: workspace.RoleViewer})
+
+		assert.ErrorIs(t, service.CanCreate(ctx), workspace.ErrForbidden)
+	})
+}
+
+func TestAccessService_Share(t *testing.T) {
+	t.Run("when the request is not from the owner, should not share the canvas", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		service := canvas.NewAccessService(repositoryMock, mock_canvas.NewMockAccessRepository(ctrl))
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "2", OwnerID: "other"})
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("owner", nil)
+
+		share, err := service.Share(ctx, "123", canvas.ShareRequest{KeyID: "2"})
+
+		assert.ErrorIs(t, err, canvas.ErrNotOwner)
+		assert.Nil(t, share)
+	})
+
+	t.Run("when the request is from the owner, should share the canvas with the key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		accessMock := mock_canvas.NewMockAccessRepository(ctrl)
+		service := canvas.NewAccessService(repositoryMock, accessMock)
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("owner", nil)
+		accessMock.EXPECT().SaveShare(ctx, gomock.Any()).Return(nil)
+
+		share, err := service.Share(ctx, "123", canvas.ShareRequest{KeyID: "2"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "123", share.DrawingID)
+		assert.Equal(t, "2", share.KeyID)
+	})
+}
@@ -0,0 +1,290 @@
+package canvas
+
+import (
+	"fmt"
+	"sketch/internal/errors"
+	"sketch/internal/text"
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	// maxAnalyzeArea caps the cells of an analyzed drawing, as peeling takes
+	// time quadratic in them.
+	maxAnalyzeArea = 20_000
+)
+
+var (
+	ErrAnalyzeTooLarge = errors.Error(fmt.Sprintf("the drawing to analyze must have at most %d cells", maxAnalyzeArea))
+)
+
+type (
+	analysis struct {
+		draw   Draw
+		peeled [][]bool
+	}
+
+	candidate struct {
+		x, y, width, height int
+		outline, fill       string
+		score               int
+		tight               bool
+	}
+)
+
+// Analyze finds the rectangles that make up a drawing, returning requests
+// that draw it back exactly. It works like peeling layers: the rectangle on
+// top is fully visible, so it is found first and its cells become free to
+// hold anything for the rectangles under it. The requests are returned in
+// drawing order, which is the reverse of the peeling order.
+func Analyze(drawing string) DrawRequests {
+	a := newAnalysis(ParseDraw(drawing))
+	peeled := make(DrawRequests, 0)
+
+	for {
+		corners := a.corners()
+		if len(corners) == 0 {
+			break
+		}
+
+		candidates := make([]candidate, 0, len(corners))
+		for _, corner := range corners {
+			candidates = append(candidates, a.bestCandidate(corner[0], corner[1]))
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].isBetter(candidates[j])
+		})
+
+		// Tight rectangles that do not overlap can be peeled in the same round,
+		// as the order between them does not change the drawing.
+		chosen := []candidate{candidates[0]}
+		for _, current := range candidates[1:] {
+			if !current.tight || current.overlapsAny(chosen) {
+				continue
+			}
+			chosen = append(chosen, current)
+		}
+
+		for _, current := range chosen {
+			a.peel(current)
+			peeled = append(peeled, current.request())
+		}
+	}
+
+	requests := make(DrawRequests, 0, len(peeled))
+	for i := len(peeled) - 1; i >= 0; i-- {
+		requests = append(requests, peeled[i])
+	}
+	return requests
+}
+
+func newAnalysis(draw Draw) *analysis {
+	peeled := make([][]bool, len(draw))
+	for row := range draw {
+		peeled[row] = make([]bool, len(draw[row]))
+	}
+	return &analysis{draw: draw, peeled: peeled}
+}
+
+func isBlank(value string) bool {
+	return value == "" || value == paddingChar
+}
+
+func (a *analysis) cell(x, y int) string {
+	if y >= len(a.draw) || x >= len(a.draw[y]) {
+		return ""
+	}
+	return a.draw[y][x]
+}
+
+// matches tells if a rectangle may write value at the cell. Peeled cells are
+// overwritten later, so any value fits them.
+func (a *analysis) matches(x, y int, value string) bool {
+	if y < len(a.peeled) && x < len(a.peeled[y]) && a.peeled[y][x] {
+		return true
+	}
+	return a.cell(x, y) == value
+}
+
+func (a *analysis) isVisible(x, y int) bool {
+	return !a.isPeeled(x, y) && !isBlank(a.cell(x, y))
+}
+
+// corners returns the visible cells that may be the top-left corner of a
+// rectangle: the same character does not continue on their left or above.
+// The first visible cell in reading order is always one of them.
+func (a *analysis) corners() [][2]int {
+	corners := make([][2]int, 0)
+	for y, row := range a.draw {
+		for x, value := range row {
+			if !a.isVisible(x, y) {
+				continue
+			}
+
+			continuesLeft := x > 0 && a.isVisible(x-1, y) && a.cell(x-1, y) == value
+			continuesAbove := y > 0 && a.isVisible(x, y-1) && a.cell(x, y-1) == value
+			if !continuesLeft && !continuesAbove {
+				corners = append(corners, [2]int{x, y})
+			}
+		}
+	}
+	return corners
+}
+
+// bestCandidate looks for the rectangle with top-left corner at (x, y) that
+// peels the most cells. A 1x1 rectangle always fits, so there is always one.
+func (a *analysis) bestCandidate(x, y int) candidate {
+	outline := a.cell(x, y)
+	best := candidate{x: x, y: y, width: 1, height: 1, fill: outline, outline: outline, score: 1}
+	best.tight = a.isTight(best)
+	if utf8.RuneCountInString(outline) != 1 || text.ASCIIChar(outline).Validate() != nil {
+		return best
+	}
+
+	maxWidth := 0
+	for a.matches(x+maxWidth, y, outline) {
+		maxWidth++
+	}
+	maxHeight := 0
+	for a.matches(x, y+maxHeight, outline) {
+		maxHeight++
+	}
+
+	// Bigger rectangles first: once a tight one fits, any rectangle whose area
+	// is not greater than its score can be skipped.
+	for width := maxWidth; width >= 1; width-- {
+		for height := maxHeight; height >= 1; height-- {
+			if best.tight && width*height <= best.score {
+				break
+			}
+			if current, ok := a.fit(x, y, width, height, outline); ok && current.isBetter(best) {
+				best = current
+			}
+		}
+	}
+	return best
+}
+
+func (a *analysis) isPeeled(x, y int) bool {
+	return y < len(a.peeled) && x < len(a.peeled[y]) && a.peeled[y][x]
+}
+
+// fit checks the border of the rectangle and chooses the fill: the single
+// character found inside it, or "none" when the inside is mixed.
+func (a *analysis) fit(x, y, width, height int, outline string) (candidate, bool) {
+	current := candidate{x: x, y: y, width: width, height: height, outline: outline, fill: outline}
+	for column := x; column < x+width; column++ {
+		if !a.matches(column, y+height-1, outline) || !a.matches(column, y, outline) {
+			return current, false
+		}
+	}
+	for row := y; row < y+height; row++ {
+		if !a.matches(x+width-1, row, outline) {
+			return current, false
+		}
+	}
+
+	if width > 2 && height > 2 {
+		current.fill = a.interiorFill(current)
+	}
+
+	for row := y; row < y+height; row++ {
+		for column := x; column < x+width; column++ {
+			if current.writes(column, row) && !a.isPeeled(column, row) {
+				current.score++
+			}
+		}
+	}
+	current.tight = a.isTight(current)
+	return current, true
+}
+
+// isTight tells if the outline character stops at the border of the
+// rectangle. Rectangles on top are tight, while the visible part of a
+// rectangle under them usually is not.
+func (a *analysis) isTight(c candidate) bool {
+	continues := func(x, y int) bool {
+		return x >= 0 && y >= 0 && a.isVisible(x, y) && a.cell(x, y) == c.outline
+	}
+
+	for column := c.x; column < c.x+c.width; column++ {
+		if continues(column, c.y-1) || continues(column, c.y+c.height) {
+			return false
+		}
+	}
+	for row := c.y; row < c.y+c.height; row++ {
+		if continues(c.x-1, row) || continues(c.x+c.width, row) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *analysis) interiorFill(c candidate) string {
+	fill := ""
+	for row := c.y + 1; row < c.y+c.height-1; row++ {
+		for column := c.x + 1; column < c.x+c.width-1; column++ {
+			if a.isPeeled(column, row) {
+				continue
+			}
+
+			value := a.cell(column, row)
+			if isBlank(value) || (fill != "" && value != fill) || text.ASCIIChar(value).Validate() != nil {
+				return EmptyChar
+			}
+			fill = value
+		}
+	}
+
+	if fill == "" {
+		return c.outline
+	}
+	return fill
+}
+
+func (c candidate) writes(x, y int) bool {
+	isBorder := x == c.x || y == c.y || x == c.x+c.width-1 || y == c.y+c.height-1
+	return isBorder || c.fill != EmptyChar
+}
+
+func (c candidate) overlapsAny(others []candidate) bool {
+	for _, other := range others {
+		if c.x < other.x+other.width && other.x < c.x+c.width &&
+			c.y < other.y+other.height && other.y < c.y+c.height {
+			return true
+		}
+	}
+	return false
+}
+
+func (c candidate) isBetter(other candidate) bool {
+	if c.tight != other.tight {
+		return c.tight
+	}
+	if c.score != other.score {
+		return c.score > other.score
+	}
+	return c.width*c.height > other.width*other.height
+}
+
+func (a *analysis) peel(c candidate) {
+	for row := c.y; row < c.y+c.height; row++ {
+		for column := c.x; column < c.x+c.width; column++ {
+			if c.writes(column, row) {
+				a.peeled[row][column] = true
+			}
+		}
+	}
+}
+
+func (c candidate) request() DrawRequest {
+	if utf8.RuneCountInString(c.outline) != 1 || text.ASCIIChar(c.outline).Validate() != nil {
+		return DrawRequest{Type: OperationText, X: c.x, Y: c.y, Text: c.outline}
+	}
+
+	request := DrawRequest{X: c.x, Y: c.y, Width: c.width, Height: c.height, Fill: text.ASCIIChar(c.fill)}
+	if c.fill != c.outline {
+		request.Outline = text.ASCIIChar(c.outline)
+	}
+	return request
+}
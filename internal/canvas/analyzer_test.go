@@ -0,0 +1,105 @@
+package canvas_test
+
+import (
+	"math/rand"
+	"sketch/internal/canvas"
+	"sketch/internal/text"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		drawing  string
+		expected canvas.DrawRequests
+	}{
+		{
+			name:     "solid rectangle, should return a single fill",
+			drawing:  "  ***\n  ***",
+			expected: canvas.DrawRequests{{X: 2, Width: 3, Height: 2, Fill: "*"}},
+		},
+		{
+			name:     "outlined rectangle, should return its outline and fill",
+			drawing:  "@@@@\n@..@\n@@@@",
+			expected: canvas.DrawRequests{{Width: 4, Height: 3, Outline: "@", Fill: "."}},
+		},
+		{
+			name:     "rectangle over another, should return them in drawing order",
+			drawing:  "....\n..##\n..##",
+			expected: canvas.DrawRequests{{Width: 4, Height: 3, Fill: "."}, {X: 2, Y: 1, Width: 2, Height: 2, Fill: "#"}},
+		},
+		{
+			name:     "outline with blank inside, should use a 'none' fill",
+			drawing:  "XXX\nX X\nXXX",
+			expected: canvas.DrawRequests{{Width: 3, Height: 3, Outline: "X", Fill: canvas.EmptyChar}},
+		},
+		{
+			name:     "non ascii characters, should become text operations",
+			drawing:  "🔥",
+			expected: canvas.DrawRequests{{Type: canvas.OperationText, Text: "🔥"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := canvas.Analyze(tc.drawing)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestAnalyze_RoundTrip(t *testing.T) {
+	drawer := canvas.NewDrawer()
+	assertRoundTrip := func(t *testing.T, requests []canvas.DrawRequest) {
+		t.Helper()
+		drawing, err := drawer.Draw(requests)
+		assert.NoError(t, err)
+
+		analyzed := canvas.Analyze(drawing)
+		assert.NoError(t, analyzed.Validate())
+		redrawn, err := drawer.Draw(analyzed)
+
+		assert.NoError(t, err)
+		assert.Equal(t, drawing, redrawn)
+		assert.LessOrEqual(t, len(analyzed), len(requests))
+	}
+
+	t.Run("fixtures", func(t *testing.T) {
+		assertRoundTrip(t, []canvas.DrawRequest{
+			{X: 3, Y: 2, Width: 5, Height: 3, Fill: "X", Outline: "@"},
+			{X: 10, Y: 3, Width: 14, Height: 6, Fill: "O", Outline: "X"},
+		})
+		assertRoundTrip(t, []canvas.DrawRequest{
+			{X: 14, Y: 0, Width: 7, Height: 6, Outline: "none", Fill: "."},
+			{X: 0, Y: 3, Width: 8, Height: 4, Outline: "O", Fill: "none"},
+			{X: 5, Y: 5, Width: 5, Height: 3, Outline: "X", Fill: "X"},
+		})
+	})
+
+	t.Run("random rectangles", func(t *testing.T) {
+		random := rand.New(rand.NewSource(42))
+		chars := []text.ASCIIChar{"#", "@", ".", "*", "+", canvas.EmptyChar}
+
+		for i := 0; i < 200; i++ {
+			requests := make([]canvas.DrawRequest, 1+random.Intn(4))
+			for j := range requests {
+				requests[j] = canvas.DrawRequest{
+					X:       random.Intn(20),
+					Y:       random.Intn(10),
+					Width:   1 + random.Intn(10),
+					Height:  1 + random.Intn(6),
+					Outline: chars[random.Intn(len(chars)-1)],
+					Fill:    chars[random.Intn(len(chars))],
+				}
+			}
+
+			drawing, _ := drawer.Draw(requests)
+			redrawn, err := drawer.Draw(canvas.Analyze(drawing))
+
+			assert.NoError(t, err)
+			assert.Equal(t, drawing, redrawn, "requests: %+v", requests)
+		}
+	})
+}
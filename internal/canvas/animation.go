@@ -0,0 +1,99 @@
+package canvas
+
+import (
+	"fmt"
+	"sketch/internal/errors"
+)
+
+const (
+	maxFrames        = 500
+	maxFrameDuration = 60000
+	// defaultFrameDuration is used, in milliseconds, for frames without one.
+	defaultFrameDuration = 100
+	maxLoops             = 100
+
+	SourceFrames    GIFSource = "frames"
+	SourceRevisions GIFSource = "revisions"
+	// defaultRevisionDuration is how long, in milliseconds, each revision is
+	// shown in a gif unless a delay is informed.
+	defaultRevisionDuration = 500
+)
+
+var (
+	ErrEmptyAnimation       = errors.Error("animation must have at least one frame")
+	ErrTooManyFrames        = errors.Error(fmt.Sprintf("animation must have at most %d frames", maxFrames))
+	ErrInvalidFrameDuration = errors.Error(fmt.Sprintf("frame duration must be between 0 and %d milliseconds", maxFrameDuration))
+	ErrFrameNotFound        = errors.Error("frame not found")
+	ErrInvalidFramePosition = errors.Error("frame must be a number equal or greater than zero")
+	ErrInvalidLoops         = errors.Error(fmt.Sprintf("loops must be a number between 1 and %d", maxLoops))
+	ErrInvalidGIFSource     = errors.Error("source must be frames or revisions")
+)
+
+type (
+	// GIFSource tells whether a gif plays the frames of an animation or the
+	// revisions of a canvas.
+	GIFSource string
+
+	// FrameRequest holds the operations of a frame. They are drawn over the
+	// previous frame, so a frame only needs what changed, unless it is clear.
+	FrameRequest struct {
+		Duration int          `json:"duration"`
+		Clear    bool         `json:"clear,omitempty"`
+		Requests DrawRequests `json:"requests"`
+	}
+
+	AnimationRequest struct {
+		Frames []FrameRequest `json:"frames"`
+	}
+
+	// Frame is a drawn frame and how long it is shown, in milliseconds.
+	Frame struct {
+		Drawing  string `json:"drawing" db:"drawing"`
+		Duration int    `json:"duration" db:"duration"`
+	}
+
+	AnimationResponse struct {
+		ID     string `json:"id"`
+		Frames int    `json:"frames"`
+	}
+)
+
+func (a AnimationRequest) Validate() error {
+	if len(a.Frames) == 0 {
+		return ErrEmptyAnimation
+	}
+	if len(a.Frames) > maxFrames {
+		return ErrTooManyFrames
+	}
+
+	for i, frame := range a.Frames {
+		if frame.Duration < 0 || frame.Duration > maxFrameDuration {
+			return fmt.Errorf("frame %d: %w", i, ErrInvalidFrameDuration)
+		}
+
+		// The first frame and the cleared ones start from a blank canvas, so
+		// they must draw something.
+		if i > 0 && !frame.Clear && len(frame.Requests) == 0 {
+			continue
+		}
+		if err := frame.Requests.Validate(); err != nil {
+			return fmt.Errorf("frame %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (f FrameRequest) duration() int {
+	if f.Duration == 0 {
+		return defaultFrameDuration
+	}
+	return f.Duration
+}
+
+func (s GIFSource) IsValid() bool {
+	switch s {
+	case SourceFrames, SourceRevisions:
+		return true
+	}
+	return false
+}
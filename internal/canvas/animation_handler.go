@@ -0,0 +1,222 @@
+package canvas
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"html/template"
+	"net/http"
+	"net/url"
+	"sketch/internal/imaging"
+	"sketch/internal/routing"
+	"strconv"
+	"time"
+)
+
+const (
+	// clearScreen moves the cursor home after clearing the terminal, so each
+	// frame is drawn in the same place.
+	clearScreen = "\x1b[2J\x1b[H"
+)
+
+type AnimationHandler struct {
+	service AnimationService
+}
+
+func NewAnimationHandler(service AnimationService) *AnimationHandler {
+	return &AnimationHandler{
+		service: service,
+	}
+}
+
+func (c *AnimationHandler) Save(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+	request, err := routing.FromJSON[AnimationRequest](r)
+	if err != nil {
+		return fmt.Errorf("failed to get json body: %w", err)
+	}
+
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	response, err := c.service.Save(r.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, response)
+}
+
+// GetFrame returns a frame of the animation, counting from zero.
+func (c *AnimationHandler) GetFrame(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	position, err := strconv.Atoi(params.ByName("n"))
+	if err != nil || position < 0 {
+		return ErrInvalidFramePosition
+	}
+
+	frame, err := c.service.GetFrame(r.Context(), params.ByName("id"), position)
+
+	if errors.Is(err, ErrFrameNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, frame)
+}
+
+// Play serves a page playing the frames in a loop.
+func (c *AnimationHandler) Play(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	frames, err := c.service.GetFrames(r.Context(), params.ByName("id"))
+
+	if errors.Is(err, ErrFrameNotFound) {
+		return template.
+			Must(template.ParseFiles("./pages/404.html")).
+			Execute(w, nil)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return template.
+		Must(template.ParseFiles("./pages/player.html")).
+		Execute(w, map[string]any{"Frames": frames})
+}
+
+// Stream plays the frames in a terminal, clearing the screen with ANSI escape
+// codes before each one, as many times as the loops query parameter says.
+//
+//	curl -N localhost:8080/<id>/stream?loops=3
+func (c *AnimationHandler) Stream(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	loops := 1
+	if raw := r.URL.Query().Get("loops"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 || value > maxLoops {
+			return ErrInvalidLoops
+		}
+		loops = value
+	}
+
+	frames, err := c.service.GetFrames(r.Context(), params.ByName("id"))
+
+	if errors.Is(err, ErrFrameNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+
+	for loop := 0; loop < loops; loop++ {
+		for _, frame := range frames {
+			if _, err := fmt.Fprint(w, clearScreen+frame.Drawing+"\n"); err != nil {
+				return nil
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+
+			timer := time.NewTimer(time.Duration(frame.Duration) * time.Millisecond)
+			select {
+			case <-r.Context().Done():
+				timer.Stop()
+				return nil
+			case <-timer.C:
+			}
+		}
+	}
+	return nil
+}
+
+// GIF exports the frames of an animation, or the revisions of a canvas with
+// source=revisions, as an animated gif. The scale and delay query parameters
+// set the size of the font and the milliseconds every frame is shown.
+func (c *AnimationHandler) GIF(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	source := SourceFrames
+	if raw := r.URL.Query().Get("source"); raw != "" {
+		source = GIFSource(raw)
+	}
+	if !source.IsValid() {
+		return ErrInvalidGIFSource
+	}
+
+	options, err := gifOptions(r.URL.Query())
+	if err != nil {
+		return err
+	}
+
+	frames, err := c.gifFrames(r, params.ByName("id"), source)
+
+	if errors.Is(err, ErrFrameNotFound) || errors.Is(err, ErrRevisionsNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// The gif is encoded before writing, so that a failure still gets a json
+	// error instead of a broken image.
+	encoded := bytes.Buffer{}
+	if err := imaging.EncodeGIF(&encoded, frames, options); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "image/gif")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(encoded.Bytes())
+	return err
+}
+
+func (c *AnimationHandler) gifFrames(r *http.Request, id string, source GIFSource) ([]imaging.GIFFrame, error) {
+	if source == SourceRevisions {
+		revisions, err := c.service.GetRevisions(r.Context(), id)
+		if err != nil {
+			return nil, err
+		}
+
+		frames := make([]imaging.GIFFrame, 0, len(revisions))
+		for _, revision := range revisions {
+			frames = append(frames, imaging.GIFFrame{Drawing: revision.Drawing, Duration: defaultRevisionDuration})
+		}
+		return frames, nil
+	}
+
+	animation, err := c.service.GetFrames(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	frames := make([]imaging.GIFFrame, 0, len(animation))
+	for _, frame := range animation {
+		frames = append(frames, imaging.GIFFrame{Drawing: frame.Drawing, Duration: frame.Duration})
+	}
+	return frames, nil
+}
+
+func gifOptions(query url.Values) (imaging.GIFOptions, error) {
+	options := imaging.DefaultGIFOptions()
+	var err error
+
+	if raw := query.Get("scale"); raw != "" {
+		if options.Scale, err = strconv.Atoi(raw); err != nil {
+			return options, imaging.ErrInvalidScale
+		}
+	}
+
+	if raw := query.Get("delay"); raw != "" {
+		if options.Delay, err = strconv.Atoi(raw); err != nil {
+			return options, imaging.ErrInvalidDelay
+		}
+	}
+	return options, options.Validate()
+}
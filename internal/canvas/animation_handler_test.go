@@ -0,0 +1,141 @@
+package canvas_test
+
+import (
+	"context"
+	"image/gif"
+	"net/http"
+	"net/http/httptest"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"sketch/internal/imaging"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnimationHandler_GetFrame(t *testing.T) {
+	t.Run("when the frame is not a number, should return an error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/id/frames/x", nil)
+		params := httprouter.Params{{Key: "id", Value: "id"}, {Key: "n", Value: "x"}}
+
+		err := canvas.NewAnimationHandler(nil).GetFrame(w, r, params)
+
+		assert.ErrorIs(t, err, canvas.ErrInvalidFramePosition)
+	})
+
+	t.Run("when the frame does not exist, should return not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockAnimationService(ctrl)
+		serviceMock.EXPECT().GetFrame(gomock.Any(), "id", 9).Return(nil, canvas.ErrFrameNotFound)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/id/frames/9", nil)
+		params := httprouter.Params{{Key: "id", Value: "id"}, {Key: "n", Value: "9"}}
+
+		err := canvas.NewAnimationHandler(serviceMock).GetFrame(w, r, params)
+
+		assert.ErrorIs(t, err, canvas.ErrFrameNotFound)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestAnimationHandler_Stream(t *testing.T) {
+	frames := []canvas.Frame{{Drawing: "|", Duration: 1}, {Drawing: "/", Duration: 1}}
+	params := httprouter.Params{{Key: "id", Value: "id"}}
+
+	t.Run("when looping, should clear the screen before every frame", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockAnimationService(ctrl)
+		serviceMock.EXPECT().GetFrames(gomock.Any(), "id").Return(frames, nil)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/id/stream?loops=2", nil)
+
+		err := canvas.NewAnimationHandler(serviceMock).Stream(w, r, params)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "\x1b[2J\x1b[H|\n\x1b[2J\x1b[H/\n\x1b[2J\x1b[H|\n\x1b[2J\x1b[H/\n", w.Body.String())
+	})
+
+	t.Run("when the client goes away, should stop after the current frame", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockAnimationService(ctrl)
+		serviceMock.EXPECT().GetFrames(gomock.Any(), "id").Return(frames, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/id/stream", nil).WithContext(ctx)
+
+		err := canvas.NewAnimationHandler(serviceMock).Stream(w, r, params)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "\x1b[2J\x1b[H|\n", w.Body.String())
+	})
+
+	t.Run("when loops is out of range, should return an error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/id/stream?loops=101", nil)
+
+		err := canvas.NewAnimationHandler(nil).Stream(w, r, params)
+
+		assert.ErrorIs(t, err, canvas.ErrInvalidLoops)
+	})
+}
+
+func TestAnimationHandler_GIF(t *testing.T) {
+	params := httprouter.Params{{Key: "id", Value: "id"}}
+
+	t.Run("when exporting the revisions, should return a frame per revision", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockAnimationService(ctrl)
+		serviceMock.EXPECT().GetRevisions(gomock.Any(), "id").
+			Return([]canvas.Revision{{Drawing: "a"}, {Drawing: "ab"}}, nil)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/id/gif?source=revisions&scale=1", nil)
+
+		err := canvas.NewAnimationHandler(serviceMock).GIF(w, r, params)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "image/gif", w.Header().Get("Content-Type"))
+		decoded, err := gif.DecodeAll(w.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{50, 50}, decoded.Delay)
+	})
+
+	t.Run("when the canvas has no frames, should return not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockAnimationService(ctrl)
+		serviceMock.EXPECT().GetFrames(gomock.Any(), "id").Return(nil, canvas.ErrFrameNotFound)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/id/gif", nil)
+
+		err := canvas.NewAnimationHandler(serviceMock).GIF(w, r, params)
+
+		assert.ErrorIs(t, err, canvas.ErrFrameNotFound)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("when the source is unknown, should return an error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/id/gif?source=history", nil)
+
+		err := canvas.NewAnimationHandler(nil).GIF(w, r, params)
+
+		assert.ErrorIs(t, err, canvas.ErrInvalidGIFSource)
+	})
+
+	t.Run("when the scale is too large, should return an error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/id/gif?scale=9", nil)
+
+		err := canvas.NewAnimationHandler(nil).GIF(w, r, params)
+
+		assert.ErrorIs(t, err, imaging.ErrInvalidScale)
+	})
+}
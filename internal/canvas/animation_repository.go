@@ -0,0 +1,93 @@
+package canvas
+
+import (
+	"context"
+	"database/sql"
+	goerrors "errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	AnimationRepository interface {
+		// Transaction runs the change in a single transaction, which the
+		// change must make its queries with the context it is given.
+		Transaction(ctx context.Context, change func(ctx context.Context) error) error
+		SaveFrames(ctx context.Context, id string, frames []Frame) error
+		GetFrame(ctx context.Context, id string, position int) (Frame, error)
+		GetFrames(ctx context.Context, id string) ([]Frame, error)
+	}
+
+	animationRepository struct {
+		db *sqlx.DB
+	}
+)
+
+func NewAnimationRepository(db *sqlx.DB) AnimationRepository {
+	return &animationRepository{
+		db: db,
+	}
+}
+
+func (r *animationRepository) Transaction(ctx context.Context, change func(ctx context.Context) error) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := change(withTx(ctx, tx.Tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+func (r *animationRepository) SaveFrames(ctx context.Context, id string, frames []Frame) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	defer tx.Rollback()
+
+	const query = "insert into drawing_frames (drawing_id, position, drawing, duration) values ($1, $2, $3, $4)"
+	for position, frame := range frames {
+		if _, err := tx.ExecContext(ctx, query, id, position, frame.Drawing, frame.Duration); err != nil {
+			return fmt.Errorf("database err: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+func (r *animationRepository) GetFrame(ctx context.Context, id string, position int) (Frame, error) {
+	const query = "select drawing, duration from drawing_frames where drawing_id = $1 and position = $2"
+	var frame Frame
+	if err := r.db.GetContext(ctx, &frame, query, id, position); err != nil {
+		if goerrors.Is(err, sql.ErrNoRows) {
+			return Frame{}, ErrFrameNotFound
+		}
+
+		return Frame{}, fmt.Errorf("database err: %w", err)
+	}
+	return frame, nil
+}
+
+func (r *animationRepository) GetFrames(ctx context.Context, id string) ([]Frame, error) {
+	const query = "select drawing, duration from drawing_frames where drawing_id = $1 order by position"
+	frames := make([]Frame, 0)
+	if err := r.db.SelectContext(ctx, &frames, query, id); err != nil {
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+	if len(frames) == 0 {
+		return nil, ErrFrameNotFound
+	}
+	return frames, nil
+}
@@ -0,0 +1,122 @@
+package canvas
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+)
+
+type (
+	animationService struct {
+		repository RevisionRepository
+		animations AnimationRepository
+		drawer     Drawer
+		symbols    SymbolRepository
+		events     Publisher
+	}
+	AnimationService interface {
+		Save(ctx context.Context, request AnimationRequest) (*AnimationResponse, error)
+		GetFrame(ctx context.Context, id string, position int) (*Frame, error)
+		GetFrames(ctx context.Context, id string) ([]Frame, error)
+		GetRevisions(ctx context.Context, id string) ([]Revision, error)
+	}
+)
+
+func NewAnimationService(repository RevisionRepository, animations AnimationRepository, drawer Drawer, symbols SymbolRepository, events Publisher) AnimationService {
+	return &animationService{
+		repository: repository,
+		animations: animations,
+		drawer:     drawer,
+		symbols:    symbols,
+		events:     events,
+	}
+}
+
+// Save draws every frame over the previous one and stores them. The first
+// frame is also saved as the canvas, so the animation is a regular canvas for
+// every other endpoint. Both are saved in a single transaction.
+func (s animationService) Save(ctx context.Context, request AnimationRequest) (*AnimationResponse, error) {
+	frames := make([]Frame, 0, len(request.Frames))
+	previous := ""
+	for i, frame := range request.Frames {
+		requests := frame.Requests
+		if previous != "" && !frame.Clear {
+			requests = append(DrawRequests{{Type: OperationText, Text: previous}}, requests...)
+		}
+
+		draw, _, err := render(ctx, s.drawer, s.symbols, requests)
+		if err != nil {
+			return nil, fmt.Errorf("frame %d: %w", i, err)
+		}
+
+		frames = append(frames, Frame{Drawing: draw, Duration: frame.duration()})
+		previous = draw
+	}
+
+	canvas := newOwnedCanvas(ctx, frames[0].Drawing)
+	err := s.animations.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.repository.Save(ctx, canvas); err != nil {
+			return fmt.Errorf("error saving canvas: %w", err)
+		}
+
+		if err := s.animations.SaveFrames(ctx, canvas.ID, frames); err != nil {
+			return fmt.Errorf("error saving frames: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.events.Publish(ctx, NewEvent(EventCreated, canvas))
+
+	return &AnimationResponse{
+		ID:     canvas.ID,
+		Frames: len(frames),
+	}, nil
+}
+
+func (s animationService) GetFrame(ctx context.Context, id string, position int) (*Frame, error) {
+	if err := s.checkCanvas(ctx, id); err != nil {
+		return nil, err
+	}
+
+	frame, err := s.animations.GetFrame(ctx, id, position)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get frame %d of '%s': %w", position, id, err)
+	}
+	return &frame, nil
+}
+
+func (s animationService) GetFrames(ctx context.Context, id string) ([]Frame, error) {
+	if err := s.checkCanvas(ctx, id); err != nil {
+		return nil, err
+	}
+
+	frames, err := s.animations.GetFrames(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get frames of '%s': %w", id, err)
+	}
+	return frames, nil
+}
+
+func (s animationService) GetRevisions(ctx context.Context, id string) ([]Revision, error) {
+	revisions, err := s.repository.GetRevisions(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revisions of '%s': %w", id, err)
+	}
+	return revisions, nil
+}
+
+// checkCanvas fails when the canvas of the animation is not in the workspace
+// of the request, as the frames are not scoped themselves.
+func (s animationService) checkCanvas(ctx context.Context, id string) error {
+	_, err := s.repository.GetOwner(ctx, id)
+	if goerrors.Is(err, ErrNotFound) {
+		return ErrFrameNotFound
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to get '%s': %w", id, err)
+	}
+	return nil
+}
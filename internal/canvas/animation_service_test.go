@@ -0,0 +1,105 @@
+package canvas_test
+
+import (
+	"context"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"sketch/tests/faker"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnimationService_Save(t *testing.T) {
+	request := canvas.AnimationRequest{Frames: []canvas.FrameRequest{
+		{Duration: 200, Requests: canvas.DrawRequests{{Type: canvas.OperationText, X: 0, Y: 0, Text: "load |"}}},
+		{Requests: canvas.DrawRequests{{Type: canvas.OperationText, X: 5, Y: 0, Text: "/"}}},
+		{Clear: true, Requests: canvas.DrawRequests{{Type: canvas.OperationText, X: 0, Y: 0, Text: "done"}}},
+	}}
+
+	t.Run("when the frames are valid, should draw each one over the previous and save them", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRevisionRepository(ctrl)
+		animationsMock := mock_canvas.NewMockAnimationRepository(ctrl)
+		service := canvas.NewAnimationService(repositoryMock, animationsMock, canvas.NewDrawer(), nil, canvas.NewBroker())
+		ctx := context.Background()
+
+		var saved canvas.Canvas
+		animationsMock.EXPECT().Transaction(ctx, gomock.Any()).
+			Times(1).
+			DoAndReturn(func(ctx context.Context, change func(context.Context) error) error {
+				return change(ctx)
+			})
+		repositoryMock.EXPECT().Save(ctx, gomock.Any()).
+			Times(1).
+			DoAndReturn(func(_ context.Context, c canvas.Canvas) error {
+				saved = c
+				return nil
+			})
+		animationsMock.EXPECT().SaveFrames(ctx, gomock.Any(), []canvas.Frame{
+			{Drawing: "load |", Duration: 200},
+			{Drawing: "load /", Duration: 100},
+			{Drawing: "done", Duration: 100},
+		}).Times(1).Return(nil)
+
+		response, err := service.Save(ctx, request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &canvas.AnimationResponse{ID: saved.ID, Frames: 3}, response)
+		assert.Equal(t, "load |", saved.Drawing)
+	})
+
+	t.Run("when saving the frames fails, should return the error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRevisionRepository(ctrl)
+		animationsMock := mock_canvas.NewMockAnimationRepository(ctrl)
+		service := canvas.NewAnimationService(repositoryMock, animationsMock, canvas.NewDrawer(), nil, canvas.NewBroker())
+		ctx := context.Background()
+
+		animationsMock.EXPECT().Transaction(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, change func(context.Context) error) error {
+				return change(ctx)
+			})
+		repositoryMock.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+		animationsMock.EXPECT().SaveFrames(ctx, gomock.Any(), gomock.Len(3)).Return(faker.NewError())
+
+		response, err := service.Save(ctx, request)
+
+		assert.ErrorIs(t, err, faker.NewError())
+		assert.Nil(t, response)
+	})
+}
+
+func TestAnimationService_GetFrame(t *testing.T) {
+	t.Run("when there is no such frame, should return frame not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRevisionRepository(ctrl)
+		animationsMock := mock_canvas.NewMockAnimationRepository(ctrl)
+		service := canvas.NewAnimationService(repositoryMock, animationsMock, nil, nil, canvas.NewBroker())
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().GetOwner(ctx, "id").Return("", nil)
+		animationsMock.EXPECT().GetFrame(ctx, "id", 4).Return(canvas.Frame{}, canvas.ErrFrameNotFound)
+
+		frame, err := service.GetFrame(ctx, "id", 4)
+
+		assert.ErrorIs(t, err, canvas.ErrFrameNotFound)
+		assert.Nil(t, frame)
+	})
+
+	t.Run("when the canvas is not in the workspace, should not look for its frames", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRevisionRepository(ctrl)
+		animationsMock := mock_canvas.NewMockAnimationRepository(ctrl)
+		service := canvas.NewAnimationService(repositoryMock, animationsMock, nil, nil, canvas.NewBroker())
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().GetOwner(ctx, "id").Return("", canvas.ErrNotFound)
+
+		frame, err := service.GetFrame(ctx, "id", 4)
+
+		assert.ErrorIs(t, err, canvas.ErrFrameNotFound)
+		assert.Nil(t, frame)
+	})
+}
@@ -0,0 +1,68 @@
+package canvas_test
+
+import (
+	"sketch/internal/canvas"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnimationRequest_Validate(t *testing.T) {
+	text := canvas.DrawRequests{{Type: canvas.OperationText, X: 0, Y: 0, Text: "a"}}
+
+	testCases := []struct {
+		name    string
+		request canvas.AnimationRequest
+		err     error
+	}{
+		{
+			name:    "when there are no frames, should return an error",
+			request: canvas.AnimationRequest{},
+			err:     canvas.ErrEmptyAnimation,
+		},
+		{
+			name:    "when there are too many frames, should return an error",
+			request: canvas.AnimationRequest{Frames: make([]canvas.FrameRequest, 501)},
+			err:     canvas.ErrTooManyFrames,
+		},
+		{
+			name:    "when the first frame has no requests, should return an error",
+			request: canvas.AnimationRequest{Frames: []canvas.FrameRequest{{Duration: 10}}},
+			err:     canvas.ErrEmptyRequests,
+		},
+		{
+			name: "when a cleared frame has no requests, should return an error",
+			request: canvas.AnimationRequest{Frames: []canvas.FrameRequest{
+				{Requests: text},
+				{Clear: true},
+			}},
+			err: canvas.ErrEmptyRequests,
+		},
+		{
+			name: "when a duration is too long, should return an error",
+			request: canvas.AnimationRequest{Frames: []canvas.FrameRequest{
+				{Requests: text, Duration: 60001},
+			}},
+			err: canvas.ErrInvalidFrameDuration,
+		},
+		{
+			name: "when a later frame has no requests, should repeat the previous one",
+			request: canvas.AnimationRequest{Frames: []canvas.FrameRequest{
+				{Requests: text},
+				{Duration: 500},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.request.Validate()
+
+			if tc.err == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package canvas
 
 import (
+	"context"
+	"fmt"
+	"sketch/internal/auth"
+	"sketch/internal/errors"
+	"sketch/internal/workspace"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,10 +15,22 @@ const (
 	EmptyChar = "none"
 )
 
+var (
+	ErrBodyTooLarge = errors.Error(fmt.Sprintf("the body must have at most %d bytes", maxUploadSize))
+)
+
 type Canvas struct {
-	ID        string    `json:"id" db:"id"`
-	Drawing   string    `json:"drawing" db:"drawing"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID string `json:"id" db:"id"`
+	// OwnerID is empty for canvases drawn before API keys, which anyone may
+	// change.
+	OwnerID string `json:"owner_id,omitempty" db:"owner_id"`
+	// WorkspaceID is empty for canvases outside every workspace.
+	WorkspaceID string    `json:"workspace_id,omitempty" db:"workspace_id"`
+	Drawing     string    `json:"drawing" db:"drawing"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	// Version is the one the canvas was read at. Updating it fails with
+	// ErrConflict once another change got in since, unless it is zero.
+	Version int `json:"-" db:"version"`
 }
 
 func NewCanvas(drawing string) Canvas {
@@ -23,3 +40,22 @@ func NewCanvas(drawing string) Canvas {
 		CreatedAt: time.Now().UTC(),
 	}
 }
+
+// newOwnedCanvas is a new canvas owned by whoever makes the request, in the
+// workspace of the request.
+func newOwnedCanvas(ctx context.Context, drawing string) Canvas {
+	canvas := NewCanvas(drawing)
+	canvas.OwnerID = auth.OwnerID(ctx)
+	canvas.WorkspaceID = workspace.ID(ctx)
+	return canvas
+}
+
+// ownedScope returns the owner and the workspace of the symbols and templates
+// the request uses: those of its workspace, which belong to all of it, or
+// those of its owner outside of them.
+func ownedScope(ctx context.Context) (string, string) {
+	if id := workspace.ID(ctx); id != "" {
+		return "", id
+	}
+	return auth.OwnerID(ctx), ""
+}
@@ -0,0 +1,380 @@
+package canvas
+
+import (
+	"math"
+	"sketch/internal/errors"
+	"strconv"
+	"unicode/utf8"
+)
+
+const (
+	ChartBar       ChartKind = "bar"
+	ChartColumn    ChartKind = "column"
+	ChartLine      ChartKind = "line"
+	ChartSparkline ChartKind = "sparkline"
+
+	defaultBarChar   = "#"
+	defaultPointChar = "*"
+	sparkBlocks      = "▁▂▃▄▅▆▇█"
+)
+
+var (
+	ErrMissingChart        = errors.Error("chart operations require a chart")
+	ErrUnknownChartKind    = errors.Error("chart kind must be bar, column, line or sparkline")
+	ErrEmptySeries         = errors.Error("chart series must have at least one value")
+	ErrInvalidSeriesValue  = errors.Error("chart series values must be finite numbers")
+	ErrNegativeSeriesValue = errors.Error("bar and column charts only accept values equal or greater than zero")
+	ErrLabelsMismatch      = errors.Error("chart labels must have one label per value")
+	ErrChartTooSmall       = errors.Error("chart does not fit in its width and height")
+)
+
+type (
+	// ChartKind tells how a Chart draws its series: horizontal bars, vertical
+	// columns, a line plot or a sparkline made of block characters.
+	ChartKind string
+
+	Chart struct {
+		Kind   ChartKind `json:"kind"`
+		Series []float64 `json:"series"`
+		Labels []string  `json:"labels,omitempty"`
+	}
+
+	// chartFrame is the area left to the plot once the axes and their labels
+	// are placed.
+	chartFrame struct {
+		left   int
+		width  int
+		height int
+	}
+)
+
+// Validate checks the chart fits in the bounding box given by the request.
+func (c Chart) Validate(width, height int) error {
+	switch c.Kind {
+	case ChartBar, ChartColumn, ChartLine, ChartSparkline:
+	default:
+		return ErrUnknownChartKind
+	}
+
+	if len(c.Series) == 0 {
+		return ErrEmptySeries
+	}
+
+	for _, value := range c.Series {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return ErrInvalidSeriesValue
+		}
+		if value < 0 && (c.Kind == ChartBar || c.Kind == ChartColumn) {
+			return ErrNegativeSeriesValue
+		}
+	}
+
+	if len(c.Labels) != 0 && (len(c.Labels) != len(c.Series) || c.Kind == ChartSparkline) {
+		return ErrLabelsMismatch
+	}
+
+	if !c.fits(width, height) {
+		return ErrChartTooSmall
+	}
+	return nil
+}
+
+func (c Chart) fits(width, height int) bool {
+	switch c.Kind {
+	case ChartBar:
+		_, space, _ := c.barLayout(width)
+		return height >= len(c.Series) && space > 0
+	case ChartColumn:
+		frame := c.frame(width, height)
+		return frame.height > 0 && frame.width >= len(c.Series)
+	case ChartLine:
+		frame := c.frame(width, height)
+		return frame.height > 0 && frame.width > 0
+	}
+	return width > 0 && height > 0
+}
+
+// Render draws the chart in a grid of the given size. Cells the chart does not
+// use are left empty.
+func (c Chart) Render(width, height int, char string) Draw {
+	draw := NewDraw(width, height)
+	switch c.Kind {
+	case ChartBar:
+		c.renderBar(draw, width, orDefault(char, defaultBarChar))
+	case ChartColumn:
+		c.renderColumn(draw, width, height, orDefault(char, defaultBarChar))
+	case ChartLine:
+		c.renderLine(draw, width, height, orDefault(char, defaultPointChar))
+	case ChartSparkline:
+		c.renderSparkline(draw, width, height)
+	}
+	return draw
+}
+
+// barLayout returns the width of the labels, the columns left to the bars and
+// whether the values fit after them.
+func (c Chart) barLayout(width int) (int, int, bool) {
+	labels := 0
+	if len(c.Labels) != 0 {
+		labels = longest(c.Labels) + 1
+	}
+
+	values := make([]string, len(c.Series))
+	for i, value := range c.Series {
+		values[i] = formatValue(value)
+	}
+
+	// label, axis, bar, space and value
+	withValues := width - labels - 1 - longest(values) - 1
+	if withValues > 0 {
+		return labels, withValues, true
+	}
+	return labels, width - labels - 1, false
+}
+
+func (c Chart) renderBar(draw Draw, width int, char string) {
+	labels, space, showValues := c.barLayout(width)
+	highest := maxValue(c.Series)
+
+	for row, value := range c.Series {
+		if len(c.Labels) != 0 {
+			writeString(draw, 0, row, c.Labels[row])
+		}
+		draw.Set(labels, row, "|")
+
+		length := scale(value, highest, space)
+		for i := 0; i < length; i++ {
+			draw.Set(labels+1+i, row, char)
+		}
+		if showValues {
+			writeString(draw, labels+1+length+1, row, formatValue(value))
+		}
+	}
+}
+
+// frame places the y axis labels on the left, the x axis on the row below the
+// plot and the labels, when there are any, on the last row.
+func (c Chart) frame(width, height int) chartFrame {
+	low, high := c.bounds()
+	left := longest([]string{formatValue(low), formatValue(high)}) + 1
+
+	plotHeight := height - 1
+	if len(c.Labels) != 0 {
+		plotHeight--
+	}
+	return chartFrame{left: left, width: width - left, height: plotHeight}
+}
+
+// bounds returns the values at the bottom and at the top of the y axis.
+// Columns always start at zero.
+func (c Chart) bounds() (float64, float64) {
+	if c.Kind == ChartColumn {
+		return 0, maxValue(c.Series)
+	}
+	return minValue(c.Series), maxValue(c.Series)
+}
+
+func (c Chart) renderAxes(draw Draw, frame chartFrame) {
+	low, high := c.bounds()
+	writeString(draw, frame.left-1-utf8.RuneCountInString(formatValue(high)), 0, formatValue(high))
+	for row := 0; row < frame.height; row++ {
+		draw.Set(frame.left-1, row, "|")
+	}
+
+	bottom := frame.height
+	if c.Kind == ChartLine {
+		bottom--
+	}
+	if bottom > 0 {
+		writeString(draw, frame.left-1-utf8.RuneCountInString(formatValue(low)), bottom, formatValue(low))
+	}
+
+	draw.Set(frame.left-1, frame.height, "+")
+	for column := 0; column < frame.width; column++ {
+		draw.Set(frame.left+column, frame.height, "-")
+	}
+}
+
+func (c Chart) renderColumn(draw Draw, width, height int, char string) {
+	frame := c.frame(width, height)
+	c.renderAxes(draw, frame)
+	highest := maxValue(c.Series)
+
+	slot := frame.width / len(c.Series)
+	columnWidth := slot
+	if slot > 1 {
+		columnWidth = slot - 1
+	}
+
+	for i, value := range c.Series {
+		start := frame.left + i*slot
+		for row := frame.height - scale(value, highest, frame.height); row < frame.height; row++ {
+			for column := start; column < start+columnWidth; column++ {
+				draw.Set(column, row, char)
+			}
+		}
+		if len(c.Labels) != 0 {
+			writeString(draw, start, frame.height+1, truncate(c.Labels[i], columnWidth))
+		}
+	}
+}
+
+func (c Chart) renderLine(draw Draw, width, height int, char string) {
+	frame := c.frame(width, height)
+	c.renderAxes(draw, frame)
+	low, high := c.bounds()
+
+	points := make([][2]int, len(c.Series))
+	for i, value := range c.Series {
+		column := 0
+		if len(c.Series) > 1 {
+			column = int(math.Round(float64(i*(frame.width-1)) / float64(len(c.Series)-1)))
+		}
+
+		row := (frame.height - 1) / 2
+		if high > low {
+			row = frame.height - 1 - int(math.Round((value-low)/(high-low)*float64(frame.height-1)))
+		}
+		points[i] = [2]int{frame.left + column, row}
+	}
+
+	lines := drawer{}
+	for i := 1; i < len(points); i++ {
+		segment := DrawRequest{X: points[i-1][0], Y: points[i-1][1], ToX: points[i][0], ToY: points[i][1]}
+		lines.drawLine(draw, segment)
+	}
+	for _, point := range points {
+		draw.Set(point[0], point[1], char)
+	}
+
+	// Labels go under their point, skipping the ones that would overlap.
+	next := 0
+	for i, label := range c.Labels {
+		start := points[i][0]
+		if start < next {
+			continue
+		}
+		label = truncate(label, width-start)
+		writeString(draw, start, frame.height+1, label)
+		next = start + utf8.RuneCountInString(label) + 1
+	}
+}
+
+// renderSparkline draws a column per value, averaging consecutive values when
+// there are more of them than columns. Each row holds eight levels.
+func (c Chart) renderSparkline(draw Draw, width, height int) {
+	values := resample(c.Series, width)
+	low, high := minValue(values), maxValue(values)
+	blocks := []rune(sparkBlocks)
+	levels := height * len(blocks)
+
+	for column, value := range values {
+		level := (levels + 1) / 2
+		if high > low {
+			level = 1 + int(math.Round((value-low)/(high-low)*float64(levels-1)))
+		}
+
+		for row := height - 1; level > 0; row-- {
+			block := blocks[len(blocks)-1]
+			if level < len(blocks) {
+				block = blocks[level-1]
+			}
+			draw.Set(column, row, string(block))
+			level -= len(blocks)
+		}
+	}
+}
+
+func resample(series []float64, width int) []float64 {
+	if len(series) <= width {
+		return series
+	}
+
+	result := make([]float64, width)
+	for column := range result {
+		start, end := column*len(series)/width, (column+1)*len(series)/width
+		sum := 0.0
+		for _, value := range series[start:end] {
+			sum += value
+		}
+		result[column] = sum / float64(end-start)
+	}
+	return result
+}
+
+// scale converts the value into a length out of size, where the highest value
+// takes the whole size. Values above zero are always visible.
+func scale(value, highest float64, size int) int {
+	if highest <= 0 || value <= 0 {
+		return 0
+	}
+	length := int(math.Round(value / highest * float64(size)))
+	if length == 0 {
+		return 1
+	}
+	return length
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+func writeString(draw Draw, column, row int, value string) {
+	for _, char := range value {
+		if column >= len(draw[row]) {
+			return
+		}
+		if char != ' ' {
+			draw.Set(column, row, string(char))
+		}
+		column++
+	}
+}
+
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if length < 0 {
+		length = 0
+	}
+	if len(runes) > length {
+		return string(runes[:length])
+	}
+	return value
+}
+
+func longest(values []string) int {
+	length := 0
+	for _, value := range values {
+		if current := utf8.RuneCountInString(value); current > length {
+			length = current
+		}
+	}
+	return length
+}
+
+func minValue(values []float64) float64 {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value < result {
+			result = value
+		}
+	}
+	return result
+}
+
+func maxValue(values []float64) float64 {
+	result := values[0]
+	for _, value := range values[1:] {
+		if value > result {
+			result = value
+		}
+	}
+	return result
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" || value == EmptyChar {
+		return fallback
+	}
+	return value
+}
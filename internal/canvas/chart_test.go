@@ -0,0 +1,150 @@
+package canvas_test
+
+import (
+	"math"
+	"sketch/internal/canvas"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChart_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		chart         canvas.Chart
+		width, height int
+		expectedErr   error
+	}{
+		{
+			name:        "when the kind is unknown, should return an error",
+			chart:       canvas.Chart{Kind: "pie", Series: []float64{1}},
+			width:       10,
+			height:      5,
+			expectedErr: canvas.ErrUnknownChartKind,
+		},
+		{
+			name:        "when the series is empty, should return an error",
+			chart:       canvas.Chart{Kind: canvas.ChartLine},
+			width:       10,
+			height:      5,
+			expectedErr: canvas.ErrEmptySeries,
+		},
+		{
+			name:        "when a value is not a number, should return an error",
+			chart:       canvas.Chart{Kind: canvas.ChartLine, Series: []float64{1, math.NaN()}},
+			width:       10,
+			height:      5,
+			expectedErr: canvas.ErrInvalidSeriesValue,
+		},
+		{
+			name:        "when a bar is negative, should return an error",
+			chart:       canvas.Chart{Kind: canvas.ChartBar, Series: []float64{1, -1}},
+			width:       10,
+			height:      5,
+			expectedErr: canvas.ErrNegativeSeriesValue,
+		},
+		{
+			name:        "when labels do not match the series, should return an error",
+			chart:       canvas.Chart{Kind: canvas.ChartColumn, Series: []float64{1, 2}, Labels: []string{"a"}},
+			width:       10,
+			height:      5,
+			expectedErr: canvas.ErrLabelsMismatch,
+		},
+		{
+			name:        "when a sparkline has labels, should return an error",
+			chart:       canvas.Chart{Kind: canvas.ChartSparkline, Series: []float64{1}, Labels: []string{"a"}},
+			width:       10,
+			height:      1,
+			expectedErr: canvas.ErrLabelsMismatch,
+		},
+		{
+			name:        "when there are more bars than rows, should return an error",
+			chart:       canvas.Chart{Kind: canvas.ChartBar, Series: []float64{1, 2, 3}},
+			width:       10,
+			height:      2,
+			expectedErr: canvas.ErrChartTooSmall,
+		},
+		{
+			name:        "when there are more columns than the plot width, should return an error",
+			chart:       canvas.Chart{Kind: canvas.ChartColumn, Series: []float64{1, 2, 3}},
+			width:       4,
+			height:      5,
+			expectedErr: canvas.ErrChartTooSmall,
+		},
+		{
+			name:   "when the chart fits, should return no error",
+			chart:  canvas.Chart{Kind: canvas.ChartLine, Series: []float64{-1, 2.5}, Labels: []string{"a", "b"}},
+			width:  10,
+			height: 3,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.chart.Validate(tc.width, tc.height)
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestDrawer_DrawChart(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+		request  canvas.DrawRequest
+	}{
+		{
+			name: "bar chart, should draw a row per value with its label and value",
+			expected: `cpu  |##### 10
+mem  |### 5
+disk |# 0.5`,
+			request: canvas.DrawRequest{
+				Type: canvas.OperationChart, Width: 15, Height: 3,
+				Chart: &canvas.Chart{Kind: canvas.ChartBar, Series: []float64{10, 5, 0.5}, Labels: []string{"cpu", "mem", "disk"}},
+			},
+		},
+		{
+			name: "column chart, should draw the axes and the labels under the columns",
+			expected: `4|   ==
+ |== ==
+ |== ==
+0+------
+  ab cd`,
+			request: canvas.DrawRequest{
+				Type: canvas.OperationChart, Width: 8, Height: 5, Fill: "=",
+				Chart: &canvas.Chart{Kind: canvas.ChartColumn, Series: []float64{2, 4}, Labels: []string{"ab", "cd"}},
+			},
+		},
+		{
+			name: "line chart, should connect the points",
+			expected: `3|  *
+ | / \
+1|*   *
+ +-----`,
+			request: canvas.DrawRequest{
+				Type: canvas.OperationChart, Width: 7, Height: 4,
+				Chart: &canvas.Chart{Kind: canvas.ChartLine, Series: []float64{1, 3, 1}},
+			},
+		},
+		{
+			name:     "sparkline, should average the values that do not fit",
+			expected: "  ▁▆█",
+			request: canvas.DrawRequest{
+				Type: canvas.OperationChart, X: 2, Width: 3, Height: 1,
+				Chart: &canvas.Chart{Kind: canvas.ChartSparkline, Series: []float64{0, 2, 6, 8, 10, 10}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.NoError(t, tc.request.Validate())
+
+			drawer := canvas.NewDrawer()
+			got, err := drawer.Draw([]canvas.DrawRequest{tc.request})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
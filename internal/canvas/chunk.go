@@ -0,0 +1,67 @@
+package canvas
+
+const (
+	ChunkSize = 64
+)
+
+type (
+	chunkKey struct {
+		X int `db:"chunk_x"`
+		Y int `db:"chunk_y"`
+	}
+
+	chunk struct {
+		chunkKey
+		Content string `db:"content"`
+	}
+)
+
+// chunkRange returns the first and last chunk keys covering the viewport.
+func chunkRange(viewport Viewport) (chunkKey, chunkKey) {
+	first := chunkKey{X: viewport.X / ChunkSize, Y: viewport.Y / ChunkSize}
+	last := chunkKey{
+		X: (viewport.X + viewport.Width - 1) / ChunkSize,
+		Y: (viewport.Y + viewport.Height - 1) / ChunkSize,
+	}
+	return first, last
+}
+
+// splitChunks cuts a drawing into ChunkSize x ChunkSize pieces, leaving the
+// cells outside the drawing empty. Chunks without any cell are skipped, since
+// assembling treats missing chunks as empty.
+func splitChunks(draw Draw) []chunk {
+	width, height := draw.Size()
+	chunks := make([]chunk, 0)
+	for y := 0; y*ChunkSize < height; y++ {
+		for x := 0; x*ChunkSize < width; x++ {
+			piece := draw.crop(Viewport{X: x * ChunkSize, Y: y * ChunkSize, Width: ChunkSize, Height: ChunkSize}, "")
+			if piece.IsEmpty() {
+				continue
+			}
+			chunks = append(chunks, chunk{chunkKey: chunkKey{X: x, Y: y}, Content: piece.String()})
+		}
+	}
+	return chunks
+}
+
+// assembleChunks places the chunks back in a grid whose origin is the given
+// chunk key.
+func assembleChunks(origin chunkKey, width, height int, chunks []chunk) Draw {
+	draw := NewDraw(width, height)
+	for _, c := range chunks {
+		offsetX := (c.X - origin.X) * ChunkSize
+		offsetY := (c.Y - origin.Y) * ChunkSize
+		for row, cells := range ParseDraw(c.Content) {
+			if offsetY+row >= height {
+				break
+			}
+			for column, cell := range cells {
+				if offsetX+column >= width {
+					break
+				}
+				draw[offsetY+row][offsetX+column] = cell
+			}
+		}
+	}
+	return draw
+}
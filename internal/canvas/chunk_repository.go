@@ -0,0 +1,219 @@
+package canvas
+
+import (
+	"context"
+	"database/sql"
+	goerrors "errors"
+	"fmt"
+
+	"sketch/internal/workspace"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	// chunkedRepository stores each canvas as ChunkSize x ChunkSize chunks, so
+	// viewports only read the chunks they touch.
+	chunkedRepository struct {
+		db *sqlx.DB
+	}
+
+	chunkedCanvas struct {
+		Canvas
+		Width  int `db:"width"`
+		Height int `db:"height"`
+	}
+)
+
+func NewChunkedRepository(db *sqlx.DB) Repository {
+	return &chunkedRepository{
+		db: db,
+	}
+}
+
+func (r *chunkedRepository) GetByID(ctx context.Context, id string) (Canvas, error) {
+	meta, err := r.getMeta(ctx, r.db, id)
+	if err != nil {
+		return Canvas{}, err
+	}
+
+	const query = "select chunk_x, chunk_y, content from drawing_chunks where drawing_id = $1"
+	var chunks []chunk
+	if err := r.db.SelectContext(ctx, &chunks, query, id); err != nil {
+		return Canvas{}, fmt.Errorf("database err: %w", err)
+	}
+
+	canvas := meta.Canvas
+	canvas.Drawing = assembleChunks(chunkKey{}, meta.Width, meta.Height, chunks).String()
+	return canvas, nil
+}
+
+func (r *chunkedRepository) GetViewport(ctx context.Context, id string, viewport Viewport) (Canvas, error) {
+	meta, err := r.getMeta(ctx, r.db, id)
+	if err != nil {
+		return Canvas{}, err
+	}
+
+	chunks, err := r.getChunks(ctx, r.db, id, viewport)
+	if err != nil {
+		return Canvas{}, err
+	}
+
+	first, last := chunkRange(viewport)
+	width := (last.X - first.X + 1) * ChunkSize
+	height := (last.Y - first.Y + 1) * ChunkSize
+	area := assembleChunks(first, width, height, chunks)
+
+	canvas := meta.Canvas
+	canvas.Drawing = area.Crop(Viewport{
+		X:      viewport.X - first.X*ChunkSize,
+		Y:      viewport.Y - first.Y*ChunkSize,
+		Width:  viewport.Width,
+		Height: viewport.Height,
+	}).String()
+	return canvas, nil
+}
+
+func (r *chunkedRepository) Save(ctx context.Context, canvas Canvas) error {
+	draw := ParseDraw(canvas.Drawing)
+	width, height := draw.Size()
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	defer tx.Rollback()
+
+	const query = "insert into chunked_drawings (id, owner_id, workspace_id, width, height, created_at) values ($1, $2, $3, $4, $5, $6)"
+	if _, err := tx.ExecContext(ctx, query, canvas.ID, canvas.OwnerID, canvas.WorkspaceID, width, height, canvas.CreatedAt); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	if err := r.saveChunks(ctx, tx, canvas.ID, splitChunks(draw)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+// Update writes only the chunks the new drawing changes, and deletes those it
+// left empty or out of its bounds, so small edits of large canvases stay
+// small writes.
+func (r *chunkedRepository) Update(ctx context.Context, canvas Canvas) error {
+	draw := ParseDraw(canvas.Drawing)
+	width, height := draw.Size()
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	defer tx.Rollback()
+
+	const update = "update chunked_drawings set width = $2, height = $3, version = version + 1 " +
+		"where id = $1 and workspace_id = $4 and ($5 = 0 or version = $5)"
+	result, err := tx.ExecContext(ctx, update, canvas.ID, width, height, workspace.ID(ctx), canvas.Version)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return missedUpdate(ctx, r, canvas)
+	}
+
+	const selectChunks = "select chunk_x, chunk_y, content from drawing_chunks where drawing_id = $1"
+	var stored []chunk
+	if err := sqlx.SelectContext(ctx, tx, &stored, selectChunks, canvas.ID); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	contents := make(map[chunkKey]string, len(stored))
+	for _, c := range stored {
+		contents[c.chunkKey] = c.Content
+	}
+
+	changed := make([]chunk, 0)
+	for _, c := range splitChunks(draw) {
+		if content, ok := contents[c.chunkKey]; !ok || content != c.Content {
+			changed = append(changed, c)
+		}
+		delete(contents, c.chunkKey)
+	}
+
+	if err := r.saveChunks(ctx, tx, canvas.ID, changed); err != nil {
+		return err
+	}
+
+	const deleteChunk = "delete from drawing_chunks where drawing_id = $1 and chunk_x = $2 and chunk_y = $3"
+	for key := range contents {
+		if _, err := tx.ExecContext(ctx, deleteChunk, canvas.ID, key.X, key.Y); err != nil {
+			return fmt.Errorf("database err: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the canvas, its chunks go with it.
+func (r *chunkedRepository) Delete(ctx context.Context, id string) error {
+	const query = "delete from chunked_drawings where id = $1 and workspace_id = $2"
+	result, err := executor(ctx, r.db).ExecContext(ctx, query, id, workspace.ID(ctx))
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *chunkedRepository) GetOwner(ctx context.Context, id string) (string, error) {
+	const query = "select owner_id from chunked_drawings where id = $1 and workspace_id = $2"
+	return getOwner(ctx, executor(ctx, r.db), query, id)
+}
+
+func (r *chunkedRepository) CountByOwner(ctx context.Context, ownerID string) (int, error) {
+	const query = "select count(*) from chunked_drawings where owner_id = $1"
+	return countByOwner(ctx, executor(ctx, r.db), query, ownerID)
+}
+
+func (r *chunkedRepository) getMeta(ctx context.Context, q sqlx.QueryerContext, id string) (chunkedCanvas, error) {
+	const query = "select id, owner_id, workspace_id, width, height, version, created_at from chunked_drawings " +
+		"where id = $1 and workspace_id = $2"
+	var meta chunkedCanvas
+	if err := sqlx.GetContext(ctx, q, &meta, query, id, workspace.ID(ctx)); err != nil {
+		if goerrors.Is(err, sql.ErrNoRows) {
+			return meta, ErrNotFound
+		}
+
+		return meta, fmt.Errorf("database err: %w", err)
+	}
+	return meta, nil
+}
+
+func (r *chunkedRepository) getChunks(ctx context.Context, q sqlx.QueryerContext, id string, viewport Viewport) ([]chunk, error) {
+	const query = "select chunk_x, chunk_y, content from drawing_chunks " +
+		"where drawing_id = $1 and chunk_x between $2 and $3 and chunk_y between $4 and $5"
+	first, last := chunkRange(viewport)
+	var chunks []chunk
+	if err := sqlx.SelectContext(ctx, q, &chunks, query, id, first.X, last.X, first.Y, last.Y); err != nil {
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+	return chunks, nil
+}
+
+func (r *chunkedRepository) saveChunks(ctx context.Context, tx sqlx.ExecerContext, id string, chunks []chunk) error {
+	const query = "insert into drawing_chunks (drawing_id, chunk_x, chunk_y, content) values ($1, $2, $3, $4) " +
+		"on conflict (drawing_id, chunk_x, chunk_y) do update set content = excluded.content"
+	for _, c := range chunks {
+		if _, err := tx.ExecContext(ctx, query, id, c.X, c.Y, c.Content); err != nil {
+			return fmt.Errorf("database err: %w", err)
+		}
+	}
+	return nil
+}
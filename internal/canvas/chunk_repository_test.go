@@ -0,0 +1,213 @@
+package canvas_test
+
+import (
+	"context"
+	"database/sql"
+	"sketch/internal/canvas"
+	"sketch/tests/faker"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunkedRepository_Save(t *testing.T) {
+	const (
+		insertCanvas = "insert into chunked_drawings (id, owner_id, workspace_id, width, height, created_at) values ($1, $2, $3, $4, $5, $6)"
+		insertChunk  = "insert into drawing_chunks (drawing_id, chunk_x, chunk_y, content) values ($1, $2, $3, $4) " +
+			"on conflict (drawing_id, chunk_x, chunk_y) do update set content = excluded.content"
+	)
+	setup := func() (canvas.Repository, sqlmock.Sqlmock) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		db := sqlx.NewDb(mockDB, "sqlmock")
+		return canvas.NewChunkedRepository(db), mock
+	}
+
+	t.Run("when the drawing is wider than a chunk, should store one row per chunk", func(t *testing.T) {
+		repository, mock := setup()
+		fakeCanvas := canvas.NewCanvas(strings.Repeat("a", canvas.ChunkSize) + "b")
+
+		mock.ExpectBegin()
+		mock.ExpectExec(insertCanvas).
+			WithArgs(fakeCanvas.ID, fakeCanvas.OwnerID, fakeCanvas.WorkspaceID, canvas.ChunkSize+1, 1, fakeCanvas.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertChunk).
+			WithArgs(fakeCanvas.ID, 0, 0, strings.Repeat("a", canvas.ChunkSize)+strings.Repeat("\n", canvas.ChunkSize-1)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertChunk).
+			WithArgs(fakeCanvas.ID, 1, 0, "b"+strings.Repeat("\n", canvas.ChunkSize-1)).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repository.Save(context.Background(), fakeCanvas)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when inserting a chunk fails, should rollback and return the error", func(t *testing.T) {
+		repository, mock := setup()
+		fakeCanvas := faker.NewCanvas(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(insertCanvas).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(insertChunk).WillReturnError(faker.NewError())
+		mock.ExpectRollback()
+
+		err := repository.Save(context.Background(), fakeCanvas)
+
+		assert.ErrorIs(t, err, faker.NewError())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestChunkedRepository_Update(t *testing.T) {
+	const (
+		updateCanvas = "update chunked_drawings set width = $2, height = $3, version = version + 1 " +
+			"where id = $1 and workspace_id = $4 and ($5 = 0 or version = $5)"
+		selectChunks = "select chunk_x, chunk_y, content from drawing_chunks where drawing_id = $1"
+		insertChunk  = "insert into drawing_chunks (drawing_id, chunk_x, chunk_y, content) values ($1, $2, $3, $4) " +
+			"on conflict (drawing_id, chunk_x, chunk_y) do update set content = excluded.content"
+		deleteChunk = "delete from drawing_chunks where drawing_id = $1 and chunk_x = $2 and chunk_y = $3"
+	)
+	setup := func() (canvas.Repository, sqlmock.Sqlmock) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		return canvas.NewChunkedRepository(sqlx.NewDb(mockDB, "sqlmock")), mock
+	}
+	first := strings.Repeat("a", canvas.ChunkSize) + strings.Repeat("\n", canvas.ChunkSize-1)
+	second := "b" + strings.Repeat("\n", canvas.ChunkSize-1)
+
+	t.Run("when one cell changes, should write only its chunk", func(t *testing.T) {
+		repository, mock := setup()
+		edited := canvas.Canvas{ID: "123", Drawing: strings.Repeat("a", canvas.ChunkSize) + "c"}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateCanvas).
+			WithArgs("123", canvas.ChunkSize+1, 1, "", 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(selectChunks).
+			WithArgs("123").
+			WillReturnRows(sqlmock.NewRows([]string{"chunk_x", "chunk_y", "content"}).
+				AddRow(0, 0, first).
+				AddRow(1, 0, second))
+		mock.ExpectExec(insertChunk).
+			WithArgs("123", 1, 0, "c"+strings.Repeat("\n", canvas.ChunkSize-1)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repository.Update(context.Background(), edited)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when the drawing shrinks, should delete the chunks out of its bounds", func(t *testing.T) {
+		repository, mock := setup()
+		edited := canvas.Canvas{ID: "123", Drawing: strings.Repeat("a", canvas.ChunkSize)}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateCanvas).
+			WithArgs("123", canvas.ChunkSize, 1, "", 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(selectChunks).
+			WithArgs("123").
+			WillReturnRows(sqlmock.NewRows([]string{"chunk_x", "chunk_y", "content"}).
+				AddRow(0, 0, first).
+				AddRow(1, 0, second))
+		mock.ExpectExec(deleteChunk).
+			WithArgs("123", 1, 0).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repository.Update(context.Background(), edited)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when the canvas does not exist, should return not found", func(t *testing.T) {
+		repository, mock := setup()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(updateCanvas).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repository.Update(context.Background(), canvas.Canvas{ID: "123", Drawing: "a"})
+
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestChunkedRepository_GetByID(t *testing.T) {
+	const (
+		selectCanvas = "select id, owner_id, workspace_id, width, height, version, created_at from chunked_drawings where id = $1 and workspace_id = $2"
+		selectChunks = "select chunk_x, chunk_y, content from drawing_chunks where drawing_id = $1"
+	)
+	setup := func() (canvas.Repository, sqlmock.Sqlmock) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		db := sqlx.NewDb(mockDB, "sqlmock")
+		return canvas.NewChunkedRepository(db), mock
+	}
+
+	t.Run("when there are chunks, should assemble the full drawing", func(t *testing.T) {
+		repository, mock := setup()
+		createdAt := time.Now().UTC()
+		mock.ExpectQuery(selectCanvas).
+			WithArgs("123", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "width", "height", "created_at"}).
+				AddRow("123", canvas.ChunkSize+1, 2, createdAt))
+		mock.ExpectQuery(selectChunks).
+			WithArgs("123").
+			WillReturnRows(sqlmock.NewRows([]string{"chunk_x", "chunk_y", "content"}).
+				AddRow(0, 0, strings.Repeat("a", canvas.ChunkSize)+"\n*").
+				AddRow(1, 0, "b"))
+
+		result, err := repository.GetByID(context.Background(), "123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Repeat("a", canvas.ChunkSize)+"b\n*", result.Drawing)
+		assert.Equal(t, createdAt, result.CreatedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when the canvas does not exist, should return not found error", func(t *testing.T) {
+		repository, mock := setup()
+		mock.ExpectQuery(selectCanvas).WithArgs("123", "").WillReturnError(sql.ErrNoRows)
+
+		result, err := repository.GetByID(context.Background(), "123")
+
+		assert.Empty(t, result)
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+	})
+}
+
+func TestChunkedRepository_GetViewport(t *testing.T) {
+	const (
+		selectCanvas = "select id, owner_id, workspace_id, width, height, version, created_at from chunked_drawings where id = $1 and workspace_id = $2"
+		selectChunks = "select chunk_x, chunk_y, content from drawing_chunks " +
+			"where drawing_id = $1 and chunk_x between $2 and $3 and chunk_y between $4 and $5"
+	)
+	mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	repository := canvas.NewChunkedRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	mock.ExpectQuery(selectCanvas).
+		WithArgs("123", "").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "width", "height", "created_at"}).
+			AddRow("123", 200, 2, time.Now()))
+	mock.ExpectQuery(selectChunks).
+		WithArgs("123", 1, 2, 0, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"chunk_x", "chunk_y", "content"}).
+			AddRow(1, 0, strings.Repeat("a", canvas.ChunkSize)).
+			AddRow(2, 0, "bc"))
+
+	viewport := canvas.Viewport{X: 2*canvas.ChunkSize - 1, Y: 0, Width: 3, Height: 1}
+	result, err := repository.GetViewport(context.Background(), "123", viewport)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", result.Drawing)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
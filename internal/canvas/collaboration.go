@@ -0,0 +1,287 @@
+package canvas
+
+import (
+	"context"
+	goerrors "errors"
+	"sketch/internal/errors"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MessageDraw     MessageType = "draw"
+	MessageCursor   MessageType = "cursor"
+	MessageWelcome  MessageType = "welcome"
+	MessageUpdate   MessageType = "update"
+	MessagePresence MessageType = "presence"
+	MessageError    MessageType = "error"
+
+	// outboxSize is how many messages a collaborator may fall behind before
+	// being disconnected.
+	outboxSize = 64
+	// maxNameLength is the longest name a collaborator may show to others.
+	maxNameLength = 64
+)
+
+var (
+	ErrUnknownMessageType      = errors.Error("message type must be draw or cursor")
+	ErrInvalidCollaboratorName = errors.Error("name must have at most 64 characters")
+	ErrInvalidMessage          = errors.Error("message must be a json object with a type")
+)
+
+type (
+	MessageType string
+
+	// Presence is a connected collaborator and where its cursor is.
+	Presence struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		X    int    `json:"x"`
+		Y    int    `json:"y"`
+	}
+
+	// CollaborationMessage is sent both ways. Clients send draw and cursor
+	// messages, the server answers with the others.
+	CollaborationMessage struct {
+		Type     MessageType  `json:"type"`
+		Sequence int          `json:"sequence,omitempty"`
+		Client   string       `json:"client,omitempty"`
+		Requests DrawRequests `json:"requests,omitempty"`
+		Canvas   string       `json:"canvas,omitempty"`
+		X        int          `json:"x,omitempty"`
+		Y        int          `json:"y,omitempty"`
+		Clients  []Presence   `json:"clients,omitempty"`
+		Message  string       `json:"message,omitempty"`
+	}
+
+	Collaborator struct {
+		Presence
+		room   *room
+		send   chan CollaborationMessage
+		closed bool
+	}
+
+	// room orders the operations on a canvas: they are applied one at a
+	// time, each one over the result of the previous, and every collaborator
+	// gets the updates in the same order, numbered by sequence.
+	room struct {
+		id       string
+		service  Service
+		members  int
+		mu       sync.Mutex
+		sequence int
+		clients  []*Collaborator
+	}
+
+	// Hub keeps a room for every canvas being edited by someone. Rooms live
+	// in memory, so collaborators of a canvas must reach the same instance.
+	Hub struct {
+		service Service
+		mu      sync.Mutex
+		rooms   map[string]*room
+	}
+)
+
+func NewHub(service Service) *Hub {
+	return &Hub{
+		service: service,
+		rooms:   make(map[string]*room),
+	}
+}
+
+// Messages returns the messages to send to the collaborator. It is closed
+// when the collaborator leaves or falls too far behind.
+func (c *Collaborator) Messages() <-chan CollaborationMessage {
+	return c.send
+}
+
+// Join adds a collaborator to the room of the canvas. Its first message is a
+// welcome with the current canvas and who else is there.
+func (h *Hub) Join(ctx context.Context, id string, name string) (*Collaborator, error) {
+	r := h.acquire(id)
+	collaborator, err := r.join(ctx, name)
+	if err != nil {
+		h.release(r)
+		return nil, err
+	}
+	return collaborator, nil
+}
+
+func (h *Hub) Leave(collaborator *Collaborator) {
+	collaborator.room.leave(collaborator)
+	h.release(collaborator.room)
+}
+
+// Receive handles a message of the collaborator. Errors are sent back to it
+// only.
+func (h *Hub) Receive(ctx context.Context, collaborator *Collaborator, message CollaborationMessage) {
+	r := collaborator.room
+	switch message.Type {
+	case MessageDraw:
+		if err := message.Requests.Validate(); err != nil {
+			h.Reply(collaborator, err)
+			return
+		}
+		r.apply(ctx, collaborator, message.Requests)
+	case MessageCursor:
+		r.cursor(collaborator, message.X, message.Y)
+	default:
+		h.Reply(collaborator, ErrUnknownMessageType)
+	}
+}
+
+// Reply sends the error to the collaborator.
+func (h *Hub) Reply(collaborator *Collaborator, err error) {
+	r := collaborator.room
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliver(collaborator, CollaborationMessage{Type: MessageError, Message: errorMessage(err)})
+}
+
+func (h *Hub) acquire(id string) *room {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r, ok := h.rooms[id]
+	if !ok {
+		r = &room{id: id, service: h.service}
+		h.rooms[id] = r
+	}
+	r.members++
+	return r
+}
+
+func (h *Hub) release(r *room) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r.members--
+	if r.members == 0 {
+		delete(h.rooms, r.id)
+	}
+}
+
+func (r *room) join(ctx context.Context, name string) (*Collaborator, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	canvas, err := r.service.GetByID(ctx, r.id)
+	if err != nil {
+		return nil, err
+	}
+
+	collaborator := &Collaborator{
+		Presence: Presence{ID: uuid.New().String(), Name: name},
+		room:     r,
+		send:     make(chan CollaborationMessage, outboxSize),
+	}
+	// The others learn about the new collaborator from a presence message, and
+	// it learns about them from the welcome.
+	others := r.clients
+	r.clients = append(r.clients, collaborator)
+	presence := r.presence()
+	for _, other := range others {
+		r.deliver(other, CollaborationMessage{Type: MessagePresence, Clients: presence})
+	}
+	r.deliver(collaborator, CollaborationMessage{
+		Type:     MessageWelcome,
+		Sequence: r.sequence,
+		Client:   collaborator.ID,
+		Canvas:   canvas.Drawing,
+		Clients:  presence,
+	})
+	return collaborator, nil
+}
+
+func (r *room) leave(collaborator *Collaborator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.remove(collaborator) {
+		r.broadcastPresence()
+	}
+}
+
+func (r *room) apply(ctx context.Context, collaborator *Collaborator, requests DrawRequests) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if collaborator.closed {
+		return
+	}
+
+	response, err := r.service.Edit(ctx, r.id, requests)
+	if err != nil {
+		r.deliver(collaborator, CollaborationMessage{Type: MessageError, Message: errorMessage(err)})
+		return
+	}
+
+	r.sequence++
+	r.broadcast(CollaborationMessage{
+		Type:     MessageUpdate,
+		Sequence: r.sequence,
+		Client:   collaborator.ID,
+		Requests: requests,
+		Canvas:   response.Drawing,
+	})
+}
+
+func (r *room) cursor(collaborator *Collaborator, x, y int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if collaborator.closed {
+		return
+	}
+	collaborator.X, collaborator.Y = x, y
+	r.broadcastPresence()
+}
+
+func (r *room) broadcastPresence() {
+	r.broadcast(CollaborationMessage{Type: MessagePresence, Clients: r.presence()})
+}
+
+func (r *room) broadcast(message CollaborationMessage) {
+	for _, collaborator := range append([]*Collaborator(nil), r.clients...) {
+		r.deliver(collaborator, message)
+	}
+}
+
+// deliver queues the message without blocking the room. A collaborator whose
+// queue is full is removed, as it could no longer follow the sequence.
+func (r *room) deliver(collaborator *Collaborator, message CollaborationMessage) {
+	if collaborator.closed {
+		return
+	}
+
+	select {
+	case collaborator.send <- message:
+	default:
+		r.remove(collaborator)
+	}
+}
+
+func (r *room) remove(collaborator *Collaborator) bool {
+	for i, current := range r.clients {
+		if current == collaborator {
+			r.clients = append(r.clients[:i], r.clients[i+1:]...)
+			collaborator.closed = true
+			close(collaborator.send)
+			return true
+		}
+	}
+	return false
+}
+
+func (r *room) presence() []Presence {
+	presence := make([]Presence, 0, len(r.clients))
+	for _, collaborator := range r.clients {
+		presence = append(presence, collaborator.Presence)
+	}
+	return presence
+}
+
+// errorMessage hides the details of unexpected errors, as the router does.
+func errorMessage(err error) string {
+	var businessErr errors.Error
+	if goerrors.As(err, &businessErr) {
+		return err.Error()
+	}
+	return "failed to process the message"
+}
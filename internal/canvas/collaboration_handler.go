@@ -0,0 +1,116 @@
+package canvas
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"sketch/internal/ratelimit"
+	"sketch/internal/routing"
+	"sketch/internal/websocket"
+	"time"
+	"unicode/utf8"
+)
+
+// pingInterval is how often the websockets are pinged. Those that answer
+// nothing for two of them are closed, and their collaborators leave.
+const pingInterval = 30 * time.Second
+
+type CollaborationHandler struct {
+	hub *Hub
+	// renders limits the draws sent over the websockets as those sent in
+	// requests.
+	renders *ratelimit.Limiter
+	// allowedOrigins are the pages of other sites allowed to open the
+	// websockets.
+	allowedOrigins []string
+}
+
+func NewCollaborationHandler(hub *Hub, renders *ratelimit.Limiter, allowedOrigins []string) *CollaborationHandler {
+	return &CollaborationHandler{
+		hub:            hub,
+		renders:        renders,
+		allowedOrigins: allowedOrigins,
+	}
+}
+
+// Collaborate upgrades the request to a websocket joined to the room of the
+// canvas. Every message is a json CollaborationMessage: clients send draw
+// and cursor messages and receive the welcome, the numbered updates, the
+// presence of the others and the errors of their own messages. Draws take
+// the area of the canvas they draw from the render limit of the client.
+func (c *CollaborationHandler) Collaborate(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		name = "anonymous"
+	}
+	if utf8.RuneCountInString(name) > maxNameLength {
+		return ErrInvalidCollaboratorName
+	}
+
+	collaborator, err := c.hub.Join(r.Context(), params.ByName("id"), name)
+
+	if errors.Is(err, ErrNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	conn, err := websocket.Upgrade(w, r, c.allowedOrigins)
+	if errors.Is(err, websocket.ErrForbiddenOrigin) {
+		c.hub.Leave(collaborator)
+		return routing.Forbidden(w, err)
+	}
+
+	if err != nil {
+		c.hub.Leave(collaborator)
+		return err
+	}
+
+	conn.KeepAlive(pingInterval)
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		write(conn, collaborator.Messages())
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+
+		var message CollaborationMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			c.hub.Reply(collaborator, ErrInvalidMessage)
+			continue
+		}
+
+		if message.Type == MessageDraw && !ratelimit.Allow(c.renders, r, renderCost(message.Requests)) {
+			c.hub.Reply(collaborator, ratelimit.ErrRateLimited)
+			continue
+		}
+		c.hub.Receive(r.Context(), collaborator, message)
+	}
+
+	c.hub.Leave(collaborator)
+	<-written
+	return nil
+}
+
+// write sends the messages until there are no more, then closes the
+// connection.
+func write(conn *websocket.Conn, messages <-chan CollaborationMessage) {
+	defer conn.Close()
+	for message := range messages {
+		data, err := json.Marshal(message)
+		if err != nil {
+			return
+		}
+		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			return
+		}
+	}
+}
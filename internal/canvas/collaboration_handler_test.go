@@ -0,0 +1,96 @@
+package canvas_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"sketch/internal/ratelimit"
+	"sketch/internal/websocket"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollaborationHandler_Collaborate(t *testing.T) {
+	newServer := func(t *testing.T, service canvas.Service, renders *ratelimit.Limiter) string {
+		handler := canvas.NewCollaborationHandler(canvas.NewHub(service), renders, nil)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			params := httprouter.Params{{Key: "id", Value: "id"}}
+			if err := handler.Collaborate(w, r, params); err != nil && w.Header().Get("Content-Type") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+		t.Cleanup(server.Close)
+		return "ws" + strings.TrimPrefix(server.URL, "http") + "/id/collaborate?name=ana"
+	}
+
+	read := func(t *testing.T, conn *websocket.Conn) canvas.CollaborationMessage {
+		t.Helper()
+		_, data, err := conn.ReadMessage()
+		assert.NoError(t, err)
+		var message canvas.CollaborationMessage
+		assert.NoError(t, json.Unmarshal(data, &message))
+		return message
+	}
+
+	t.Run("when drawing over the socket, should receive the update", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockService(ctrl)
+		serviceMock.EXPECT().GetByID(gomock.Any(), "id").Return(&canvas.Canvas{ID: "id", Drawing: "ab"}, nil)
+		serviceMock.EXPECT().Edit(gomock.Any(), "id", gomock.Len(1)).Return(&canvas.DrawResponse{ID: "id", Drawing: "cb"}, nil)
+
+		conn, err := websocket.Dial(newServer(t, serviceMock, nil))
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		welcome := read(t, conn)
+		assert.Equal(t, canvas.MessageWelcome, welcome.Type)
+		assert.Equal(t, "ana", welcome.Clients[0].Name)
+
+		draw := `{"type": "draw", "requests": [{"type": "text", "x": 0, "y": 0, "text": "c"}]}`
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(draw)))
+		update := read(t, conn)
+		assert.Equal(t, canvas.MessageUpdate, update.Type)
+		assert.Equal(t, "cb", update.Canvas)
+
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("nope")))
+		assert.Equal(t, canvas.ErrInvalidMessage.Error(), read(t, conn).Message)
+	})
+
+	t.Run("when the client is out of render tokens, should not draw", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockService(ctrl)
+		serviceMock.EXPECT().GetByID(gomock.Any(), "id").Return(&canvas.Canvas{ID: "id", Drawing: "ab"}, nil)
+		serviceMock.EXPECT().Edit(gomock.Any(), "id", gomock.Len(1)).Times(1).Return(&canvas.DrawResponse{ID: "id", Drawing: "cb"}, nil)
+
+		conn, err := websocket.Dial(newServer(t, serviceMock, ratelimit.NewLimiter(ratelimit.PerMinute(1))))
+		assert.NoError(t, err)
+		defer conn.Close()
+		read(t, conn)
+
+		draw := `{"type": "draw", "requests": [{"type": "text", "x": 0, "y": 0, "text": "c"}]}`
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(draw)))
+		assert.Equal(t, canvas.MessageUpdate, read(t, conn).Type)
+
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(draw)))
+		refused := read(t, conn)
+		assert.Equal(t, canvas.MessageError, refused.Type)
+		assert.Equal(t, ratelimit.ErrRateLimited.Error(), refused.Message)
+	})
+
+	t.Run("when the canvas does not exist, should refuse the handshake", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockService(ctrl)
+		serviceMock.EXPECT().GetByID(gomock.Any(), "id").Return(nil, canvas.ErrNotFound)
+
+		conn, err := websocket.Dial(newServer(t, serviceMock, nil))
+
+		assert.ErrorIs(t, err, websocket.ErrHandshakeFailed)
+		assert.Nil(t, conn)
+	})
+}
@@ -0,0 +1,134 @@
+package canvas_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHub_Join(t *testing.T) {
+	t.Run("when the canvas exists, should welcome the collaborator and tell the others", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockService(ctrl)
+		serviceMock.EXPECT().GetByID(gomock.Any(), "id").
+			Times(2).
+			Return(&canvas.Canvas{ID: "id", Drawing: "ab"}, nil)
+		hub := canvas.NewHub(serviceMock)
+		ctx := context.Background()
+
+		ana, err := hub.Join(ctx, "id", "ana")
+		assert.NoError(t, err)
+		bob, err := hub.Join(ctx, "id", "bob")
+		assert.NoError(t, err)
+
+		welcome := <-bob.Messages()
+		assert.Equal(t, canvas.MessageWelcome, welcome.Type)
+		assert.Equal(t, "ab", welcome.Canvas)
+		assert.Equal(t, []canvas.Presence{ana.Presence, bob.Presence}, welcome.Clients)
+
+		<-ana.Messages()
+		presence := <-ana.Messages()
+		assert.Equal(t, canvas.MessagePresence, presence.Type)
+		assert.Len(t, presence.Clients, 2)
+	})
+
+	t.Run("when the canvas does not exist, should return not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockService(ctrl)
+		serviceMock.EXPECT().GetByID(gomock.Any(), "id").Return(nil, canvas.ErrNotFound)
+
+		collaborator, err := canvas.NewHub(serviceMock).Join(context.Background(), "id", "ana")
+
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+		assert.Nil(t, collaborator)
+	})
+}
+
+func TestHub_Receive(t *testing.T) {
+	requests := canvas.DrawRequests{{Type: canvas.OperationText, X: 0, Y: 0, Text: "c"}}
+
+	setup := func(t *testing.T) (*canvas.Hub, *mock_canvas.MockService, *canvas.Collaborator, *canvas.Collaborator) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockService(ctrl)
+		serviceMock.EXPECT().GetByID(gomock.Any(), "id").
+			AnyTimes().
+			Return(&canvas.Canvas{ID: "id", Drawing: "ab"}, nil)
+		hub := canvas.NewHub(serviceMock)
+
+		ana, _ := hub.Join(context.Background(), "id", "ana")
+		bob, _ := hub.Join(context.Background(), "id", "bob")
+		<-ana.Messages()
+		<-ana.Messages()
+		<-bob.Messages()
+		return hub, serviceMock, ana, bob
+	}
+
+	t.Run("when drawing, should apply the requests and send the update to everyone in order", func(t *testing.T) {
+		hub, serviceMock, ana, bob := setup(t)
+		gomock.InOrder(
+			serviceMock.EXPECT().Edit(gomock.Any(), "id", requests).Return(&canvas.DrawResponse{ID: "id", Drawing: "cb"}, nil),
+			serviceMock.EXPECT().Edit(gomock.Any(), "id", requests).Return(&canvas.DrawResponse{ID: "id", Drawing: "cb"}, nil),
+		)
+
+		hub.Receive(context.Background(), ana, canvas.CollaborationMessage{Type: canvas.MessageDraw, Requests: requests})
+		hub.Receive(context.Background(), bob, canvas.CollaborationMessage{Type: canvas.MessageDraw, Requests: requests})
+
+		for _, collaborator := range []*canvas.Collaborator{ana, bob} {
+			first, second := <-collaborator.Messages(), <-collaborator.Messages()
+			assert.Equal(t, canvas.MessageUpdate, first.Type)
+			assert.Equal(t, 1, first.Sequence)
+			assert.Equal(t, ana.ID, first.Client)
+			assert.Equal(t, "cb", first.Canvas)
+			assert.Equal(t, 2, second.Sequence)
+			assert.Equal(t, bob.ID, second.Client)
+		}
+	})
+
+	t.Run("when the requests are invalid, should send the error only to the sender", func(t *testing.T) {
+		hub, _, ana, bob := setup(t)
+
+		hub.Receive(context.Background(), ana, canvas.CollaborationMessage{Type: canvas.MessageDraw})
+
+		message := <-ana.Messages()
+		assert.Equal(t, canvas.MessageError, message.Type)
+		assert.Equal(t, canvas.ErrEmptyRequests.Error(), message.Message)
+		assert.Len(t, bob.Messages(), 0)
+	})
+
+	t.Run("when the edit fails unexpectedly, should hide the error", func(t *testing.T) {
+		hub, serviceMock, ana, _ := setup(t)
+		serviceMock.EXPECT().Edit(gomock.Any(), "id", requests).Return(nil, fmt.Errorf("database err: %w", sql.ErrConnDone))
+
+		hub.Receive(context.Background(), ana, canvas.CollaborationMessage{Type: canvas.MessageDraw, Requests: requests})
+
+		message := <-ana.Messages()
+		assert.Equal(t, "failed to process the message", message.Message)
+	})
+
+	t.Run("when the cursor moves, should send the presence to everyone", func(t *testing.T) {
+		hub, _, ana, bob := setup(t)
+
+		hub.Receive(context.Background(), ana, canvas.CollaborationMessage{Type: canvas.MessageCursor, X: 3, Y: 4})
+
+		message := <-bob.Messages()
+		assert.Equal(t, canvas.MessagePresence, message.Type)
+		assert.Equal(t, canvas.Presence{ID: ana.ID, Name: "ana", X: 3, Y: 4}, message.Clients[0])
+	})
+
+	t.Run("when a collaborator leaves, should close its messages and tell the others", func(t *testing.T) {
+		hub, _, ana, bob := setup(t)
+
+		hub.Leave(ana)
+
+		_, open := <-ana.Messages()
+		assert.False(t, open)
+		message := <-bob.Messages()
+		assert.Equal(t, []canvas.Presence{bob.Presence}, message.Clients)
+	})
+}
@@ -1,21 +1,50 @@
 package canvas
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sketch/internal/banner"
 	"sketch/internal/errors"
 	"sketch/internal/text"
 	"strings"
+	"unicode/utf8"
+)
+
+const (
+	OperationRectangle OperationType = "rectangle"
+	OperationText      OperationType = "text"
+	OperationSymbol    OperationType = "symbol"
+	OperationLine      OperationType = "line"
+	OperationBanner    OperationType = "banner"
+	OperationChart     OperationType = "chart"
+	OperationTable     OperationType = "table"
 )
 
 type (
 	Draw [][]string
 
+	// OperationType tells which shape a DrawRequest draws. Requests without a
+	// type are rectangles.
+	OperationType string
+
 	DrawRequest struct {
+		Type    OperationType  `json:"type,omitempty"`
 		X       int            `json:"x" validate:"required"`
 		Y       int            `json:"y" validate:"required"`
 		Width   int            `json:"width" validate:"required"`
 		Height  int            `json:"height" validate:"required"`
 		Outline text.ASCIIChar `json:"outline"`
 		Fill    text.ASCIIChar `json:"fill"`
+		Text    string         `json:"text,omitempty"`
+		Symbol  string         `json:"symbol,omitempty"`
+		Linked  bool           `json:"linked,omitempty"`
+		ToX     int            `json:"to_x,omitempty"`
+		ToY     int            `json:"to_y,omitempty"`
+		Font    string         `json:"font,omitempty"`
+		Layout  banner.Layout  `json:"layout,omitempty"`
+		Chart   *Chart         `json:"chart,omitempty"`
+		Table   *Table         `json:"table,omitempty"`
 	}
 
 	DrawRequests []DrawRequest
@@ -42,7 +71,10 @@ func (d Draw) String() string {
 }
 
 var (
-	ErrEmptyRequests = errors.Error("at least one request is required")
+	ErrEmptyRequests        = errors.Error("at least one request is required")
+	ErrUnknownOperationType = errors.Error("unknown operation type")
+	ErrNegativeCoordinates  = errors.Error("coordinates must be equal or greater than zero")
+	ErrInvalidLayout        = errors.Error("layout must be full, kerning or smushing")
 )
 
 func NewDraw(width, height int) Draw {
@@ -53,6 +85,74 @@ func NewDraw(width, height int) Draw {
 	return draw
 }
 
+// ParseDraw splits a rendered drawing back into its grid, one cell per rune.
+func ParseDraw(drawing string) Draw {
+	lines := strings.Split(drawing, "\n")
+	draw := make(Draw, len(lines))
+	for i, line := range lines {
+		draw[i] = make([]string, 0, len(line))
+		for _, char := range line {
+			draw[i] = append(draw[i], string(char))
+		}
+	}
+	return draw
+}
+
+// Crop returns the rectangle described by the viewport. Cells outside the
+// drawing are padded, so the rectangle always has the size of the viewport.
+func (d Draw) Crop(viewport Viewport) Draw {
+	return d.crop(viewport, paddingChar)
+}
+
+// crop returns the rectangle described by the viewport, with the cells
+// outside the drawing set to padding.
+func (d Draw) crop(viewport Viewport, padding string) Draw {
+	cropped := NewDraw(viewport.Width, viewport.Height)
+	for row := 0; row < viewport.Height; row++ {
+		sourceRow := viewport.Y + row
+		for column := 0; column < viewport.Width; column++ {
+			sourceColumn := viewport.X + column
+			if sourceRow < len(d) && sourceColumn < len(d[sourceRow]) && d[sourceRow][sourceColumn] != "" {
+				cropped[row][column] = d[sourceRow][sourceColumn]
+				continue
+			}
+			cropped[row][column] = padding
+		}
+	}
+	return cropped
+}
+
+// Size returns the widest row and the number of rows of the drawing.
+func (d Draw) Size() (int, int) {
+	width := 0
+	for _, row := range d {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	return width, len(d)
+}
+
+// Set writes the cell at the given column and row, padding the empty cells on
+// its left so the row still renders aligned.
+func (d Draw) Set(column, row int, value string) {
+	for i := column - 1; i >= 0 && d[row][i] == ""; i-- {
+		d[row][i] = paddingChar
+	}
+	d[row][column] = value
+}
+
+func (d Draw) IsEmpty() bool {
+	for _, row := range d {
+		for _, cell := range row {
+			if cell != "" {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func (d DrawRequests) Validate() error {
 	if len(d) == 0 {
 		return ErrEmptyRequests
@@ -67,6 +167,23 @@ func (d DrawRequests) Validate() error {
 	return nil
 }
 
+func (d DrawRequests) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+func (d *DrawRequests) Scan(src any) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, d)
+	case string:
+		return json.Unmarshal([]byte(value), d)
+	case nil:
+		*d = nil
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into draw requests", src)
+}
+
 func (d DrawRequest) GetFillChar() string {
 	if d.Fill == EmptyChar {
 		return " "
@@ -74,6 +191,24 @@ func (d DrawRequest) GetFillChar() string {
 	return string(d.Fill)
 }
 
+// GetLineChar returns the outline, or a character following the direction of
+// the line when no outline was informed.
+func (d DrawRequest) GetLineChar() string {
+	if d.Outline != "" && d.Outline != EmptyChar {
+		return string(d.Outline)
+	}
+
+	switch {
+	case d.Y == d.ToY:
+		return "-"
+	case d.X == d.ToX:
+		return "|"
+	case (d.ToX > d.X) == (d.ToY > d.Y):
+		return "\\"
+	}
+	return "/"
+}
+
 func (d DrawRequest) GetOutlineChar() string {
 	if d.Outline == EmptyChar {
 		return ""
@@ -81,12 +216,108 @@ func (d DrawRequest) GetOutlineChar() string {
 	return string(d.Outline)
 }
 
+// Lines returns the rows written by text, banner and table operations.
+func (d DrawRequest) Lines() []string {
+	switch d.Type {
+	case OperationBanner:
+		font, err := banner.Load(d.Font)
+		if err != nil {
+			return nil
+		}
+		return font.Render(d.Text, d.Layout)
+	case OperationTable:
+		return d.Table.Render()
+	}
+	return strings.Split(d.Text, "\n")
+}
+
+// Size returns the area covered by the request. Text, banner and table
+// operations are as wide as their longest line and lines reach their farthest
+// end point.
+func (d DrawRequest) Size() (int, int) {
+	if d.Type == OperationLine {
+		width, height := 1, 1
+		if d.ToX > d.X {
+			width = d.ToX - d.X + 1
+		}
+		if d.ToY > d.Y {
+			height = d.ToY - d.Y + 1
+		}
+		return width, height
+	}
+
+	switch d.Type {
+	case OperationText, OperationBanner, OperationTable:
+	default:
+		return d.Width, d.Height
+	}
+
+	lines := d.Lines()
+	width := 0
+	for _, line := range lines {
+		if length := utf8.RuneCountInString(line); length > width {
+			width = length
+		}
+	}
+	return width, len(lines)
+}
+
+// Size returns the size of the canvas drawing all the requests.
+func (d DrawRequests) Size() (int, int) {
+	width, height := 0, 0
+	for _, request := range d {
+		if end := request.WidthEnd(); end > width {
+			width = end
+		}
+		if end := request.HeightEnd(); end > height {
+			height = end
+		}
+	}
+	return width, height
+}
+
+// Bounds returns at least the size of the canvas drawing all the requests,
+// from their fields alone: banners take their characters at full width and
+// nothing is rendered.
+func (d DrawRequests) Bounds() (int, int) {
+	width, height := 0, 0
+	for _, request := range d {
+		requestWidth, requestHeight := request.bounds()
+		if end := request.X + requestWidth; end > width {
+			width = end
+		}
+		if end := request.Y + requestHeight; end > height {
+			height = end
+		}
+	}
+	return width, height
+}
+
+func (d DrawRequest) bounds() (int, int) {
+	switch d.Type {
+	case OperationBanner:
+		font, err := banner.Load(d.Font)
+		if err != nil {
+			return 0, 0
+		}
+		return font.Width(d.Text), font.Height
+	case OperationTable:
+		if d.Table == nil {
+			return 0, 0
+		}
+		return d.Table.Size()
+	}
+	return d.Size()
+}
+
 func (d DrawRequest) WidthEnd() int {
-	return d.X + d.Width
+	width, _ := d.Size()
+	return d.X + width
 }
 
 func (d DrawRequest) HeightEnd() int {
-	return d.Y + d.Height
+	_, height := d.Size()
+	return d.Y + height
 }
 
 func (d DrawRequest) IsLastRow(row int) bool {
@@ -102,6 +333,79 @@ func (d DrawRequest) IsLateralOutline(column int) bool {
 }
 
 func (d DrawRequest) Validate() error {
+	if d.X < 0 || d.Y < 0 {
+		return ErrNegativeCoordinates
+	}
+
+	switch d.Type {
+	case "", OperationRectangle:
+		return d.validateRectangle()
+	case OperationText:
+		if d.Text == "" {
+			return errors.Error("text operations require a text")
+		}
+		return nil
+	case OperationSymbol:
+		if d.Symbol == "" {
+			return errors.Error("symbol operations require a symbol name")
+		}
+		return nil
+	case OperationBanner:
+		return d.validateBanner()
+	case OperationChart:
+		return d.validateChart()
+	case OperationTable:
+		if d.Table == nil {
+			return ErrMissingTable
+		}
+		return d.Table.Validate()
+	case OperationLine:
+		if d.ToX < 0 || d.ToY < 0 {
+			return ErrNegativeCoordinates
+		}
+		return d.Outline.Validate()
+	}
+
+	return ErrUnknownOperationType
+}
+
+func (d DrawRequest) validateBanner() error {
+	if d.Text == "" {
+		return errors.Error("banner operations require a text")
+	}
+
+	if strings.ContainsRune(d.Text, '\n') {
+		return errors.Error("banner text must be a single line")
+	}
+
+	if _, err := banner.Load(d.Font); err != nil {
+		return err
+	}
+
+	if !d.Layout.IsValid() {
+		return ErrInvalidLayout
+	}
+
+	return nil
+}
+
+func (d DrawRequest) validateChart() error {
+	if d.Chart == nil {
+		return ErrMissingChart
+	}
+
+	if err := d.Fill.Validate(); err != nil && d.Fill != EmptyChar {
+		return err
+	}
+
+	if d.Width <= 0 || d.Height <= 0 {
+		return errors.Error("width and height must be equal or greater than zero")
+	}
+
+	return d.Chart.Validate(d.Width, d.Height)
+}
+
+func (d DrawRequest) validateRectangle() error {
 	isEmpty := func(value text.ASCIIChar) bool {
 		return value == "" || value == EmptyChar
 	}
@@ -110,18 +414,14 @@ func (d DrawRequest) Validate() error {
 		return errors.Error("at least one value must be informed to fill or outline")
 	}
 
-	if err := d.Fill.Validate(); err != nil {
+	if err := d.Fill.Validate(); err != nil && d.Fill != EmptyChar {
 		return err
 	}
 
-	if err := d.Outline.Validate(); err != nil {
+	if err := d.Outline.Validate(); err != nil && d.Outline != EmptyChar {
 		return err
 	}
 
-	if d.X < 0 || d.Y < 0 {
-		return errors.Error("coordinates must be equal or greater than zero")
-	}
-
 	if d.Width <= 0 || d.Height <= 0 {
 		return errors.Error("width and height must be equal or greater than zero")
 	}
@@ -1,6 +1,7 @@
 package canvas_test
 
 import (
+	"sketch/internal/banner"
 	"sketch/internal/canvas"
 	"sketch/internal/text"
 	"sketch/tests/faker"
@@ -116,6 +117,18 @@ func TestDrawRequest_Validate(t *testing.T) {
 				assert.ErrorContains(t, err, "coordinates must be equal or greater than zero")
 			},
 		},
+		{
+			name: "when fill is 'none', should return no error",
+			fields: fields{
+				Width:   3,
+				Height:  3,
+				Outline: "a",
+				Fill:    canvas.EmptyChar,
+			},
+			assert: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
 		{
 			name: "when all fields are valid, should return no error",
 			fields: fields{
@@ -148,6 +161,56 @@ func TestDrawRequest_Validate(t *testing.T) {
 	}
 }
 
+func TestDrawRequest_ValidateBanner(t *testing.T) {
+	tests := []struct {
+		name    string
+		request canvas.DrawRequest
+		assert  func(t *testing.T, err error)
+	}{
+		{
+			name:    "when the text is empty, should return an error",
+			request: canvas.DrawRequest{Type: canvas.OperationBanner},
+			assert: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, "require a text")
+			},
+		},
+		{
+			name:    "when the text has many lines, should return an error",
+			request: canvas.DrawRequest{Type: canvas.OperationBanner, Text: "a\nb"},
+			assert: func(t *testing.T, err error) {
+				assert.ErrorContains(t, err, "single line")
+			},
+		},
+		{
+			name:    "when the font is unknown, should return an error",
+			request: canvas.DrawRequest{Type: canvas.OperationBanner, Text: "a", Font: "comic-sans"},
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, banner.ErrUnknownFont)
+			},
+		},
+		{
+			name:    "when the layout is unknown, should return an error",
+			request: canvas.DrawRequest{Type: canvas.OperationBanner, Text: "a", Layout: "fitted"},
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, canvas.ErrInvalidLayout)
+			},
+		},
+		{
+			name:    "when font and layout are valid, should return no error",
+			request: canvas.DrawRequest{Type: canvas.OperationBanner, Text: "a", Font: "block", Layout: banner.LayoutSmushing},
+			assert: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assert(t, tt.request.Validate())
+		})
+	}
+}
+
 func TestDrawRequests_Validate(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -319,3 +382,42 @@ func TestDrawRequest_IsLateralOutline(t *testing.T) {
 		})
 	}
 }
+
+func TestDraw_Crop(t *testing.T) {
+	drawing := "@@@@@\n@XXX@\n@@@@@"
+
+	tests := []struct {
+		name     string
+		viewport canvas.Viewport
+		expected string
+	}{
+		{
+			name:     "when the viewport is inside the drawing, should return only that region",
+			viewport: canvas.Viewport{X: 1, Y: 1, Width: 3, Height: 2},
+			expected: "XXX\n@@@",
+		},
+		{
+			name:     "when the viewport covers the whole drawing, should return it unchanged",
+			viewport: canvas.Viewport{Width: 5, Height: 3},
+			expected: drawing,
+		},
+		{
+			name:     "when the viewport goes beyond the drawing, should pad those cells",
+			viewport: canvas.Viewport{X: 3, Y: 2, Width: 4, Height: 2},
+			expected: "@@  \n    ",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := canvas.ParseDraw(drawing).Crop(tc.viewport)
+			assert.Equal(t, tc.expected, got.String())
+		})
+	}
+}
+
+func TestParseDraw(t *testing.T) {
+	draw := canvas.ParseDraw("🔥a\nb")
+
+	assert.Equal(t, canvas.Draw{{"🔥", "a"}, {"b"}}, draw)
+}
@@ -1,6 +1,7 @@
 package canvas
 
 import (
+	"sketch/internal/errors"
 	"strings"
 
 	"github.com/labstack/gommon/log"
@@ -10,6 +11,10 @@ const (
 	paddingChar = " "
 )
 
+var (
+	ErrUnexpandedSymbol = errors.Error("symbol operations must be expanded before drawing")
+)
+
 type (
 	Drawer interface {
 		Draw(requests []DrawRequest) (string, error)
@@ -33,27 +38,106 @@ func (d drawer) Draw(requests []DrawRequest) (string, error) {
 
 	for _, request := range requests {
 		draw := NewDraw(width, height)
-		for row := request.Y; row < request.HeightEnd(); row++ {
-			for column := 0; column < request.WidthEnd(); column++ {
+		switch request.Type {
+		case OperationSymbol:
+			return "", ErrUnexpandedSymbol
+		case OperationText, OperationBanner, OperationTable:
+			d.drawText(draw, request)
+		case OperationLine:
+			d.drawLine(draw, request)
+		case OperationChart:
+			d.drawChart(draw, request)
+		default:
+			d.drawRectangle(draw, request)
+		}
 
-				if column < request.X {
-					draw[row][column] = paddingChar
-					continue
-				}
+		draws = append(draws, draw)
+	}
 
-				if canFill, outline := d.canFillOutline(row, column, request); canFill {
-					draw[row][column] = outline
-					continue
-				}
+	return d.drawToString(width, height, draws), nil
+}
 
-				draw[row][column] = request.GetFillChar()
+func (d drawer) drawText(draw Draw, request DrawRequest) {
+	for i, line := range request.Lines() {
+		column := request.X
+		for _, char := range line {
+			draw.Set(column, request.Y+i, string(char))
+			column++
+		}
+	}
+}
+
+func (d drawer) drawChart(draw Draw, request DrawRequest) {
+	chart := request.Chart.Render(request.Width, request.Height, string(request.Fill))
+	for row, cells := range chart {
+		for column, cell := range cells {
+			if cell != "" {
+				draw.Set(request.X+column, request.Y+row, cell)
 			}
 		}
+	}
+}
 
-		draws = append(draws, draw)
+// drawLine rasterizes the segment with Bresenham's algorithm.
+func (d drawer) drawLine(draw Draw, request DrawRequest) {
+	char := request.GetLineChar()
+	x, y := request.X, request.Y
+	dx, dy := abs(request.ToX-x), -abs(request.ToY-y)
+	stepX, stepY := sign(request.ToX-x), sign(request.ToY-y)
+	err := dx + dy
+
+	for {
+		draw.Set(x, y, char)
+		if x == request.ToX && y == request.ToY {
+			return
+		}
+
+		doubled := 2 * err
+		if doubled >= dy {
+			err += dy
+			x += stepX
+		}
+		if doubled <= dx {
+			err += dx
+			y += stepY
+		}
 	}
+}
 
-	return d.drawToString(width, height, draws), nil
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+func sign(value int) int {
+	switch {
+	case value > 0:
+		return 1
+	case value < 0:
+		return -1
+	}
+	return 0
+}
+
+func (d drawer) drawRectangle(draw Draw, request DrawRequest) {
+	for row := request.Y; row < request.HeightEnd(); row++ {
+		for column := 0; column < request.WidthEnd(); column++ {
+
+			if column < request.X {
+				draw[row][column] = paddingChar
+				continue
+			}
+
+			if canFill, outline := d.canFillOutline(row, column, request); canFill {
+				draw[row][column] = outline
+				continue
+			}
+
+			draw[row][column] = request.GetFillChar()
+		}
+	}
 }
 
 func (d drawer) canFillOutline(row, column int, request DrawRequest) (bool, string) {
@@ -105,18 +189,5 @@ func (d drawer) joinDraws(width int, height int, draws []Draw) Draw {
 }
 
 func (d drawer) getCanvasDimension(requests []DrawRequest) (int, int) {
-	width := 0
-	height := 0
-
-	for _, request := range requests {
-		currentWidth := request.X + request.Width
-		if currentWidth > width {
-			width = currentWidth
-		}
-		if request.HeightEnd() > height {
-			height = request.HeightEnd()
-		}
-	}
-
-	return width, height
+	return DrawRequests(requests).Size()
 }
@@ -1,6 +1,7 @@
 package canvas_test
 
 import (
+	"sketch/internal/banner"
 	"sketch/internal/canvas"
 	"testing"
 
@@ -287,3 +288,112 @@ func TestDrawer_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestDrawer_DrawText(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+		requests []canvas.DrawRequest
+	}{
+		{
+			name:     "text alone, should be padded to its position",
+			expected: "\n  hi\n  yo",
+			requests: []canvas.DrawRequest{
+				{Type: canvas.OperationText, X: 2, Y: 1, Text: "hi\nyo"},
+			},
+		},
+		{
+			name:     "text over a rectangle, should keep the rectangle where the text has spaces",
+			expected: "@@@@@\n@a b@\n@@@@@",
+			requests: []canvas.DrawRequest{
+				{Width: 5, Height: 3, Outline: "@", Fill: "none"},
+				{Type: canvas.OperationText, X: 1, Y: 1, Text: "a b"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			drawer := canvas.NewDrawer()
+			got, err := drawer.Draw(tc.requests)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+
+	t.Run("symbol operations, should return an error", func(t *testing.T) {
+		drawer := canvas.NewDrawer()
+		_, err := drawer.Draw([]canvas.DrawRequest{{Type: canvas.OperationSymbol, Symbol: "server"}})
+
+		assert.ErrorIs(t, err, canvas.ErrUnexpandedSymbol)
+	})
+}
+
+func TestDrawer_DrawLine(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+		request  canvas.DrawRequest
+	}{
+		{
+			name:     "horizontal line without char, should use dashes",
+			expected: " ---",
+			request:  canvas.DrawRequest{Type: canvas.OperationLine, X: 1, ToX: 3},
+		},
+		{
+			name:     "vertical line drawn upwards, should use pipes",
+			expected: "|\n|\n|",
+			request:  canvas.DrawRequest{Type: canvas.OperationLine, Y: 2, ToY: 0},
+		},
+		{
+			name:     "diagonal line with a char, should use it",
+			expected: "*\n *\n  *",
+			request:  canvas.DrawRequest{Type: canvas.OperationLine, ToX: 2, ToY: 2, Outline: "*"},
+		},
+		{
+			name:     "diagonal line going up, should use slashes",
+			expected: "  /\n /\n/",
+			request:  canvas.DrawRequest{Type: canvas.OperationLine, Y: 2, ToX: 2, ToY: 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			drawer := canvas.NewDrawer()
+			got, err := drawer.Draw([]canvas.DrawRequest{tc.request})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestDrawer_DrawBanner(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+		request  canvas.DrawRequest
+	}{
+		{
+			name:     "default font and layout, should kern the characters",
+			expected: "\n # ####\n # # #\n ### #\n # # #\n # ####",
+			request:  canvas.DrawRequest{Type: canvas.OperationBanner, X: 1, Y: 1, Text: "Hi"},
+		},
+		{
+			name:     "full width, should keep the spacing of the font",
+			expected: "# # ###\n# #  #\n###  #\n# #  #\n# # ###",
+			request:  canvas.DrawRequest{Type: canvas.OperationBanner, Text: "Hi", Layout: banner.LayoutFull},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			drawer := canvas.NewDrawer()
+			got, err := drawer.Draw([]canvas.DrawRequest{tc.request})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package canvas
+
+import (
+	"fmt"
+	"sketch/internal/banner"
+	"sketch/internal/errors"
+	"sketch/internal/text"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	DSLContentType = "text/x-sketch"
+)
+
+type (
+	dslToken struct {
+		value  string
+		column int
+		option bool
+	}
+
+	dslLine struct {
+		number int
+		tokens []dslToken
+	}
+
+	dslCommand struct {
+		arguments []string
+		parse     func(line dslLine) (DrawRequest, error)
+	}
+)
+
+// ParseDSL reads the plain-text drawing language, one operation per line:
+//
+//	# comments start with a hash
+//	rect 10 2 5 3 outline=@ fill=.
+//	text 1 1 "hello"
+//	line 0 0 10 0 char=-
+//	symbol server 4 4 linked
+//	banner 0 0 "v1.2" font=block layout=smushing
+//
+// Errors carry the line and column where they were found.
+func ParseDSL(source string) (DrawRequests, error) {
+	requests := make(DrawRequests, 0)
+	for i, raw := range strings.Split(source, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(raw), "#") {
+			continue
+		}
+
+		line, err := tokenizeDSL(i+1, raw)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(line.tokens) == 0 {
+			continue
+		}
+
+		request, err := line.parse()
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+func dslError(line, column int, format string, args ...any) error {
+	return errors.Error(fmt.Sprintf("line %d, column %d: %s", line, column, fmt.Sprintf(format, args...)))
+}
+
+func tokenizeDSL(number int, raw string) (dslLine, error) {
+	line := dslLine{number: number}
+	runes := []rune(strings.TrimRight(raw, "\r"))
+
+	for i := 0; i < len(runes); {
+		if unicode.IsSpace(runes[i]) {
+			i++
+			continue
+		}
+
+		token := dslToken{column: i + 1}
+		value := strings.Builder{}
+		for i < len(runes) && !unicode.IsSpace(runes[i]) {
+			if runes[i] != '"' {
+				token.option = token.option || runes[i] == '='
+				value.WriteRune(runes[i])
+				i++
+				continue
+			}
+
+			quoted, next, err := readQuoted(runes, i)
+			if err != nil {
+				return line, dslError(number, i+1, "%s", err.Error())
+			}
+			value.WriteString(quoted)
+			i = next
+		}
+		token.value = value.String()
+		line.tokens = append(line.tokens, token)
+	}
+	return line, nil
+}
+
+// readQuoted reads the string starting at the opening quote, returning its
+// unescaped content and the position after the closing quote.
+func readQuoted(runes []rune, start int) (string, int, error) {
+	value := strings.Builder{}
+	for i := start + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '"':
+			return value.String(), i + 1, nil
+		case '\\':
+			i++
+			if i == len(runes) {
+				break
+			}
+			switch runes[i] {
+			case 'n':
+				value.WriteRune('\n')
+			case '"', '\\':
+				value.WriteRune(runes[i])
+			default:
+				return "", 0, fmt.Errorf("unknown escape sequence '\\%c'", runes[i])
+			}
+		default:
+			value.WriteRune(runes[i])
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+func (l dslLine) parse() (DrawRequest, error) {
+	commands := map[string]dslCommand{
+		"rect":   {arguments: []string{"x", "y", "width", "height"}, parse: parseDSLRect},
+		"text":   {arguments: []string{"x", "y", "text"}, parse: parseDSLText},
+		"line":   {arguments: []string{"x1", "y1", "x2", "y2"}, parse: parseDSLLine},
+		"banner": {arguments: []string{"x", "y", "text"}, parse: parseDSLBanner},
+		"symbol": {arguments: []string{"name", "x", "y"}, parse: parseDSLSymbol},
+	}
+
+	name := l.tokens[0]
+	command, ok := commands[name.value]
+	if !ok {
+		return DrawRequest{}, dslError(l.number, name.column, "unknown command '%s'", name.value)
+	}
+
+	if given := len(l.positional()) - 1; given < len(command.arguments) {
+		missing := command.arguments[given]
+		return DrawRequest{}, dslError(l.number, l.end(), "%s expects %s, missing %s",
+			name.value, strings.Join(command.arguments, ", "), missing)
+	}
+
+	request, err := command.parse(l)
+	if err != nil {
+		return DrawRequest{}, err
+	}
+
+	if err := request.Validate(); err != nil {
+		return DrawRequest{}, dslError(l.number, name.column, "%s", err.Error())
+	}
+	return request, nil
+}
+
+// positional returns the tokens that are not key=value options.
+func (l dslLine) positional() []dslToken {
+	tokens := make([]dslToken, 0, len(l.tokens))
+	for _, token := range l.tokens {
+		if !token.option {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+func (l dslLine) end() int {
+	last := l.tokens[len(l.tokens)-1]
+	return last.column + len([]rune(last.value))
+}
+
+func (l dslLine) integers(count int) ([]int, error) {
+	tokens := l.positional()
+	values := make([]int, 0, count)
+	for _, token := range tokens[len(tokens)-count:] {
+		value, err := strconv.Atoi(token.value)
+		if err != nil {
+			return nil, dslError(l.number, token.column, "expected an integer, got '%s'", token.value)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// options reads the key=value tokens, only accepting the given keys.
+func (l dslLine) options(allowed ...string) (map[string]string, error) {
+	options := make(map[string]string)
+	for _, token := range l.tokens {
+		if !token.option {
+			continue
+		}
+		key, value, _ := strings.Cut(token.value, "=")
+
+		known := false
+		for _, name := range allowed {
+			known = known || name == key
+		}
+		if !known {
+			return nil, dslError(l.number, token.column, "unknown option '%s'", key)
+		}
+		options[key] = value
+	}
+	return options, nil
+}
+
+func (l dslLine) extra(expected int) error {
+	tokens := l.positional()
+	if len(tokens) > expected+1 {
+		token := tokens[expected+1]
+		return dslError(l.number, token.column, "unexpected argument '%s'", token.value)
+	}
+	return nil
+}
+
+func parseDSLRect(line dslLine) (DrawRequest, error) {
+	if err := line.extra(4); err != nil {
+		return DrawRequest{}, err
+	}
+
+	values, err := line.integers(4)
+	if err != nil {
+		return DrawRequest{}, err
+	}
+
+	options, err := line.options("outline", "fill")
+	if err != nil {
+		return DrawRequest{}, err
+	}
+
+	return DrawRequest{
+		X:       values[0],
+		Y:       values[1],
+		Width:   values[2],
+		Height:  values[3],
+		Outline: text.ASCIIChar(options["outline"]),
+		Fill:    text.ASCIIChar(options["fill"]),
+	}, nil
+}
+
+func parseDSLText(line dslLine) (DrawRequest, error) {
+	if err := line.extra(3); err != nil {
+		return DrawRequest{}, err
+	}
+
+	tokens := line.positional()
+	coordinates := dslLine{number: line.number, tokens: tokens[:3]}
+	values, err := coordinates.integers(2)
+	if err != nil {
+		return DrawRequest{}, err
+	}
+
+	if _, err := line.options(); err != nil {
+		return DrawRequest{}, err
+	}
+
+	return DrawRequest{
+		Type: OperationText,
+		X:    values[0],
+		Y:    values[1],
+		Text: tokens[3].value,
+	}, nil
+}
+
+func parseDSLBanner(line dslLine) (DrawRequest, error) {
+	if err := line.extra(3); err != nil {
+		return DrawRequest{}, err
+	}
+
+	tokens := line.positional()
+	coordinates := dslLine{number: line.number, tokens: tokens[:3]}
+	values, err := coordinates.integers(2)
+	if err != nil {
+		return DrawRequest{}, err
+	}
+
+	options, err := line.options("font", "layout")
+	if err != nil {
+		return DrawRequest{}, err
+	}
+
+	return DrawRequest{
+		Type:   OperationBanner,
+		X:      values[0],
+		Y:      values[1],
+		Text:   tokens[3].value,
+		Font:   options["font"],
+		Layout: banner.Layout(options["layout"]),
+	}, nil
+}
+
+func parseDSLLine(line dslLine) (DrawRequest, error) {
+	if err := line.extra(4); err != nil {
+		return DrawRequest{}, err
+	}
+
+	values, err := line.integers(4)
+	if err != nil {
+		return DrawRequest{}, err
+	}
+
+	options, err := line.options("char")
+	if err != nil {
+		return DrawRequest{}, err
+	}
+
+	return DrawRequest{
+		Type:    OperationLine,
+		X:       values[0],
+		Y:       values[1],
+		ToX:     values[2],
+		ToY:     values[3],
+		Outline: text.ASCIIChar(options["char"]),
+	}, nil
+}
+
+func parseDSLSymbol(line dslLine) (DrawRequest, error) {
+	tokens := line.positional()
+	linked := len(tokens) == 5 && tokens[4].value == "linked"
+	if !linked {
+		if err := line.extra(3); err != nil {
+			return DrawRequest{}, err
+		}
+	}
+
+	coordinates := dslLine{number: line.number, tokens: tokens[:4]}
+	values, err := coordinates.integers(2)
+	if err != nil {
+		return DrawRequest{}, err
+	}
+
+	if _, err := line.options(); err != nil {
+		return DrawRequest{}, err
+	}
+
+	return DrawRequest{
+		Type:   OperationSymbol,
+		Symbol: tokens[1].value,
+		X:      values[0],
+		Y:      values[1],
+		Linked: linked,
+	}, nil
+}
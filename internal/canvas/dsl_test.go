@@ -0,0 +1,95 @@
+package canvas_test
+
+import (
+	"sketch/internal/banner"
+	"sketch/internal/canvas"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDSL(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		assert func(t *testing.T, requests canvas.DrawRequests, err error)
+	}{
+		{
+			name: "when every command is valid, should parse them in order",
+			source: `# a box with a title
+rect 10 2 5 3 outline=@ fill=.
+text 1 1 "hello \"you\""
+
+line 0 0 4 0 char=-
+symbol server 4 4 linked
+banner 0 6 "v1" font=block layout=smushing`,
+			assert: func(t *testing.T, requests canvas.DrawRequests, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, canvas.DrawRequests{
+					{X: 10, Y: 2, Width: 5, Height: 3, Outline: "@", Fill: "."},
+					{Type: canvas.OperationText, X: 1, Y: 1, Text: `hello "you"`},
+					{Type: canvas.OperationLine, X: 0, Y: 0, ToX: 4, ToY: 0, Outline: "-"},
+					{Type: canvas.OperationSymbol, Symbol: "server", X: 4, Y: 4, Linked: true},
+					{Type: canvas.OperationBanner, X: 0, Y: 6, Text: "v1", Font: "block", Layout: banner.LayoutSmushing},
+				}, requests)
+			},
+		},
+		{
+			name:   "when text contains an equal sign, should not take it as an option",
+			source: `text 0 0 "a=b"`,
+			assert: func(t *testing.T, requests canvas.DrawRequests, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "a=b", requests[0].Text)
+			},
+		},
+		{
+			name:   "when the command is unknown, should point to it",
+			source: "rect 0 0 1 1 fill=*\n  circle 1 1 3",
+			assert: func(t *testing.T, requests canvas.DrawRequests, err error) {
+				assert.EqualError(t, err, "line 2, column 3: unknown command 'circle'")
+			},
+		},
+		{
+			name:   "when a coordinate is not a number, should point to it",
+			source: "rect 0 zero 1 1 fill=*",
+			assert: func(t *testing.T, requests canvas.DrawRequests, err error) {
+				assert.EqualError(t, err, "line 1, column 8: expected an integer, got 'zero'")
+			},
+		},
+		{
+			name:   "when an argument is missing, should point to the end of the line",
+			source: "rect 0 0 1",
+			assert: func(t *testing.T, requests canvas.DrawRequests, err error) {
+				assert.EqualError(t, err, "line 1, column 11: rect expects x, y, width, height, missing height")
+			},
+		},
+		{
+			name:   "when an option is unknown, should point to it",
+			source: "rect 0 0 1 1 color=red",
+			assert: func(t *testing.T, requests canvas.DrawRequests, err error) {
+				assert.EqualError(t, err, "line 1, column 14: unknown option 'color'")
+			},
+		},
+		{
+			name:   "when a string is not terminated, should point to its quote",
+			source: `text 0 0 "hello`,
+			assert: func(t *testing.T, requests canvas.DrawRequests, err error) {
+				assert.EqualError(t, err, "line 1, column 10: unterminated string")
+			},
+		},
+		{
+			name:   "when the operation is invalid, should return the validation error",
+			source: "rect 0 0 0 1 fill=*",
+			assert: func(t *testing.T, requests canvas.DrawRequests, err error) {
+				assert.ErrorContains(t, err, "line 1, column 1: width and height")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			requests, err := canvas.ParseDSL(tc.source)
+			tc.assert(t, requests, err)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package canvas
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventCreated EventType = "canvas.created"
+	EventUpdated EventType = "canvas.updated"
+	// EventDeleted has no drawing.
+	EventDeleted EventType = "canvas.deleted"
+
+	// subscriptionSize is how many events a subscriber may fall behind. Older
+	// events are dropped, as every event carries the whole drawing.
+	subscriptionSize = 16
+)
+
+type (
+	EventType string
+
+	// Event tells that a canvas changed, with its drawing after the change.
+	Event struct {
+		ID        string    `json:"id" db:"id"`
+		Type      EventType `json:"type" db:"event_type"`
+		CanvasID  string    `json:"canvas_id" db:"canvas_id"`
+		OwnerID   string    `json:"owner_id,omitempty" db:"owner_id"`
+		Drawing   string    `json:"drawing" db:"drawing"`
+		CreatedAt time.Time `json:"created_at" db:"created_at"`
+	}
+
+	// Publisher is told about every change the services make, after it has
+	// been saved.
+	Publisher interface {
+		Publish(ctx context.Context, event Event)
+	}
+
+	// Publishers hands every event to each of its publishers, in order.
+	Publishers []Publisher
+
+	Subscription struct {
+		canvasID string
+		events   chan Event
+	}
+
+	// Broker is an in-process Publisher that hands the events to the
+	// subscribers of the canvas, and to those subscribed to every canvas.
+	Broker struct {
+		mu          sync.Mutex
+		subscribers map[*Subscription]struct{}
+	}
+)
+
+func NewEvent(eventType EventType, canvas Canvas) Event {
+	return Event{
+		ID:        uuid.New().String(),
+		Type:      eventType,
+		CanvasID:  canvas.ID,
+		OwnerID:   canvas.OwnerID,
+		Drawing:   canvas.Drawing,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+func (p Publishers) Publish(ctx context.Context, event Event) {
+	for _, publisher := range p {
+		publisher.Publish(ctx, event)
+	}
+}
+
+// Events returns the events of the subscription. It is closed on Unsubscribe.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Subscribe listens to the events of a canvas, or of every canvas when the id
+// is empty.
+func (b *Broker) Subscribe(canvasID string) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subscription := &Subscription{canvasID: canvasID, events: make(chan Event, subscriptionSize)}
+	b.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+func (b *Broker) Unsubscribe(subscription *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subscribers[subscription]; ok {
+		delete(b.subscribers, subscription)
+		close(subscription.events)
+	}
+}
+
+// Publish never blocks: a subscriber that is behind loses its oldest event.
+func (b *Broker) Publish(_ context.Context, event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for subscription := range b.subscribers {
+		if subscription.canvasID != "" && subscription.canvasID != event.CanvasID {
+			continue
+		}
+
+		select {
+		case subscription.events <- event:
+			continue
+		default:
+		}
+
+		// Only Publish sends, holding the lock, so once the oldest event is
+		// gone there is room for the new one.
+		select {
+		case <-subscription.events:
+		default:
+		}
+		subscription.events <- event
+	}
+}
@@ -0,0 +1,103 @@
+package canvas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"io"
+	"net/http"
+	"sketch/internal/routing"
+	"time"
+)
+
+const (
+	// EventSnapshot is the first event of a stream, with the drawing as it is
+	// when the stream starts.
+	EventSnapshot EventType = "canvas.snapshot"
+
+	// keepAliveInterval is how often a comment is sent on quiet streams, so
+	// proxies do not close them.
+	keepAliveInterval = 15 * time.Second
+)
+
+type EventHandler struct {
+	service Service
+	broker  *Broker
+}
+
+func NewEventHandler(service Service, broker *Broker) *EventHandler {
+	return &EventHandler{
+		service: service,
+		broker:  broker,
+	}
+}
+
+// Events streams the changes of the canvas as Server-Sent Events, starting
+// with a snapshot of the current drawing. Each event is named after its type
+// and its data is the json Event.
+//
+//	curl -N localhost:8080/<id>/events
+func (c *EventHandler) Events(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	id := params.ByName("id")
+	// Subscribing first makes sure no change is lost between the snapshot and
+	// the stream.
+	subscription := c.broker.Subscribe(id)
+	defer c.broker.Unsubscribe(subscription)
+
+	canvas, err := c.service.GetByID(r.Context(), id)
+
+	if errors.Is(err, ErrNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return fmt.Errorf("streaming is not supported by the response writer")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	snapshot := NewEvent(EventSnapshot, *canvas)
+	if err := writeEvent(w, snapshot); err != nil {
+		return nil
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return nil
+			}
+		case event, open := <-subscription.Events():
+			if !open {
+				return nil
+			}
+			if err := writeEvent(w, event); err != nil {
+				return nil
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeEvent(w io.Writer, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
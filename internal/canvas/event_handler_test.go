@@ -0,0 +1,83 @@
+package canvas_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventHandler_Events(t *testing.T) {
+	newServer := func(t *testing.T, service canvas.Service, broker *canvas.Broker) *httptest.Server {
+		handler := canvas.NewEventHandler(service, broker)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = handler.Events(w, r, httprouter.Params{{Key: "id", Value: "id"}})
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	// readEvent returns the lines of the next event of the stream.
+	readEvent := func(t *testing.T, reader *bufio.Reader) []string {
+		t.Helper()
+		lines := make([]string, 0)
+		for {
+			line, err := reader.ReadString('\n')
+			assert.NoError(t, err)
+			if line == "\n" {
+				return lines
+			}
+			lines = append(lines, strings.TrimSuffix(line, "\n"))
+		}
+	}
+
+	t.Run("when the canvas changes, should stream the snapshot and then the change", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockService(ctrl)
+		broker := canvas.NewBroker()
+		updated := canvas.NewEvent(canvas.EventUpdated, canvas.Canvas{ID: "id", Drawing: "b"})
+		serviceMock.EXPECT().GetByID(gomock.Any(), "id").
+			DoAndReturn(func(ctx context.Context, id string) (*canvas.Canvas, error) {
+				// A change made while the snapshot is read must still be sent.
+				broker.Publish(ctx, updated)
+				return &canvas.Canvas{ID: "id", Drawing: "a"}, nil
+			})
+		server := newServer(t, serviceMock, broker)
+
+		response, err := http.Get(server.URL + "/id/events")
+		assert.NoError(t, err)
+		defer response.Body.Close()
+		reader := bufio.NewReader(response.Body)
+
+		assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+		snapshot := readEvent(t, reader)
+		assert.Equal(t, "event: canvas.snapshot", snapshot[1])
+		assert.Contains(t, snapshot[2], `"drawing":"a"`)
+		assert.Equal(t, []string{
+			"id: " + updated.ID,
+			"event: canvas.updated",
+			`data: {"id":"` + updated.ID + `","type":"canvas.updated","canvas_id":"id","drawing":"b","created_at":"` +
+				updated.CreatedAt.Format("2006-01-02T15:04:05.999999999Z07:00") + `"}`,
+		}, readEvent(t, reader))
+	})
+
+	t.Run("when the canvas does not exist, should return not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockService(ctrl)
+		serviceMock.EXPECT().GetByID(gomock.Any(), "id").Return(nil, canvas.ErrNotFound)
+		server := newServer(t, serviceMock, canvas.NewBroker())
+
+		response, err := http.Get(server.URL + "/id/events")
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, response.StatusCode)
+	})
+}
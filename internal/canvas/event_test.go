@@ -0,0 +1,51 @@
+package canvas_test
+
+import (
+	"context"
+	"sketch/internal/canvas"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroker_Publish(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("when subscribed to a canvas, should only get its events", func(t *testing.T) {
+		broker := canvas.NewBroker()
+		subscription := broker.Subscribe("a")
+		everything := broker.Subscribe("")
+
+		broker.Publish(ctx, canvas.NewEvent(canvas.EventUpdated, canvas.Canvas{ID: "b"}))
+		broker.Publish(ctx, canvas.NewEvent(canvas.EventUpdated, canvas.Canvas{ID: "a", Drawing: "x"}))
+
+		event := <-subscription.Events()
+		assert.Equal(t, "a", event.CanvasID)
+		assert.Equal(t, "x", event.Drawing)
+		assert.Len(t, subscription.Events(), 0)
+		assert.Len(t, everything.Events(), 2)
+	})
+
+	t.Run("when a subscriber is behind, should drop its oldest events", func(t *testing.T) {
+		broker := canvas.NewBroker()
+		subscription := broker.Subscribe("a")
+
+		for i := 0; i < 20; i++ {
+			broker.Publish(ctx, canvas.NewEvent(canvas.EventUpdated, canvas.Canvas{ID: "a", Drawing: string(rune('a' + i))}))
+		}
+
+		assert.Equal(t, "e", (<-subscription.Events()).Drawing)
+	})
+
+	t.Run("when unsubscribed, should close the events", func(t *testing.T) {
+		broker := canvas.NewBroker()
+		subscription := broker.Subscribe("a")
+
+		broker.Unsubscribe(subscription)
+		broker.Unsubscribe(subscription)
+		broker.Publish(ctx, canvas.NewEvent(canvas.EventUpdated, canvas.Canvas{ID: "a"}))
+
+		_, open := <-subscription.Events()
+		assert.False(t, open)
+	})
+}
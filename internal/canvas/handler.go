@@ -1,12 +1,26 @@
 package canvas
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/julienschmidt/httprouter"
 	"html/template"
+	"io"
+	"math"
+	"mime"
 	"net/http"
+	"net/url"
+	"sketch/internal/diagram"
+	"sketch/internal/imaging"
 	"sketch/internal/routing"
+	"sketch/internal/text"
+	"strconv"
+)
+
+const (
+	maxUploadSize = 10 << 20
 )
 
 type Handler struct {
@@ -19,6 +33,7 @@ func NewHandler(service Service) *Handler {
 	}
 }
 
+// Show renders the page of the canvas of the id query param.
 func (c *Handler) Show(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
 	id := r.URL.Query().Get("id")
 	tmpl := template.Must(template.ParseFiles("./pages/home.html"))
@@ -38,9 +53,9 @@ func (c *Handler) Show(w http.ResponseWriter, r *http.Request, _ httprouter.Para
 }
 
 func (c *Handler) Draw(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
-	requests, err := routing.FromJSON[DrawRequests](r)
+	requests, err := c.readRequests(w, r)
 	if err != nil {
-		return fmt.Errorf("failed to get json body: %w", err)
+		return err
 	}
 
 	if err := requests.Validate(); err != nil {
@@ -55,9 +70,73 @@ func (c *Handler) Draw(w http.ResponseWriter, r *http.Request, _ httprouter.Para
 	return routing.ToJSON(w, http.StatusOK, response)
 }
 
+// readRequests reads the body, refusing those over maxUploadSize, and decodes
+// it as draw requests.
+func (c *Handler) readRequests(w http.ResponseWriter, r *http.Request) (DrawRequests, error) {
+	body, err := readBody(w, r)
+	if err != nil {
+		return nil, err
+	}
+	return decodeRequests(r.Header.Get("Content-Type"), body)
+}
+
+// decodeRequests decodes the body as JSON, or as the drawing DSL when the
+// request is sent as text/x-sketch.
+func decodeRequests(contentType string, body []byte) (DrawRequests, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != DSLContentType {
+		var requests DrawRequests
+		if err := json.NewDecoder(bytes.NewReader(body)).Decode(&requests); err != nil {
+			return nil, fmt.Errorf("failed to get json body: %w", err)
+		}
+		return requests, nil
+	}
+	return ParseDSL(string(body))
+}
+
+// RenderCost weighs a render by the area of the canvas it draws, taken from
+// the requests without rendering them. The requests it cannot read, too large
+// included, weigh one, as they fail later anyway.
+func (c *Handler) RenderCost(r *http.Request, _ httprouter.Params) int {
+	body, ok := peekBody(r)
+	if !ok {
+		return 1
+	}
+
+	requests, err := decodeRequests(r.Header.Get("Content-Type"), body)
+	if err != nil {
+		return 1
+	}
+	return renderCost(requests)
+}
+
+// renderCost is the area of the canvas drawing the requests, at least one.
+func renderCost(requests DrawRequests) int {
+	width, height := requests.Bounds()
+	if width <= 0 || height <= 0 {
+		return 1
+	}
+
+	if width > math.MaxInt/height {
+		return math.MaxInt
+	}
+	return width * height
+}
+
+// GetById returns the canvas, or only the region of it in the query.
 func (c *Handler) GetById(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
 	id := params.ByName("id")
-	canvas, err := c.service.GetByID(r.Context(), id)
+	viewport, err := NewViewportFromQuery(r.URL.Query())
+	if err != nil {
+		return err
+	}
+
+	var canvas *Canvas
+	if viewport != nil {
+		canvas, err = c.service.GetViewport(r.Context(), id, *viewport)
+	} else {
+		canvas, err = c.service.GetByID(r.Context(), id)
+	}
 
 	if errors.Is(err, ErrNotFound) {
 		return routing.NotFound(w, err)
@@ -69,3 +148,285 @@ func (c *Handler) GetById(w http.ResponseWriter, r *http.Request, params httprou
 
 	return routing.ToJSON(w, http.StatusOK, canvas)
 }
+
+func (c *Handler) Crop(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	viewport, err := routing.FromJSON[Viewport](r)
+	if err != nil {
+		return fmt.Errorf("failed to get json body: %w", err)
+	}
+
+	if err := viewport.Validate(); err != nil {
+		return err
+	}
+
+	response, err := c.service.Crop(r.Context(), params.ByName("id"), viewport)
+
+	if errors.Is(err, ErrNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, response)
+}
+
+func (c *Handler) Edit(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	requests, err := c.readRequests(w, r)
+	if err != nil {
+		return err
+	}
+
+	if err := requests.Validate(); err != nil {
+		return err
+	}
+
+	response, err := c.service.Edit(r.Context(), params.ByName("id"), requests)
+
+	if errors.Is(err, ErrNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if errors.Is(err, ErrConflict) {
+		return routing.Conflict(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, response)
+}
+
+func (c *Handler) Delete(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	err := c.service.Delete(r.Context(), params.ByName("id"))
+
+	if errors.Is(err, ErrNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// Import creates a canvas from raw text art. Tabs are expanded to the
+// tab_size query parameter, 8 by default.
+func (c *Handler) Import(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+	tabSize := text.DefaultTabSize
+	if raw := r.URL.Query().Get("tab_size"); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil {
+			return text.ErrInvalidTabSize
+		}
+		tabSize = size
+	}
+
+	body, err := readBody(w, r)
+	if err != nil {
+		return err
+	}
+
+	drawing, err := text.Normalize(string(body), tabSize)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.service.Import(r.Context(), drawing)
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, response)
+}
+
+// Analyze returns the requests that draw the text art sent in the body.
+func (c *Handler) Analyze(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+	body, err := readBody(w, r)
+	if err != nil {
+		return err
+	}
+
+	drawing, err := text.Normalize(string(body), text.DefaultTabSize)
+	if err != nil {
+		return err
+	}
+
+	if width, height := ParseDraw(drawing).Size(); width*height > maxAnalyzeArea {
+		return ErrAnalyzeTooLarge
+	}
+
+	return routing.ToJSON(w, http.StatusOK, Analyze(drawing))
+}
+
+// Convert turns an uploaded png, jpeg or gif into a new canvas. The image is
+// read from the "image" field of a multipart form or from the raw body.
+func (c *Handler) Convert(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+	options, err := convertOptions(r.URL.Query())
+	if err != nil {
+		return err
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	body := io.Reader(r.Body)
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		file, _, err := r.FormFile("image")
+		if err != nil {
+			return imaging.ErrInvalidImage
+		}
+		defer file.Close()
+		body = file
+	}
+
+	img, err := imaging.Decode(body)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return ErrBodyTooLarge
+	}
+	if err != nil {
+		return err
+	}
+
+	drawing, err := text.Normalize(imaging.ToASCII(img, options), text.DefaultTabSize)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.service.Import(r.Context(), drawing)
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, response)
+}
+
+// Sequence draws the sequence diagram described in the body as a new canvas.
+func (c *Handler) Sequence(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+	body, err := readBody(w, r)
+	if err != nil {
+		return err
+	}
+
+	sequence, err := diagram.ParseSequence(string(body))
+	if err != nil {
+		return err
+	}
+
+	return c.importText(w, r, sequence.Render())
+}
+
+// Diagram lays out the boxes and routes the connectors of the diagram sent in
+// the body, saving the result as a new canvas.
+func (c *Handler) Diagram(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+	body, err := readBody(w, r)
+	if err != nil {
+		return err
+	}
+
+	var request diagram.Diagram
+	if err := json.Unmarshal(body, &request); err != nil {
+		return fmt.Errorf("failed to get json body: %w", err)
+	}
+
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	return c.importDiagram(w, r, request)
+}
+
+// DiagramDOT lays out the Graphviz digraph sent in the body in layers before
+// drawing it like Diagram does.
+func (c *Handler) DiagramDOT(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+	body, err := readBody(w, r)
+	if err != nil {
+		return err
+	}
+
+	graph, err := diagram.ParseDOT(string(body))
+	if err != nil {
+		return err
+	}
+
+	request := diagram.Layout(graph)
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	return c.importDiagram(w, r, request)
+}
+
+func (c *Handler) importDiagram(w http.ResponseWriter, r *http.Request, request diagram.Diagram) error {
+	rendered, err := request.Render()
+	if err != nil {
+		return err
+	}
+
+	return c.importText(w, r, rendered)
+}
+
+// importText saves the drawing rendered by a diagram as a new canvas.
+func (c *Handler) importText(w http.ResponseWriter, r *http.Request, rendered string) error {
+	drawing, err := text.Normalize(rendered, text.DefaultTabSize)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.service.Import(r.Context(), drawing)
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, response)
+}
+
+// peekBody reads the body up to maxUploadSize and leaves it to be read again,
+// whole. It is not ok when the body could not be read or is larger.
+func peekBody(r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxUploadSize+1))
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+	return body, err == nil && len(body) <= maxUploadSize
+}
+
+// readBody reads the body, refusing those over maxUploadSize.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxUploadSize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return nil, ErrBodyTooLarge
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+	return body, nil
+}
+
+func convertOptions(query url.Values) (imaging.Options, error) {
+	options := imaging.DefaultOptions()
+	var err error
+
+	if raw := query.Get("width"); raw != "" {
+		if options.Width, err = strconv.Atoi(raw); err != nil {
+			return options, imaging.ErrInvalidWidth
+		}
+	}
+
+	if raw := query.Get("aspect"); raw != "" {
+		if options.Aspect, err = strconv.ParseFloat(raw, 64); err != nil {
+			return options, imaging.ErrInvalidAspect
+		}
+	}
+
+	if raw := query.Get("ramp"); raw != "" {
+		options.Ramp = raw
+	}
+
+	options.Dither = query.Get("dither") == "true"
+	return options, options.Validate()
+}
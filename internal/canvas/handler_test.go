@@ -5,13 +5,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"image"
+	"image/color"
+	"image/png"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"sketch/internal/canvas"
 	mock_canvas "sketch/internal/canvas/mocks"
+	"sketch/internal/diagram"
+	"sketch/internal/imaging"
+	"sketch/internal/text"
 	. "sketch/tests"
 	"sketch/tests/faker"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -130,6 +137,15 @@ func TestHandler_Draw(t *testing.T) {
 				assert.ErrorIs(t, args.gotErr, io.EOF)
 			},
 		},
+		{
+			name: "when the body is too large, should refuse it",
+			arrange: arrangeArgs{
+				body: bytes.Repeat([]byte(" "), 10<<20+1),
+			},
+			assert: func(t *testing.T, args assertArgs) {
+				assert.ErrorIs(t, args.gotErr, canvas.ErrBodyTooLarge)
+			},
+		},
 		{
 			name: "when there is an error creating the draw, should return it",
 			arrange: arrangeArgs{
@@ -181,3 +197,459 @@ func TestHandler_Draw(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_Crop(t *testing.T) {
+	type arrangeArgs struct {
+		body             []byte
+		called           int
+		expectedResponse *canvas.DrawResponse
+		expectedErr      error
+	}
+	type assertArgs struct {
+		gotErr      error
+		gotResponse string
+		statusCode  int
+	}
+	fakeResponse := &canvas.DrawResponse{ID: "id", Drawing: "XXX"}
+	validViewport := canvas.Viewport{X: 1, Y: 1, Width: 3, Height: 1}
+
+	tests := []struct {
+		name    string
+		arrange arrangeArgs
+		assert  func(t *testing.T, args assertArgs)
+	}{
+		{
+			name:    "when the viewport is invalid, should return an error",
+			arrange: arrangeArgs{body: ToJSON(canvas.Viewport{X: 1})},
+			assert: func(t *testing.T, args assertArgs) {
+				assert.ErrorContains(t, args.gotErr, "width and height")
+			},
+		},
+		{
+			name: "when the canvas does not exist, should return a 404",
+			arrange: arrangeArgs{
+				body:        ToJSON(validViewport),
+				called:      1,
+				expectedErr: canvas.ErrNotFound,
+			},
+			assert: func(t *testing.T, args assertArgs) {
+				assert.Equal(t, http.StatusNotFound, args.statusCode)
+			},
+		},
+		{
+			name: "when there are no errors, should return the cropped canvas",
+			arrange: arrangeArgs{
+				body:             ToJSON(validViewport),
+				called:           1,
+				expectedResponse: fakeResponse,
+			},
+			assert: func(t *testing.T, args assertArgs) {
+				assert.NoError(t, args.gotErr)
+				assert.JSONEq(t, string(ToJSON(fakeResponse)), args.gotResponse)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			serviceMock := mock_canvas.NewMockService(ctrl)
+			const id = "123"
+			serviceMock.EXPECT().Crop(gomock.Any(), id, validViewport).
+				Times(tc.arrange.called).
+				Return(tc.arrange.expectedResponse, tc.arrange.expectedErr)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/123/crop", bytes.NewReader(tc.arrange.body))
+			handler := canvas.NewHandler(serviceMock)
+			err := handler.Crop(w, r, httprouter.Params{{Key: "id", Value: id}})
+
+			tc.assert(t, assertArgs{gotErr: err, gotResponse: w.Body.String(), statusCode: w.Code})
+		})
+	}
+}
+
+func TestHandler_DrawDSL(t *testing.T) {
+	t.Run("when the body is valid, should save the parsed requests", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockService(ctrl)
+		expected := canvas.DrawRequests{{X: 1, Y: 2, Width: 3, Height: 4, Fill: "*"}}
+		serviceMock.EXPECT().Save(gomock.Any(), expected).
+			Times(1).
+			Return(&canvas.DrawResponse{ID: "id"}, nil)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("rect 1 2 3 4 fill=*")))
+		r.Header.Set("Content-Type", "text/x-sketch; charset=utf-8")
+		err := canvas.NewHandler(serviceMock).Draw(w, r, nil)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("when the body has a syntax error, should return it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockService(ctrl)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("rect 1 2")))
+		r.Header.Set("Content-Type", canvas.DSLContentType)
+		err := canvas.NewHandler(serviceMock).Draw(w, r, nil)
+
+		assert.ErrorContains(t, err, "line 1, column 9")
+	})
+}
+
+func TestHandler_RenderCost(t *testing.T) {
+	t.Run("when the body draws a canvas, should weigh it by its area and leave the body", func(t *testing.T) {
+		body := `[{"x": 1, "y": 2, "width": 3, "height": 4, "fill": "*"}]`
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(body)))
+
+		cost := canvas.NewHandler(nil).RenderCost(r, nil)
+		left, _ := io.ReadAll(r.Body)
+
+		assert.Equal(t, 4*6, cost)
+		assert.Equal(t, body, string(left))
+	})
+
+	t.Run("when the body cannot be read, should weigh it as one", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte("not json")))
+
+		assert.Equal(t, 1, canvas.NewHandler(nil).RenderCost(r, nil))
+	})
+
+	t.Run("when the body draws a banner, should weigh it without rendering it", func(t *testing.T) {
+		body := `[{"type": "banner", "x": 0, "y": 0, "text": "hi"}]`
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(body)))
+		requests := canvas.DrawRequests{{Type: canvas.OperationBanner, Text: "hi"}}
+		width, height := requests.Size()
+
+		assert.GreaterOrEqual(t, canvas.NewHandler(nil).RenderCost(r, nil), width*height)
+	})
+
+	t.Run("when the table is missing, should weigh it as one", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`[{"type": "table", "x": 0, "y": 0}]`)))
+
+		assert.Equal(t, 1, canvas.NewHandler(nil).RenderCost(r, nil))
+	})
+
+	t.Run("when the body is too large, should weigh it as one and leave it whole", func(t *testing.T) {
+		body := bytes.Repeat([]byte(" "), 10<<20+1)
+		r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+
+		cost := canvas.NewHandler(nil).RenderCost(r, nil)
+		left, _ := io.ReadAll(r.Body)
+
+		assert.Equal(t, 1, cost)
+		assert.Len(t, left, len(body))
+	})
+}
+
+func TestHandler_Import(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		body     string
+		expected string
+		called   int
+		assert   func(t *testing.T, err error)
+	}{
+		{
+			name:     "when the text is valid, should import it normalized",
+			url:      "/import?tab_size=4",
+			body:     "+--+   \r\n|\t|\r\n+--+\r\n",
+			expected: "+--+\n|   |\n+--+",
+			called:   1,
+			assert: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "when the tab size is invalid, should return an error",
+			url:  "/import?tab_size=0",
+			body: "x",
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, text.ErrInvalidTabSize)
+			},
+		},
+		{
+			name: "when the text is too large, should return an error",
+			url:  "/import",
+			body: strings.Repeat("x", 10<<20+1),
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, canvas.ErrBodyTooLarge)
+			},
+		},
+		{
+			name: "when the text is blank, should return an error",
+			url:  "/import",
+			body: "\n\n",
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, text.ErrEmptyText)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			serviceMock := mock_canvas.NewMockService(ctrl)
+			serviceMock.EXPECT().Import(gomock.Any(), tc.expected).
+				Times(tc.called).
+				Return(&canvas.DrawResponse{ID: "id", Drawing: tc.expected}, nil)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, tc.url, bytes.NewReader([]byte(tc.body)))
+			err := canvas.NewHandler(serviceMock).Import(w, r, nil)
+
+			tc.assert(t, err)
+		})
+	}
+}
+
+func TestHandler_Analyze(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/analyze", bytes.NewReader([]byte("@@@@\r\n@..@\r\n@@@@\r\n")))
+	err := canvas.NewHandler(nil).Analyze(w, r, nil)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"x":0,"y":0,"width":4,"height":3,"outline":"@","fill":"."}]`, w.Body.String())
+}
+
+func TestHandler_AnalyzeTooLarge(t *testing.T) {
+	w := httptest.NewRecorder()
+	drawing := strings.Repeat(strings.Repeat("@", 200)+"\n", 101)
+	r := httptest.NewRequest(http.MethodPost, "/analyze", bytes.NewReader([]byte(drawing)))
+	err := canvas.NewHandler(nil).Analyze(w, r, nil)
+
+	assert.ErrorIs(t, err, canvas.ErrAnalyzeTooLarge)
+}
+
+func TestHandler_Convert(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	img.SetGray(0, 0, color.Gray{Y: 255})
+	encoded := &bytes.Buffer{}
+	_ = png.Encode(encoded, img)
+
+	t.Run("when the body is a png, should import its conversion", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockService(ctrl)
+		serviceMock.EXPECT().Import(gomock.Any(), "+@@@\n@@@@").
+			Times(1).
+			Return(&canvas.DrawResponse{ID: "id"}, nil)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/convert?width=4", bytes.NewReader(encoded.Bytes()))
+		r.Header.Set("Content-Type", "image/png")
+		err := canvas.NewHandler(serviceMock).Convert(w, r, nil)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("when the body is not an image, should return an error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/convert", bytes.NewReader([]byte("hello")))
+		err := canvas.NewHandler(nil).Convert(w, r, nil)
+
+		assert.ErrorIs(t, err, imaging.ErrInvalidImage)
+	})
+
+	t.Run("when the options are invalid, should return an error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/convert?width=0", bytes.NewReader(encoded.Bytes()))
+		err := canvas.NewHandler(nil).Convert(w, r, nil)
+
+		assert.ErrorIs(t, err, imaging.ErrInvalidWidth)
+	})
+}
+
+func TestHandler_Diagram(t *testing.T) {
+	t.Run("when the diagram is valid, should import its drawing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockService(ctrl)
+		serviceMock.EXPECT().Import(gomock.Any(), "+---+     +---+\n| a |---->| b |\n+---+     +---+").
+			Times(1).
+			Return(&canvas.DrawResponse{ID: "id"}, nil)
+
+		body := `{"nodes": [{"id": "a", "x": 0, "y": 0}, {"id": "b", "x": 10, "y": 0}], "edges": [{"from": "a", "to": "b"}]}`
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/diagrams", bytes.NewReader([]byte(body)))
+		err := canvas.NewHandler(serviceMock).Diagram(w, r, nil)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("when an edge references an unknown node, should return an error", func(t *testing.T) {
+		body := `{"nodes": [{"id": "a", "x": 0, "y": 0}], "edges": [{"from": "a", "to": "b"}]}`
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/diagrams", bytes.NewReader([]byte(body)))
+		err := canvas.NewHandler(nil).Diagram(w, r, nil)
+
+		assert.ErrorIs(t, err, diagram.ErrUnknownNode)
+	})
+
+	t.Run("when a node is too far, should refuse it without drawing it", func(t *testing.T) {
+		body := `{"nodes": [{"id": "a", "x": 1000000, "y": 1000000}]}`
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/diagrams", bytes.NewReader([]byte(body)))
+		err := canvas.NewHandler(nil).Diagram(w, r, nil)
+
+		assert.ErrorIs(t, err, diagram.ErrDiagramTooLarge)
+	})
+
+	t.Run("when the body is too large, should return an error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/diagrams", strings.NewReader(strings.Repeat(" ", 10<<20+1)))
+		err := canvas.NewHandler(nil).Diagram(w, r, nil)
+
+		assert.ErrorIs(t, err, canvas.ErrBodyTooLarge)
+	})
+}
+
+func TestHandler_DiagramDOT(t *testing.T) {
+	t.Run("when the dot is valid, should import its layout", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockService(ctrl)
+		serviceMock.EXPECT().Import(gomock.Any(), "\n +---+\n | a |\n +---+\n   |\n   |\n   |\n   v\n +---+\n | b |\n +---+").
+			Times(1).
+			Return(&canvas.DrawResponse{ID: "id"}, nil)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/diagrams/dot", bytes.NewReader([]byte("digraph { a -> b }")))
+		r.Header.Set("Content-Type", diagram.DOTContentType)
+		err := canvas.NewHandler(serviceMock).DiagramDOT(w, r, nil)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("when the dot is invalid, should return an error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/diagrams/dot", bytes.NewReader([]byte("graph { a }")))
+		err := canvas.NewHandler(nil).DiagramDOT(w, r, nil)
+
+		assert.ErrorIs(t, err, diagram.ErrInvalidDOT)
+	})
+
+	t.Run("when the body is too large, should return an error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/diagrams/dot", strings.NewReader(strings.Repeat("x", 10<<20+1)))
+		err := canvas.NewHandler(nil).DiagramDOT(w, r, nil)
+
+		assert.ErrorIs(t, err, canvas.ErrBodyTooLarge)
+	})
+}
+
+func TestHandler_Sequence(t *testing.T) {
+	t.Run("when the sequence is valid, should import its drawing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockService(ctrl)
+		serviceMock.EXPECT().Import(gomock.Any(), "+---+  +---+\n| a |  | b |\n+---+  +---+\n  |      |\n  |----->|\n  |      |").
+			Times(1).
+			Return(&canvas.DrawResponse{ID: "id"}, nil)
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/sequences", bytes.NewReader([]byte("a -> b")))
+		err := canvas.NewHandler(serviceMock).Sequence(w, r, nil)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("when the sequence is empty, should return an error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/sequences", bytes.NewReader([]byte("")))
+		err := canvas.NewHandler(nil).Sequence(w, r, nil)
+
+		assert.ErrorIs(t, err, diagram.ErrEmptySequence)
+	})
+
+	t.Run("when the body is too large, should return an error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/sequences", strings.NewReader(strings.Repeat("x", 10<<20+1)))
+		err := canvas.NewHandler(nil).Sequence(w, r, nil)
+
+		assert.ErrorIs(t, err, canvas.ErrBodyTooLarge)
+	})
+}
+
+func TestHandler_Edit(t *testing.T) {
+	tests := []struct {
+		name       string
+		serviceErr error
+		statusCode int
+		wantErr    error
+	}{
+		{
+			name:       "when the canvas is edited, should return it",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "when there is no canvas, should return a 404",
+			serviceErr: canvas.ErrNotFound,
+			statusCode: http.StatusNotFound,
+			wantErr:    canvas.ErrNotFound,
+		},
+		{
+			name:       "when other changes kept getting in first, should return a 409",
+			serviceErr: canvas.ErrConflict,
+			statusCode: http.StatusConflict,
+			wantErr:    canvas.ErrConflict,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			serviceMock := mock_canvas.NewMockService(ctrl)
+			handler := canvas.NewHandler(serviceMock)
+			body := `[{"x": 0, "y": 0, "width": 1, "height": 1, "fill": "*"}]`
+			req := httptest.NewRequest(http.MethodPost, "/123", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			var response *canvas.DrawResponse
+			if tt.serviceErr == nil {
+				response = &canvas.DrawResponse{ID: "123", Drawing: "*"}
+			}
+
+			serviceMock.EXPECT().Edit(gomock.Any(), "123", gomock.Any()).Return(response, tt.serviceErr)
+
+			err := handler.Edit(w, req, httprouter.Params{{Key: "id", Value: "123"}})
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.statusCode, w.Code)
+		})
+	}
+}
+
+func TestHandler_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		serviceErr error
+		statusCode int
+		wantErr    error
+	}{
+		{
+			name:       "when the canvas is deleted, should return no content",
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "when there is no canvas, should return a 404",
+			serviceErr: canvas.ErrNotFound,
+			statusCode: http.StatusNotFound,
+			wantErr:    canvas.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctrl := gomock.NewController(t)
+			serviceMock := mock_canvas.NewMockService(ctrl)
+			handler := canvas.NewHandler(serviceMock)
+			req := httptest.NewRequest(http.MethodDelete, "/123", nil)
+
+			serviceMock.EXPECT().Delete(gomock.Any(), "123").Return(tt.serviceErr)
+
+			err := handler.Delete(w, req, httprouter.Params{{Key: "id", Value: "123"}})
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.statusCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package canvas
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sketch/internal/errors"
+	"strings"
+	"time"
+)
+
+const (
+	PermissionRead Permission = "read"
+	PermissionEdit Permission = "edit"
+
+	// QueryToken and HeaderToken carry the token of a share link, the query
+	// being for the pages, which cannot send headers.
+	QueryToken  = "token"
+	HeaderToken = "X-Share-Token"
+
+	defaultLinkLifetime = 24 * time.Hour
+	maxLinkLifetime     = 30 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidPermission = errors.Error("permission must be read or edit")
+	ErrInvalidLifetime   = errors.Error(fmt.Sprintf("expires_in must be between 1 and %d seconds", int(maxLinkLifetime.Seconds())))
+	ErrInvalidToken      = errors.Error("invalid share token")
+	ErrTokenExpired      = errors.Error("the share token expired")
+	ErrLinkNotFound      = errors.Error("share link not found")
+	ErrReadOnlyLink      = errors.Error("the share link only allows reading the canvas")
+)
+
+type (
+	Permission string
+
+	// Link lets anyone with its token read, or edit, a canvas until it
+	// expires or is revoked. The token itself is only shown when the link is
+	// created.
+	Link struct {
+		ID          string     `json:"id" db:"id"`
+		DrawingID   string     `json:"drawing_id" db:"drawing_id"`
+		WorkspaceID string     `json:"workspace_id,omitempty" db:"workspace_id"`
+		Permission  Permission `json:"permission" db:"permission"`
+		CreatedBy   string     `json:"created_by" db:"created_by"`
+		ExpiresAt   time.Time  `json:"expires_at" db:"expires_at"`
+		CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+		RevokedAt   *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	}
+
+	// NewLink is a link just created, with its token.
+	NewLink struct {
+		Link
+		Token string `json:"token"`
+	}
+
+	LinkRequest struct {
+		Permission Permission `json:"permission"`
+		// ExpiresIn is how long, in seconds, the link lasts. A day when zero.
+		ExpiresIn int `json:"expires_in"`
+	}
+
+	// linkClaims is what a token tells, under its signature.
+	linkClaims struct {
+		LinkID     string     `json:"lid"`
+		DrawingID  string     `json:"did"`
+		Permission Permission `json:"perm"`
+		ExpiresAt  int64      `json:"exp"`
+	}
+
+	linkKey struct{}
+)
+
+func (r LinkRequest) Validate() error {
+	if r.Permission != PermissionRead && r.Permission != PermissionEdit {
+		return ErrInvalidPermission
+	}
+
+	if r.ExpiresIn < 0 || time.Duration(r.ExpiresIn)*time.Second > maxLinkLifetime {
+		return ErrInvalidLifetime
+	}
+	return nil
+}
+
+// Lifetime returns how long the link of the request lasts.
+func (r LinkRequest) Lifetime() time.Duration {
+	if r.ExpiresIn == 0 {
+		return defaultLinkLifetime
+	}
+	return time.Duration(r.ExpiresIn) * time.Second
+}
+
+// WithLink tells the request was made with the token of the link.
+func WithLink(ctx context.Context, link Link) context.Context {
+	return context.WithValue(ctx, linkKey{}, link)
+}
+
+// LinkFromContext returns the link whose token the request carries, if any.
+func LinkFromContext(ctx context.Context) (Link, bool) {
+	link, ok := ctx.Value(linkKey{}).(Link)
+	return link, ok
+}
+
+// SignLink returns the token of the link: its claims and their HMAC-SHA256,
+// keyed with the secret, both base64url encoded and joined by a dot.
+func SignLink(secret []byte, link Link) (string, error) {
+	claims, err := json.Marshal(linkClaims{
+		LinkID:     link.ID,
+		DrawingID:  link.DrawingID,
+		Permission: link.Permission,
+		ExpiresAt:  link.ExpiresAt.Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(claims)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(signLink(secret, payload)), nil
+}
+
+// verifyLink returns the claims of the token when it was signed with the
+// secret and did not expire at now.
+func verifyLink(secret []byte, token string, now time.Time) (linkClaims, error) {
+	var claims linkClaims
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return claims, ErrInvalidToken
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(decoded, signLink(secret, payload)) {
+		return claims, ErrInvalidToken
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return claims, ErrInvalidToken
+	}
+
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return claims, ErrInvalidToken
+	}
+
+	if !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return claims, ErrTokenExpired
+	}
+	return claims, nil
+}
+
+func signLink(secret []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
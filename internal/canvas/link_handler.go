@@ -0,0 +1,82 @@
+package canvas
+
+import (
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"sketch/internal/routing"
+)
+
+type LinkHandler struct {
+	service LinkService
+}
+
+func NewLinkHandler(service LinkService) *LinkHandler {
+	return &LinkHandler{
+		service: service,
+	}
+}
+
+// Create mints a share link of the canvas. Its token is only in this
+// response.
+func (c *LinkHandler) Create(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	request, err := routing.FromJSON[LinkRequest](r)
+	if err != nil {
+		return fmt.Errorf("failed to get json body: %w", err)
+	}
+
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	link, err := c.service.Create(r.Context(), params.ByName("id"), request)
+	if err := accessError(w, err); err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, link)
+}
+
+func (c *LinkHandler) GetLinks(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	links, err := c.service.GetLinks(r.Context(), params.ByName("id"))
+	if err := accessError(w, err); err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, links)
+}
+
+func (c *LinkHandler) Revoke(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	err := c.service.Revoke(r.Context(), params.ByName("id"), params.ByName("link"))
+
+	if errors.Is(err, ErrLinkNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err := accessError(w, err); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// openLink returns the request made with the share token it carries, if any,
+// for the canvas of the id.
+func openLink(r *http.Request, links LinkService, id string) (*http.Request, error) {
+	token := r.Header.Get(HeaderToken)
+	if token == "" {
+		token = r.URL.Query().Get(QueryToken)
+	}
+
+	if token == "" {
+		return r, nil
+	}
+
+	ctx, err := links.Open(r.Context(), id, token)
+	if err != nil {
+		return r, err
+	}
+	return r.WithContext(ctx), nil
+}
@@ -0,0 +1,77 @@
+package canvas
+
+import (
+	"context"
+	"database/sql"
+	goerrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	LinkRepository interface {
+		SaveLink(ctx context.Context, link Link) error
+		GetLink(ctx context.Context, id string) (Link, error)
+		GetLinks(ctx context.Context, drawingID string) ([]Link, error)
+		RevokeLink(ctx context.Context, drawingID string, id string) error
+	}
+
+	linkRepository struct {
+		db *sqlx.DB
+	}
+)
+
+func NewLinkRepository(db *sqlx.DB) LinkRepository {
+	return &linkRepository{
+		db: db,
+	}
+}
+
+func (r *linkRepository) SaveLink(ctx context.Context, link Link) error {
+	const query = "insert into canvas_links (id, drawing_id, workspace_id, permission, created_by, expires_at, created_at) " +
+		"values (:id, :drawing_id, :workspace_id, :permission, :created_by, :expires_at, :created_at)"
+	if _, err := r.db.NamedExecContext(ctx, query, link); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+func (r *linkRepository) GetLink(ctx context.Context, id string) (Link, error) {
+	const query = "select id, drawing_id, workspace_id, permission, created_by, expires_at, created_at, revoked_at " +
+		"from canvas_links where id = $1"
+	var link Link
+	if err := r.db.GetContext(ctx, &link, query, id); err != nil {
+		if goerrors.Is(err, sql.ErrNoRows) {
+			return link, ErrLinkNotFound
+		}
+
+		return link, fmt.Errorf("database err: %w", err)
+	}
+	return link, nil
+}
+
+func (r *linkRepository) GetLinks(ctx context.Context, drawingID string) ([]Link, error) {
+	const query = "select id, drawing_id, workspace_id, permission, created_by, expires_at, created_at, revoked_at " +
+		"from canvas_links where drawing_id = $1 order by created_at"
+	links := make([]Link, 0)
+	if err := r.db.SelectContext(ctx, &links, query, drawingID); err != nil {
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+	return links, nil
+}
+
+// RevokeLink fails with ErrLinkNotFound when the link was already revoked.
+func (r *linkRepository) RevokeLink(ctx context.Context, drawingID string, id string) error {
+	const query = "update canvas_links set revoked_at = $3 where id = $1 and drawing_id = $2 and revoked_at is null"
+	result, err := r.db.ExecContext(ctx, query, id, drawingID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrLinkNotFound
+	}
+	return nil
+}
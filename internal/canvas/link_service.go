@@ -0,0 +1,155 @@
+package canvas
+
+import (
+	"context"
+	"crypto/rand"
+	goerrors "errors"
+	"fmt"
+	"sketch/internal/auth"
+	"sketch/internal/workspace"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// LinkService mints the share links of a canvas, which only those who may
+	// share it get to see or revoke: in a workspace its editors and admins,
+	// elsewhere its owner.
+	LinkService interface {
+		Create(ctx context.Context, id string, request LinkRequest) (*NewLink, error)
+		GetLinks(ctx context.Context, id string) ([]Link, error)
+		Revoke(ctx context.Context, id string, linkID string) error
+		// Open checks the token gives access to the canvas of the id, and
+		// returns the context of a request made with it: in the workspace of
+		// the canvas, as a viewer or an editor.
+		Open(ctx context.Context, id string, token string) (context.Context, error)
+	}
+
+	linkService struct {
+		repository Repository
+		links      LinkRepository
+		secret     []byte
+	}
+)
+
+// NewLinkService signs the tokens with the secret. A random one is used when
+// it is empty, so the links do not outlive the process.
+func NewLinkService(repository Repository, links LinkRepository, secret string) LinkService {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, secretSize)
+		if _, err := rand.Read(key); err != nil {
+			panic(fmt.Sprintf("failed to generate share link secret: %v", err))
+		}
+	}
+
+	return &linkService{
+		repository: repository,
+		links:      links,
+		secret:     key,
+	}
+}
+
+func (s linkService) Create(ctx context.Context, id string, request LinkRequest) (*NewLink, error) {
+	if err := s.checkSharer(ctx, id); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	link := Link{
+		ID:          uuid.New().String(),
+		DrawingID:   id,
+		WorkspaceID: workspace.ID(ctx),
+		Permission:  request.Permission,
+		CreatedBy:   auth.OwnerID(ctx),
+		ExpiresAt:   now.Add(request.Lifetime()).Truncate(time.Second),
+		CreatedAt:   now,
+	}
+
+	token, err := SignLink(s.secret, link)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign link: %w", err)
+	}
+
+	if err := s.links.SaveLink(ctx, link); err != nil {
+		return nil, fmt.Errorf("failed to save link of '%s': %w", id, err)
+	}
+	return &NewLink{Link: link, Token: token}, nil
+}
+
+func (s linkService) GetLinks(ctx context.Context, id string) ([]Link, error) {
+	if err := s.checkSharer(ctx, id); err != nil {
+		return nil, err
+	}
+
+	links, err := s.links.GetLinks(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the links of '%s': %w", id, err)
+	}
+	return links, nil
+}
+
+func (s linkService) Revoke(ctx context.Context, id string, linkID string) error {
+	if err := s.checkSharer(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.links.RevokeLink(ctx, id, linkID); err != nil {
+		return fmt.Errorf("failed to revoke link '%s': %w", linkID, err)
+	}
+	return nil
+}
+
+func (s linkService) Open(ctx context.Context, id string, token string) (context.Context, error) {
+	claims, err := verifyLink(s.secret, token, time.Now())
+	if err != nil {
+		return ctx, err
+	}
+
+	if claims.DrawingID != id {
+		return ctx, ErrInvalidToken
+	}
+
+	link, err := s.links.GetLink(ctx, claims.LinkID)
+	if goerrors.Is(err, ErrLinkNotFound) {
+		return ctx, ErrInvalidToken
+	}
+
+	if err != nil {
+		return ctx, fmt.Errorf("failed to get link '%s': %w", claims.LinkID, err)
+	}
+
+	if link.RevokedAt != nil || link.DrawingID != id {
+		return ctx, ErrInvalidToken
+	}
+
+	role := workspace.RoleViewer
+	if link.Permission == PermissionEdit {
+		role = workspace.RoleEditor
+	}
+	ctx = workspace.WithScope(ctx, workspace.Scope{WorkspaceID: link.WorkspaceID, Role: role})
+	return WithLink(ctx, link), nil
+}
+
+// checkSharer fails unless the request may share the canvas.
+func (s linkService) checkSharer(ctx context.Context, id string) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return auth.ErrKeyRequired
+	}
+
+	ownerID, err := s.repository.GetOwner(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s': %w", id, err)
+	}
+
+	if workspace.ID(ctx) != "" {
+		return workspace.Check(ctx, workspace.RoleEditor)
+	}
+
+	if ownerID == "" || ownerID != principal.OwnerID {
+		return ErrNotOwner
+	}
+	return nil
+}
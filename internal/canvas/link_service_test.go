@@ -0,0 +1,191 @@
+package canvas_test
+
+import (
+	"context"
+	"sketch/internal/auth"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"sketch/internal/workspace"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const linkSecret = "a very secret secret"
+
+func newLinkService(t *testing.T) (canvas.LinkService, *mock_canvas.MockRepository, *mock_canvas.MockLinkRepository) {
+	ctrl := gomock.NewController(t)
+	repositoryMock := mock_canvas.NewMockRepository(ctrl)
+	linksMock := mock_canvas.NewMockLinkRepository(ctrl)
+	return canvas.NewLinkService(repositoryMock, linksMock, linkSecret), repositoryMock, linksMock
+}
+
+func TestLinkService_Create(t *testing.T) {
+	owner := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+	other := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "2", OwnerID: "other"})
+
+	t.Run("when the owner creates a link, should save it and sign its token", func(t *testing.T) {
+		service, repositoryMock, linksMock := newLinkService(t)
+
+		repositoryMock.EXPECT().GetOwner(owner, "123").Return("owner", nil)
+		linksMock.EXPECT().SaveLink(owner, gomock.Any()).Return(nil)
+
+		link, err := service.Create(owner, "123", canvas.LinkRequest{Permission: canvas.PermissionEdit, ExpiresIn: 60})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "123", link.DrawingID)
+		assert.Equal(t, "owner", link.CreatedBy)
+		assert.Equal(t, canvas.PermissionEdit, link.Permission)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), link.ExpiresAt, 2*time.Second)
+		assert.NotEmpty(t, link.Token)
+	})
+
+	t.Run("when someone else creates a link, should forbid it", func(t *testing.T) {
+		service, repositoryMock, _ := newLinkService(t)
+
+		repositoryMock.EXPECT().GetOwner(other, "123").Return("owner", nil)
+
+		_, err := service.Create(other, "123", canvas.LinkRequest{Permission: canvas.PermissionRead})
+		assert.ErrorIs(t, err, canvas.ErrNotOwner)
+	})
+
+	t.Run("when an editor of the workspace creates a link, should keep the workspace in it", func(t *testing.T) {
+		service, repositoryMock, linksMock := newLinkService(t)
+		ctx := workspace.WithScope(other, workspace.Scope{WorkspaceID: "ws", Role: workspace.RoleEditor})
+
+		repositoryMock.EXPECT().GetOwner(ctx, "123").Return("owner", nil)
+		linksMock.EXPECT().SaveLink(ctx, gomock.Any()).Return(nil)
+
+		link, err := service.Create(ctx, "123", canvas.LinkRequest{Permission: canvas.PermissionRead})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ws", link.WorkspaceID)
+	})
+
+	t.Run("when the request is anonymous, should require a key", func(t *testing.T) {
+		service, _, _ := newLinkService(t)
+
+		_, err := service.Create(context.Background(), "123", canvas.LinkRequest{Permission: canvas.PermissionRead})
+		assert.ErrorIs(t, err, auth.ErrKeyRequired)
+	})
+}
+
+func TestLinkService_Open(t *testing.T) {
+	link := canvas.Link{
+		ID:          "link",
+		DrawingID:   "123",
+		WorkspaceID: "ws",
+		Permission:  canvas.PermissionRead,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	token, err := canvas.SignLink([]byte(linkSecret), link)
+	assert.NoError(t, err)
+
+	t.Run("when the token is valid, should open the workspace of the canvas as a viewer", func(t *testing.T) {
+		service, _, linksMock := newLinkService(t)
+
+		linksMock.EXPECT().GetLink(gomock.Any(), "link").Return(link, nil)
+
+		ctx, err := service.Open(context.Background(), "123", token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, workspace.Scope{WorkspaceID: "ws", Role: workspace.RoleViewer}, workspace.FromContext(ctx))
+		opened, ok := canvas.LinkFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "link", opened.ID)
+	})
+
+	t.Run("when the token is of an edit link, should open it as an editor", func(t *testing.T) {
+		service, _, linksMock := newLinkService(t)
+		edit := link
+		edit.Permission = canvas.PermissionEdit
+		token, err := canvas.SignLink([]byte(linkSecret), edit)
+		assert.NoError(t, err)
+
+		linksMock.EXPECT().GetLink(gomock.Any(), "link").Return(edit, nil)
+
+		ctx, err := service.Open(context.Background(), "123", token)
+
+		assert.NoError(t, err)
+		assert.Equal(t, workspace.RoleEditor, workspace.FromContext(ctx).Role)
+	})
+
+	t.Run("when the token was signed with another secret, should reject it", func(t *testing.T) {
+		service, _, _ := newLinkService(t)
+		forged, err := canvas.SignLink([]byte("another secret"), link)
+		assert.NoError(t, err)
+
+		_, err = service.Open(context.Background(), "123", forged)
+		assert.ErrorIs(t, err, canvas.ErrInvalidToken)
+	})
+
+	t.Run("when the token is not one, should reject it", func(t *testing.T) {
+		service, _, _ := newLinkService(t)
+
+		_, err := service.Open(context.Background(), "123", "not-a-token")
+		assert.ErrorIs(t, err, canvas.ErrInvalidToken)
+	})
+
+	t.Run("when the token is of another canvas, should reject it", func(t *testing.T) {
+		service, _, _ := newLinkService(t)
+
+		_, err := service.Open(context.Background(), "456", token)
+		assert.ErrorIs(t, err, canvas.ErrInvalidToken)
+	})
+
+	t.Run("when the token expired, should reject it", func(t *testing.T) {
+		service, _, _ := newLinkService(t)
+		expired := link
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		token, err := canvas.SignLink([]byte(linkSecret), expired)
+		assert.NoError(t, err)
+
+		_, err = service.Open(context.Background(), "123", token)
+		assert.ErrorIs(t, err, canvas.ErrTokenExpired)
+	})
+
+	t.Run("when the link was revoked, should reject it", func(t *testing.T) {
+		service, _, linksMock := newLinkService(t)
+		revoked := link
+		revokedAt := time.Now()
+		revoked.RevokedAt = &revokedAt
+
+		linksMock.EXPECT().GetLink(gomock.Any(), "link").Return(revoked, nil)
+
+		_, err := service.Open(context.Background(), "123", token)
+		assert.ErrorIs(t, err, canvas.ErrInvalidToken)
+	})
+
+	t.Run("when the link does not exist, should reject it", func(t *testing.T) {
+		service, _, linksMock := newLinkService(t)
+
+		linksMock.EXPECT().GetLink(gomock.Any(), "link").Return(canvas.Link{}, canvas.ErrLinkNotFound)
+
+		_, err := service.Open(context.Background(), "123", token)
+		assert.ErrorIs(t, err, canvas.ErrInvalidToken)
+	})
+}
+
+func TestLinkService_Revoke(t *testing.T) {
+	owner := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+
+	t.Run("when the owner revokes a link, should revoke it", func(t *testing.T) {
+		service, repositoryMock, linksMock := newLinkService(t)
+
+		repositoryMock.EXPECT().GetOwner(owner, "123").Return("owner", nil)
+		linksMock.EXPECT().RevokeLink(owner, "123", "link").Return(nil)
+
+		assert.NoError(t, service.Revoke(owner, "123", "link"))
+	})
+
+	t.Run("when the link does not exist, should return not found", func(t *testing.T) {
+		service, repositoryMock, linksMock := newLinkService(t)
+
+		repositoryMock.EXPECT().GetOwner(owner, "123").Return("owner", nil)
+		linksMock.EXPECT().RevokeLink(owner, "123", "link").Return(canvas.ErrLinkNotFound)
+
+		assert.ErrorIs(t, service.Revoke(owner, "123", "link"), canvas.ErrLinkNotFound)
+	})
+}
@@ -35,19 +35,33 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
-// GetAll mocks base method.
-func (m *MockRepository) GetAll(ctx context.Context) ([]canvas.Canvas, error) {
+// CountByOwner mocks base method.
+func (m *MockRepository) CountByOwner(ctx context.Context, ownerID string) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", ctx)
-	ret0, _ := ret[0].([]canvas.Canvas)
+	ret := m.ctrl.Call(m, "CountByOwner", ctx, ownerID)
+	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAll indicates an expected call of GetAll.
-func (mr *MockRepositoryMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+// CountByOwner indicates an expected call of CountByOwner.
+func (mr *MockRepositoryMockRecorder) CountByOwner(ctx, ownerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockRepository)(nil).GetAll), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByOwner", reflect.TypeOf((*MockRepository)(nil).CountByOwner), ctx, ownerID)
+}
+
+// Delete mocks base method.
+func (m *MockRepository) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, id)
 }
 
 // GetByID mocks base method.
@@ -65,6 +79,36 @@ func (mr *MockRepositoryMockRecorder) GetByID(ctx, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockRepository)(nil).GetByID), ctx, id)
 }
 
+// GetOwner mocks base method.
+func (m *MockRepository) GetOwner(ctx context.Context, id string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOwner", ctx, id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOwner indicates an expected call of GetOwner.
+func (mr *MockRepositoryMockRecorder) GetOwner(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOwner", reflect.TypeOf((*MockRepository)(nil).GetOwner), ctx, id)
+}
+
+// GetViewport mocks base method.
+func (m *MockRepository) GetViewport(ctx context.Context, id string, viewport canvas.Viewport) (canvas.Canvas, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetViewport", ctx, id, viewport)
+	ret0, _ := ret[0].(canvas.Canvas)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetViewport indicates an expected call of GetViewport.
+func (mr *MockRepositoryMockRecorder) GetViewport(ctx, id, viewport interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetViewport", reflect.TypeOf((*MockRepository)(nil).GetViewport), ctx, id, viewport)
+}
+
 // Save mocks base method.
 func (m *MockRepository) Save(ctx context.Context, canvas canvas.Canvas) error {
 	m.ctrl.T.Helper()
@@ -78,3 +122,17 @@ func (mr *MockRepositoryMockRecorder) Save(ctx, canvas interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRepository)(nil).Save), ctx, canvas)
 }
+
+// Update mocks base method.
+func (m *MockRepository) Update(ctx context.Context, canvas canvas.Canvas) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, canvas)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockRepositoryMockRecorder) Update(ctx, canvas interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, canvas)
+}
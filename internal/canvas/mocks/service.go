@@ -35,19 +35,48 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
-// GetAll mocks base method.
-func (m *MockService) GetAll(ctx context.Context) ([]canvas.Canvas, error) {
+// Crop mocks base method.
+func (m *MockService) Crop(ctx context.Context, id string, viewport canvas.Viewport) (*canvas.DrawResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", ctx)
-	ret0, _ := ret[0].([]canvas.Canvas)
+	ret := m.ctrl.Call(m, "Crop", ctx, id, viewport)
+	ret0, _ := ret[0].(*canvas.DrawResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAll indicates an expected call of GetAll.
-func (mr *MockServiceMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+// Crop indicates an expected call of Crop.
+func (mr *MockServiceMockRecorder) Crop(ctx, id, viewport interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockService)(nil).GetAll), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Crop", reflect.TypeOf((*MockService)(nil).Crop), ctx, id, viewport)
+}
+
+// Delete mocks base method.
+func (m *MockService) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockServiceMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), ctx, id)
+}
+
+// Edit mocks base method.
+func (m *MockService) Edit(ctx context.Context, id string, requests canvas.DrawRequests) (*canvas.DrawResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Edit", ctx, id, requests)
+	ret0, _ := ret[0].(*canvas.DrawResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Edit indicates an expected call of Edit.
+func (mr *MockServiceMockRecorder) Edit(ctx, id, requests interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Edit", reflect.TypeOf((*MockService)(nil).Edit), ctx, id, requests)
 }
 
 // GetByID mocks base method.
@@ -65,6 +94,36 @@ func (mr *MockServiceMockRecorder) GetByID(ctx, id interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockService)(nil).GetByID), ctx, id)
 }
 
+// GetViewport mocks base method.
+func (m *MockService) GetViewport(ctx context.Context, id string, viewport canvas.Viewport) (*canvas.Canvas, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetViewport", ctx, id, viewport)
+	ret0, _ := ret[0].(*canvas.Canvas)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetViewport indicates an expected call of GetViewport.
+func (mr *MockServiceMockRecorder) GetViewport(ctx, id, viewport interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetViewport", reflect.TypeOf((*MockService)(nil).GetViewport), ctx, id, viewport)
+}
+
+// Import mocks base method.
+func (m *MockService) Import(ctx context.Context, drawing string) (*canvas.DrawResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, drawing)
+	ret0, _ := ret[0].(*canvas.DrawResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockServiceMockRecorder) Import(ctx, drawing interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockService)(nil).Import), ctx, drawing)
+}
+
 // Save mocks base method.
 func (m *MockService) Save(ctx context.Context, requests canvas.DrawRequests) (*canvas.DrawResponse, error) {
 	m.ctrl.T.Helper()
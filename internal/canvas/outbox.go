@@ -0,0 +1,114 @@
+package canvas
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	// Outbox keeps the events in the database, saved in the same transaction
+	// as the change they describe, until the Relay has handed them to every
+	// sink.
+	Outbox interface {
+		// Record runs the change and saves the event in a single transaction.
+		// The change must make its queries with the context it is given.
+		Record(ctx context.Context, event Event, change func(ctx context.Context) error) error
+		// Recorded is signalled when this process records events.
+		Recorded() <-chan struct{}
+		Claim(ctx context.Context, now time.Time, until time.Time, limit int) ([]Event, error)
+		GetDelivered(ctx context.Context, eventID string) ([]string, error)
+		SaveDelivered(ctx context.Context, eventID string, sink string) error
+		MarkPublished(ctx context.Context, eventID string) error
+	}
+
+	outbox struct {
+		db       *sqlx.DB
+		recorded chan struct{}
+	}
+)
+
+func NewOutbox(db *sqlx.DB) Outbox {
+	return &outbox{
+		db:       db,
+		recorded: make(chan struct{}, 1),
+	}
+}
+
+func (o *outbox) Record(ctx context.Context, event Event, change func(ctx context.Context) error) error {
+	tx, err := beginTx(ctx, o.db)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := change(withTx(ctx, tx.Tx)); err != nil {
+		return err
+	}
+
+	const query = "insert into outbox_events (id, event_type, canvas_id, owner_id, drawing, created_at) " +
+		"values (:id, :event_type, :canvas_id, :owner_id, :drawing, :created_at)"
+	if _, err := tx.NamedExecContext(ctx, query, event); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	select {
+	case o.recorded <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (o *outbox) Recorded() <-chan struct{} {
+	return o.recorded
+}
+
+// Claim returns the unpublished events, in the order they were recorded, and
+// holds them until the given time so that other relays skip them. Events a
+// relay fails to publish are claimed again once released.
+func (o *outbox) Claim(ctx context.Context, now time.Time, until time.Time, limit int) ([]Event, error) {
+	const query = "with due as (" +
+		"select position from outbox_events where published_at is null " +
+		"and (locked_until is null or locked_until <= $1) order by position limit $3 for update skip locked" +
+		"), claimed as (" +
+		"update outbox_events e set locked_until = $2 from due where e.position = due.position returning e.*" +
+		") select id, event_type, canvas_id, owner_id, drawing, created_at from claimed order by position"
+	var events []Event
+	if err := o.db.SelectContext(ctx, &events, query, now, until, limit); err != nil {
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+	return events, nil
+}
+
+// GetDelivered returns the sinks the event was already handed to.
+func (o *outbox) GetDelivered(ctx context.Context, eventID string) ([]string, error) {
+	const query = "select sink from outbox_deliveries where event_id = $1"
+	var sinks []string
+	if err := o.db.SelectContext(ctx, &sinks, query, eventID); err != nil {
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+	return sinks, nil
+}
+
+func (o *outbox) SaveDelivered(ctx context.Context, eventID string, sink string) error {
+	const query = "insert into outbox_deliveries (event_id, sink, delivered_at) values ($1, $2, $3) " +
+		"on conflict do nothing"
+	if _, err := o.db.ExecContext(ctx, query, eventID, sink, time.Now().UTC()); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+func (o *outbox) MarkPublished(ctx context.Context, eventID string) error {
+	const query = "update outbox_events set published_at = $2, locked_until = null where id = $1"
+	if _, err := o.db.ExecContext(ctx, query, eventID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
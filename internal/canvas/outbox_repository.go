@@ -0,0 +1,75 @@
+package canvas
+
+import (
+	"context"
+)
+
+// outboxRepository records the event of every change made through it in the
+// outbox, so that a saved change is never left without its event, nor an
+// event published for a change that was not saved.
+type outboxRepository struct {
+	Repository
+	outbox Outbox
+}
+
+// outboxRevisionRepository records the events of a RevisionRepository, whose
+// revisions are then written in the transaction of the change and its event.
+type outboxRevisionRepository struct {
+	*outboxRepository
+	revisions RevisionRepository
+}
+
+// NewOutboxRevisionRepository decorates a RevisionRepository like
+// NewOutboxRepository does, keeping its revisions at hand.
+func NewOutboxRevisionRepository(repository RevisionRepository, outbox Outbox) RevisionRepository {
+	return &outboxRevisionRepository{
+		outboxRepository: &outboxRepository{Repository: repository, outbox: outbox},
+		revisions:        repository,
+	}
+}
+
+func (r *outboxRevisionRepository) GetRevisions(ctx context.Context, id string) ([]Revision, error) {
+	return r.revisions.GetRevisions(ctx, id)
+}
+
+// NewOutboxRepository decorates a Repository whose changes run in the
+// transaction of their context.
+func NewOutboxRepository(repository Repository, outbox Outbox) Repository {
+	return &outboxRepository{
+		Repository: repository,
+		outbox:     outbox,
+	}
+}
+
+func (r *outboxRepository) Save(ctx context.Context, canvas Canvas) error {
+	return r.outbox.Record(ctx, NewEvent(EventCreated, canvas), func(ctx context.Context) error {
+		return r.Repository.Save(ctx, canvas)
+	})
+}
+
+// Update and Delete look the owner up when they are not given it, for the
+// event to reach the webhooks of the owner.
+func (r *outboxRepository) Update(ctx context.Context, canvas Canvas) error {
+	if canvas.OwnerID == "" {
+		ownerID, err := r.Repository.GetOwner(ctx, canvas.ID)
+		if err != nil {
+			return err
+		}
+		canvas.OwnerID = ownerID
+	}
+
+	return r.outbox.Record(ctx, NewEvent(EventUpdated, canvas), func(ctx context.Context) error {
+		return r.Repository.Update(ctx, canvas)
+	})
+}
+
+func (r *outboxRepository) Delete(ctx context.Context, id string) error {
+	ownerID, err := r.Repository.GetOwner(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return r.outbox.Record(ctx, NewEvent(EventDeleted, Canvas{ID: id, OwnerID: ownerID}), func(ctx context.Context) error {
+		return r.Repository.Delete(ctx, id)
+	})
+}
@@ -0,0 +1,213 @@
+package canvas_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sketch/internal/canvas"
+	"sketch/tests/faker"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxRepository(t *testing.T) {
+	const (
+		saveDrawing = "insert into drawings (id, owner_id, workspace_id, drawing, created_at) values (?, ?, ?, ?, ?)"
+		saveEvent   = "insert into outbox_events (id, event_type, canvas_id, owner_id, drawing, created_at) values (?, ?, ?, ?, ?, ?)"
+		saveMeta    = "insert into chunked_drawings (id, owner_id, workspace_id, width, height, created_at) values ($1, $2, $3, $4, $5, $6)"
+		saveChunk   = "insert into drawing_chunks (drawing_id, chunk_x, chunk_y, content) values ($1, $2, $3, $4) " +
+			"on conflict (drawing_id, chunk_x, chunk_y) do update set content = excluded.content"
+	)
+	setup := func() (*sqlx.DB, canvas.Outbox, sqlmock.Sqlmock) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		db := sqlx.NewDb(mockDB, "sqlmock")
+		return db, canvas.NewOutbox(db), mock
+	}
+
+	t.Run("when the canvas is saved, should record its event in the same transaction", func(t *testing.T) {
+		db, outbox, mock := setup()
+		repository := canvas.NewOutboxRepository(canvas.NewRepository(db), outbox)
+		fakeCanvas := faker.NewCanvas(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(saveDrawing).
+			WithArgs(fakeCanvas.ID, fakeCanvas.OwnerID, fakeCanvas.WorkspaceID, fakeCanvas.Drawing, fakeCanvas.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(saveEvent).
+			WithArgs(sqlmock.AnyArg(), canvas.EventCreated, fakeCanvas.ID, fakeCanvas.OwnerID, fakeCanvas.Drawing, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repository.Save(context.Background(), fakeCanvas)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when saving the canvas fails, should record no event", func(t *testing.T) {
+		db, outbox, mock := setup()
+		repository := canvas.NewOutboxRepository(canvas.NewRepository(db), outbox)
+		fakeCanvas := faker.NewCanvas(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(saveDrawing).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		err := repository.Save(context.Background(), fakeCanvas)
+
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when recording the event fails, should not save the canvas", func(t *testing.T) {
+		db, outbox, mock := setup()
+		repository := canvas.NewOutboxRepository(canvas.NewRepository(db), outbox)
+		fakeCanvas := faker.NewCanvas(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(saveDrawing).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(saveEvent).WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		err := repository.Save(context.Background(), fakeCanvas)
+
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when the repository uses transactions, should join the one of the outbox", func(t *testing.T) {
+		db, outbox, mock := setup()
+		repository := canvas.NewOutboxRepository(canvas.NewChunkedRepository(db), outbox)
+		fakeCanvas := faker.NewCanvas(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(saveMeta).
+			WithArgs(fakeCanvas.ID, fakeCanvas.OwnerID, fakeCanvas.WorkspaceID, 2, 1, fakeCanvas.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(saveChunk).
+			WithArgs(fakeCanvas.ID, 0, 0, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(saveEvent).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repository.Save(context.Background(), fakeCanvas)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when the canvas is deleted, should record a deleted event for its owner", func(t *testing.T) {
+		db, outbox, mock := setup()
+		repository := canvas.NewOutboxRepository(canvas.NewRepository(db), outbox)
+
+		mock.ExpectQuery("select owner_id from drawings where id = $1 and workspace_id = $2").
+			WithArgs("123", "").
+			WillReturnRows(sqlmock.NewRows([]string{"owner_id"}).AddRow("owner"))
+		mock.ExpectBegin()
+		mock.ExpectExec("delete from drawings where id = $1 and workspace_id = $2").
+			WithArgs("123", "").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(saveEvent).
+			WithArgs(sqlmock.AnyArg(), canvas.EventDeleted, "123", "owner", "", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repository.Delete(context.Background(), "123")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, outbox.Recorded(), 1)
+	})
+
+	t.Run("when the canvas to delete does not exist, should record no event", func(t *testing.T) {
+		db, outbox, mock := setup()
+		repository := canvas.NewOutboxRepository(canvas.NewRepository(db), outbox)
+
+		mock.ExpectQuery("select owner_id from drawings where id = $1 and workspace_id = $2").
+			WithArgs("123", "").
+			WillReturnError(sql.ErrNoRows)
+
+		err := repository.Delete(context.Background(), "123")
+
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Len(t, outbox.Recorded(), 0)
+	})
+
+	t.Run("when the canvas keeps revisions, should write them in the transaction of the event", func(t *testing.T) {
+		db, outbox, mock := setup()
+		repository := canvas.NewOutboxRevisionRepository(canvas.NewRevisionRepository(canvas.NewRepository(db), db), outbox)
+		fakeCanvas := faker.NewCanvas(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(saveDrawing).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("insert into drawing_revisions (drawing_id, drawing, created_at) values ($1, $2, $3)").
+			WithArgs(fakeCanvas.ID, fakeCanvas.Drawing, fakeCanvas.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(saveEvent).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repository.Save(context.Background(), fakeCanvas)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when the canvas keeps revisions, should delete them and its frames in the transaction of the event", func(t *testing.T) {
+		db, outbox, mock := setup()
+		repository := canvas.NewOutboxRevisionRepository(canvas.NewRevisionRepository(canvas.NewRepository(db), db), outbox)
+
+		mock.ExpectQuery("select owner_id from drawings where id = $1 and workspace_id = $2").
+			WithArgs("123", "").
+			WillReturnRows(sqlmock.NewRows([]string{"owner_id"}).AddRow("owner"))
+		mock.ExpectBegin()
+		mock.ExpectExec("delete from drawings where id = $1 and workspace_id = $2").
+			WithArgs("123", "").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("delete from drawing_revisions where drawing_id = $1").
+			WithArgs("123").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("delete from drawing_frames where drawing_id = $1").
+			WithArgs("123").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(saveEvent).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repository.Delete(context.Background(), "123")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestOutbox_SaveDelivered(t *testing.T) {
+	t.Run("when the sink already got the event, should do nothing", func(t *testing.T) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		outbox := canvas.NewOutbox(sqlx.NewDb(mockDB, "sqlmock"))
+
+		mock.ExpectExec("insert into outbox_deliveries (event_id, sink, delivered_at) values ($1, $2, $3) on conflict do nothing").
+			WithArgs("event", "webhooks", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := outbox.SaveDelivered(context.Background(), "event", "webhooks")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when there is an error saving, should return it", func(t *testing.T) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		outbox := canvas.NewOutbox(sqlx.NewDb(mockDB, "sqlmock"))
+		fakeErr := fmt.Errorf("fake: %w", sql.ErrConnDone)
+
+		mock.ExpectExec("insert into outbox_deliveries (event_id, sink, delivered_at) values ($1, $2, $3) on conflict do nothing").
+			WillReturnError(fakeErr)
+
+		err := outbox.SaveDelivered(context.Background(), "event", "webhooks")
+
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+	})
+}
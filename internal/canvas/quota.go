@@ -0,0 +1,22 @@
+package canvas
+
+import (
+	"sketch/internal/errors"
+)
+
+const (
+	// HeaderQuotaLimit and HeaderQuotaUsed tell how many canvases the owner
+	// may keep and how many it has, in the responses creating them.
+	HeaderQuotaLimit = "X-Quota-Limit"
+	HeaderQuotaUsed  = "X-Quota-Used"
+)
+
+var (
+	ErrQuotaExceeded = errors.Error("the owner has all the canvases its quota allows, delete some to create others")
+)
+
+// Usage is how many canvases an owner has, out of those its quota allows.
+type Usage struct {
+	Limit int `json:"limit"`
+	Used  int `json:"used"`
+}
@@ -0,0 +1,46 @@
+package canvas
+
+import (
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"sketch/internal/routing"
+	"strconv"
+)
+
+type QuotaHandler struct {
+	service QuotaService
+}
+
+func NewQuotaHandler(service QuotaService) *QuotaHandler {
+	return &QuotaHandler{
+		service: service,
+	}
+}
+
+// Create wraps the handlers creating canvases, refusing with a 429 the owners
+// out of quota, checked again when the canvas is saved. The responses tell the
+// quota and how much of it is used.
+func (c *QuotaHandler) Create(next routing.Handle) routing.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+		usage, err := c.service.Check(r.Context())
+		if usage.Limit > 0 {
+			w.Header().Set(HeaderQuotaLimit, strconv.Itoa(usage.Limit))
+			w.Header().Set(HeaderQuotaUsed, strconv.Itoa(usage.Used))
+		}
+
+		if errors.Is(err, ErrQuotaExceeded) {
+			return routing.TooManyRequests(w, err)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		err = next(w, r, params)
+		if errors.Is(err, ErrQuotaExceeded) {
+			return routing.TooManyRequests(w, err)
+		}
+		return err
+	}
+}
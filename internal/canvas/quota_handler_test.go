@@ -0,0 +1,69 @@
+package canvas_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaHandler_Create(t *testing.T) {
+	next := func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) error {
+		w.WriteHeader(http.StatusCreated)
+		return nil
+	}
+
+	t.Run("when the owner is under its quota, should create and tell the usage", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockQuotaService(ctrl)
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+		serviceMock.EXPECT().Check(gomock.Any()).Return(canvas.Usage{Limit: 10, Used: 3}, nil)
+
+		err := canvas.NewQuotaHandler(serviceMock).Create(next)(w, req, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "10", w.Header().Get(canvas.HeaderQuotaLimit))
+		assert.Equal(t, "3", w.Header().Get(canvas.HeaderQuotaUsed))
+	})
+
+	t.Run("when the owner reached its quota, should return too many requests", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockQuotaService(ctrl)
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+
+		serviceMock.EXPECT().Check(gomock.Any()).Return(canvas.Usage{Limit: 10, Used: 10}, canvas.ErrQuotaExceeded)
+
+		err := canvas.NewQuotaHandler(serviceMock).Create(next)(w, req, nil)
+
+		assert.ErrorIs(t, err, canvas.ErrQuotaExceeded)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "10", w.Header().Get(canvas.HeaderQuotaUsed))
+	})
+
+	t.Run("when the quota is reached while creating, should return too many requests", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockQuotaService(ctrl)
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		raced := func(http.ResponseWriter, *http.Request, httprouter.Params) error {
+			return fmt.Errorf("failed to save: %w", canvas.ErrQuotaExceeded)
+		}
+
+		serviceMock.EXPECT().Check(gomock.Any()).Return(canvas.Usage{Limit: 10, Used: 9}, nil)
+
+		err := canvas.NewQuotaHandler(serviceMock).Create(raced)(w, req, nil)
+
+		assert.ErrorIs(t, err, canvas.ErrQuotaExceeded)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+}
@@ -0,0 +1,66 @@
+package canvas
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// quotaRepository refuses to save the canvases of the owners out of quota. It
+// counts them and saves the new one in a single transaction, holding a lock on
+// the owner, so that requests racing each other cannot go over the quota.
+type quotaRepository struct {
+	RevisionRepository
+	db    *sqlx.DB
+	limit int
+}
+
+// NewQuotaRepository lets each owner keep up to limit canvases, as many as it
+// wants when it is zero. The repository must run its changes in the
+// transaction of their context.
+func NewQuotaRepository(repository RevisionRepository, db *sqlx.DB, limit int) RevisionRepository {
+	return &quotaRepository{
+		RevisionRepository: repository,
+		db:                 db,
+		limit:              limit,
+	}
+}
+
+func (r *quotaRepository) Save(ctx context.Context, canvas Canvas) error {
+	if r.limit <= 0 || canvas.OwnerID == "" {
+		return r.RevisionRepository.Save(ctx, canvas)
+	}
+
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// The owner has no row of its own, so the lock is an advisory one on its
+	// id, released with the transaction.
+	const lockOwner = "select pg_advisory_xact_lock(hashtext($1))"
+	if _, err := tx.ExecContext(ctx, lockOwner, canvas.OwnerID); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	ctx = withTx(ctx, tx.Tx)
+	count, err := r.RevisionRepository.CountByOwner(ctx, canvas.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	if count >= r.limit {
+		return ErrQuotaExceeded
+	}
+
+	if err := r.RevisionRepository.Save(ctx, canvas); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package canvas_test
+
+import (
+	"context"
+	"sketch/internal/canvas"
+	"sketch/tests/faker"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaRepository_Save(t *testing.T) {
+	const (
+		lockOwner   = "select pg_advisory_xact_lock(hashtext($1))"
+		countOwned  = "select count(*) from drawings where owner_id = $1"
+		saveDrawing = "insert into drawings (id, owner_id, workspace_id, drawing, created_at) values (?, ?, ?, ?, ?)"
+		saveEvent   = "insert into outbox_events (id, event_type, canvas_id, owner_id, drawing, created_at) values (?, ?, ?, ?, ?, ?)"
+	)
+	setup := func(limit int) (canvas.Repository, sqlmock.Sqlmock) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		db := sqlx.NewDb(mockDB, "sqlmock")
+		repository := canvas.NewOutboxRevisionRepository(canvas.NewRevisionRepository(canvas.NewRepository(db), db), canvas.NewOutbox(db))
+		return canvas.NewQuotaRepository(repository, db, limit), mock
+	}
+
+	t.Run("when the owner is under its quota, should count and save in one transaction", func(t *testing.T) {
+		repository, mock := setup(2)
+		fakeCanvas := faker.NewCanvas(t)
+		fakeCanvas.OwnerID = "owner"
+
+		mock.ExpectBegin()
+		mock.ExpectExec(lockOwner).WithArgs(fakeCanvas.OwnerID).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(countOwned).
+			WithArgs(fakeCanvas.OwnerID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectExec(saveDrawing).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("insert into drawing_revisions (drawing_id, drawing, created_at) values ($1, $2, $3)").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(saveEvent).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repository.Save(context.Background(), fakeCanvas)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when the owner reached its quota, should save nothing", func(t *testing.T) {
+		repository, mock := setup(2)
+		fakeCanvas := faker.NewCanvas(t)
+		fakeCanvas.OwnerID = "owner"
+
+		mock.ExpectBegin()
+		mock.ExpectExec(lockOwner).WithArgs(fakeCanvas.OwnerID).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(countOwned).
+			WithArgs(fakeCanvas.OwnerID).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+		mock.ExpectRollback()
+
+		err := repository.Save(context.Background(), fakeCanvas)
+
+		assert.ErrorIs(t, err, canvas.ErrQuotaExceeded)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when there is no quota, should save without counting", func(t *testing.T) {
+		repository, mock := setup(0)
+		fakeCanvas := faker.NewCanvas(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(saveDrawing).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("insert into drawing_revisions (drawing_id, drawing, created_at) values ($1, $2, $3)").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(saveEvent).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repository.Save(context.Background(), fakeCanvas)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,51 @@
+package canvas
+
+import (
+	"context"
+	"fmt"
+	"sketch/internal/auth"
+)
+
+type (
+	// QuotaService caps how many canvases each owner keeps, in all the
+	// workspaces. Canvases without an owner are not counted.
+	QuotaService interface {
+		// Check fails with ErrQuotaExceeded when the owner of the request may
+		// not create another canvas. The usage is empty without a quota. It
+		// only tells it early: the repository of NewQuotaRepository is the one
+		// enforcing the quota, when the canvas is saved.
+		Check(ctx context.Context) (Usage, error)
+	}
+
+	quotaService struct {
+		repository Repository
+		limit      int
+	}
+)
+
+// NewQuotaService lets each owner keep up to limit canvases, as many as it
+// wants when it is zero.
+func NewQuotaService(repository Repository, limit int) QuotaService {
+	return &quotaService{
+		repository: repository,
+		limit:      limit,
+	}
+}
+
+func (s quotaService) Check(ctx context.Context) (Usage, error) {
+	ownerID := auth.OwnerID(ctx)
+	if s.limit <= 0 || ownerID == "" {
+		return Usage{}, nil
+	}
+
+	count, err := s.repository.CountByOwner(ctx, ownerID)
+	if err != nil {
+		return Usage{}, fmt.Errorf("failed to count the canvases of '%s': %w", ownerID, err)
+	}
+
+	usage := Usage{Limit: s.limit, Used: count}
+	if count >= s.limit {
+		return usage, ErrQuotaExceeded
+	}
+	return usage, nil
+}
@@ -0,0 +1,63 @@
+package canvas_test
+
+import (
+	"context"
+	"errors"
+	"sketch/internal/auth"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaService_Check(t *testing.T) {
+	setup := func(t *testing.T, limit int) (canvas.QuotaService, *mock_canvas.MockRepository) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		return canvas.NewQuotaService(repositoryMock, limit), repositoryMock
+	}
+	owner := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+
+	t.Run("when the owner is under its quota, should return its usage", func(t *testing.T) {
+		service, repositoryMock := setup(t, 10)
+
+		repositoryMock.EXPECT().CountByOwner(owner, "owner").Return(9, nil)
+
+		usage, err := service.Check(owner)
+
+		assert.NoError(t, err)
+		assert.Equal(t, canvas.Usage{Limit: 10, Used: 9}, usage)
+	})
+
+	t.Run("when the owner reached its quota, should refuse it", func(t *testing.T) {
+		service, repositoryMock := setup(t, 10)
+
+		repositoryMock.EXPECT().CountByOwner(owner, "owner").Return(10, nil)
+
+		usage, err := service.Check(owner)
+
+		assert.ErrorIs(t, err, canvas.ErrQuotaExceeded)
+		assert.Equal(t, canvas.Usage{Limit: 10, Used: 10}, usage)
+	})
+
+	t.Run("when there is no quota, should not count the canvases", func(t *testing.T) {
+		service, _ := setup(t, 0)
+
+		usage, err := service.Check(owner)
+
+		assert.NoError(t, err)
+		assert.Equal(t, canvas.Usage{}, usage)
+	})
+
+	t.Run("when counting the canvases fails, should return the error", func(t *testing.T) {
+		service, repositoryMock := setup(t, 10)
+		fakeErr := errors.New("fake")
+
+		repositoryMock.EXPECT().CountByOwner(owner, "owner").Return(0, fakeErr)
+
+		_, err := service.Check(owner)
+		assert.ErrorIs(t, err, fakeErr)
+	})
+}
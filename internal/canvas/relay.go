@@ -0,0 +1,115 @@
+package canvas
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	// relayInterval is how often the relay looks for events recorded by
+	// other processes, or released by a relay that failed.
+	relayInterval = 5 * time.Second
+	relayBatch    = 50
+	// relayClaimDuration is also how long a failed event waits to be sent
+	// again.
+	relayClaimDuration = 30 * time.Second
+)
+
+// Relay publishes the events of the outbox to its sinks, in the order they
+// were recorded. An event is published at least once to every sink, and only
+// sent again to the sinks that did not get it.
+type Relay struct {
+	outbox Outbox
+	sinks  map[string]Sink
+	names  []string
+}
+
+// NewRelay sends the events to the sinks by the order of their names, which
+// identify them in the outbox and must not change between runs.
+func NewRelay(outbox Outbox, sinks map[string]Sink) *Relay {
+	names := make([]string, 0, len(sinks))
+	for name := range sinks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return &Relay{
+		outbox: outbox,
+		sinks:  sinks,
+		names:  names,
+	}
+}
+
+// Run publishes the events as they are recorded, until the context is done.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(relayInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.outbox.Recorded():
+		}
+
+		// A full batch means more may be waiting.
+		for {
+			published, err := r.Deliver(ctx)
+			if err != nil {
+				log.Errorf("failed to relay events: %v", err)
+			}
+			if err != nil || published < relayBatch {
+				break
+			}
+		}
+	}
+}
+
+// Deliver publishes a batch of the pending events and returns how many it
+// published. It stops at the first failure, so that the events of a canvas
+// are not sent out of order.
+func (r *Relay) Deliver(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	events, err := r.outbox.Claim(ctx, now, now.Add(relayClaimDuration), relayBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, event := range events {
+		if err := r.publish(ctx, event); err != nil {
+			return i, fmt.Errorf("failed to publish event '%s': %w", event.ID, err)
+		}
+	}
+	return len(events), nil
+}
+
+func (r *Relay) publish(ctx context.Context, event Event) error {
+	delivered, err := r.outbox.GetDelivered(ctx, event.ID)
+	if err != nil {
+		return err
+	}
+
+	done := make(map[string]bool, len(delivered))
+	for _, name := range delivered {
+		done[name] = true
+	}
+
+	for _, name := range r.names {
+		if done[name] {
+			continue
+		}
+
+		if err := r.sinks[name].Send(ctx, event); err != nil {
+			return fmt.Errorf("sink %s: %w", name, err)
+		}
+
+		if err := r.outbox.SaveDelivered(ctx, event.ID, name); err != nil {
+			return err
+		}
+	}
+	return r.outbox.MarkPublished(ctx, event.ID)
+}
@@ -0,0 +1,98 @@
+package canvas_test
+
+import (
+	"context"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"sketch/tests/faker"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingSink struct{}
+
+func (failingSink) Send(context.Context, canvas.Event) error {
+	return faker.NewError()
+}
+
+func TestRelay_Deliver(t *testing.T) {
+	created := canvas.NewEvent(canvas.EventCreated, faker.NewCanvas(t))
+	updated := canvas.NewEvent(canvas.EventUpdated, faker.NewCanvas(t))
+
+	t.Run("should send the events to every sink, in order, and mark them as published", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		outboxMock := mock_canvas.NewMockOutbox(ctrl)
+		first, second := canvas.NewMemorySink(), canvas.NewMemorySink()
+		relay := canvas.NewRelay(outboxMock, map[string]canvas.Sink{"first": first, "second": second})
+		ctx := context.Background()
+
+		outboxMock.EXPECT().Claim(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]canvas.Event{created, updated}, nil)
+		for _, event := range []canvas.Event{created, updated} {
+			outboxMock.EXPECT().GetDelivered(ctx, event.ID).Return(nil, nil)
+			outboxMock.EXPECT().SaveDelivered(ctx, event.ID, "first").Return(nil)
+			outboxMock.EXPECT().SaveDelivered(ctx, event.ID, "second").Return(nil)
+			outboxMock.EXPECT().MarkPublished(ctx, event.ID).Return(nil)
+		}
+
+		published, err := relay.Deliver(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, published)
+		assert.Equal(t, []canvas.Event{created, updated}, first.Events())
+		assert.Equal(t, []canvas.Event{created, updated}, second.Events())
+	})
+
+	t.Run("when a sink already got the event, should not send it again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		outboxMock := mock_canvas.NewMockOutbox(ctrl)
+		first, second := canvas.NewMemorySink(), canvas.NewMemorySink()
+		relay := canvas.NewRelay(outboxMock, map[string]canvas.Sink{"first": first, "second": second})
+		ctx := context.Background()
+
+		outboxMock.EXPECT().Claim(ctx, gomock.Any(), gomock.Any(), gomock.Any()).Return([]canvas.Event{created}, nil)
+		outboxMock.EXPECT().GetDelivered(ctx, created.ID).Return([]string{"first"}, nil)
+		outboxMock.EXPECT().SaveDelivered(ctx, created.ID, "second").Return(nil)
+		outboxMock.EXPECT().MarkPublished(ctx, created.ID).Return(nil)
+
+		_, err := relay.Deliver(ctx)
+
+		assert.NoError(t, err)
+		assert.Empty(t, first.Events())
+		assert.Equal(t, []canvas.Event{created}, second.Events())
+	})
+
+	t.Run("when a sink fails, should stop without publishing the event or the next ones", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		outboxMock := mock_canvas.NewMockOutbox(ctrl)
+		memory := canvas.NewMemorySink()
+		relay := canvas.NewRelay(outboxMock, map[string]canvas.Sink{"a": memory, "b": failingSink{}})
+		ctx := context.Background()
+
+		outboxMock.EXPECT().Claim(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]canvas.Event{created, updated}, nil)
+		outboxMock.EXPECT().GetDelivered(ctx, created.ID).Return(nil, nil)
+		outboxMock.EXPECT().SaveDelivered(ctx, created.ID, "a").Return(nil)
+		outboxMock.EXPECT().MarkPublished(gomock.Any(), gomock.Any()).Times(0)
+
+		published, err := relay.Deliver(ctx)
+
+		assert.ErrorIs(t, err, faker.NewError())
+		assert.Equal(t, 0, published)
+		assert.Equal(t, []canvas.Event{created}, memory.Events())
+	})
+}
+
+func TestMemorySink_Send(t *testing.T) {
+	t.Run("when an event is sent again, should keep it once", func(t *testing.T) {
+		sink := canvas.NewMemorySink()
+		event := canvas.NewEvent(canvas.EventCreated, faker.NewCanvas(t))
+
+		assert.NoError(t, sink.Send(context.Background(), event))
+		assert.NoError(t, sink.Send(context.Background(), event))
+
+		assert.Equal(t, []canvas.Event{event}, sink.Events())
+	})
+}
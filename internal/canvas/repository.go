@@ -6,18 +6,33 @@ import (
 	goerrors "errors"
 	"fmt"
 	"sketch/internal/errors"
+	"sketch/internal/workspace"
 
 	"github.com/jmoiron/sqlx"
 )
 
 var (
 	ErrNotFound = errors.Error("not found")
+	ErrConflict = errors.Error("the canvas was changed meanwhile, try again")
 )
 
 type (
+	// Repository only finds and changes the canvases of the workspace of the
+	// context, see workspace.ID.
 	Repository interface {
 		GetByID(ctx context.Context, id string) (Canvas, error)
+		GetViewport(ctx context.Context, id string, viewport Viewport) (Canvas, error)
 		Save(ctx context.Context, canvas Canvas) error
+		// Update fails with ErrConflict when the canvas changed since it was
+		// read at its version, so that edits made together do not overwrite
+		// each other.
+		Update(ctx context.Context, canvas Canvas) error
+		Delete(ctx context.Context, id string) error
+		// GetOwner returns the owner of the canvas, empty when it has none.
+		GetOwner(ctx context.Context, id string) (string, error)
+		// CountByOwner returns how many canvases the owner has, in every
+		// workspace.
+		CountByOwner(ctx context.Context, ownerID string) (int, error)
 	}
 
 	repository struct {
@@ -32,9 +47,9 @@ func NewRepository(db *sqlx.DB) Repository {
 }
 
 func (r *repository) GetByID(ctx context.Context, id string) (Canvas, error) {
-	const query = "select id, drawing, created_at from drawings where id = $1"
+	const query = "select id, owner_id, workspace_id, drawing, version, created_at from drawings where id = $1 and workspace_id = $2"
 	var canvas Canvas
-	if err := r.db.GetContext(ctx, &canvas, query, id); err != nil {
+	if err := r.db.GetContext(ctx, &canvas, query, id, workspace.ID(ctx)); err != nil {
 		if goerrors.Is(err, sql.ErrNoRows) {
 			return canvas, ErrNotFound
 		}
@@ -44,10 +59,93 @@ func (r *repository) GetByID(ctx context.Context, id string) (Canvas, error) {
 	return canvas, nil
 }
 
+func (r *repository) GetViewport(ctx context.Context, id string, viewport Viewport) (Canvas, error) {
+	canvas, err := r.GetByID(ctx, id)
+	if err != nil {
+		return canvas, err
+	}
+
+	canvas.Drawing = ParseDraw(canvas.Drawing).Crop(viewport).String()
+	return canvas, nil
+}
+
 func (r *repository) Save(ctx context.Context, canvas Canvas) error {
-	const query = "insert into drawings (id, drawing, created_at) values (:id, :drawing, :created_at)"
-	if _, err := r.db.NamedExecContext(ctx, query, canvas); err != nil {
+	const query = "insert into drawings (id, owner_id, workspace_id, drawing, created_at) " +
+		"values (:id, :owner_id, :workspace_id, :drawing, :created_at)"
+	if _, err := sqlx.NamedExecContext(ctx, executor(ctx, r.db), query, canvas); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) Update(ctx context.Context, canvas Canvas) error {
+	const query = "update drawings set drawing = $2, version = version + 1 " +
+		"where id = $1 and workspace_id = $3 and ($4 = 0 or version = $4)"
+	result, err := executor(ctx, r.db).ExecContext(ctx, query, canvas.ID, canvas.Drawing, workspace.ID(ctx), canvas.Version)
+	if err != nil {
 		return fmt.Errorf("database err: %w", err)
 	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return missedUpdate(ctx, r, canvas)
+	}
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	const query = "delete from drawings where id = $1 and workspace_id = $2"
+	result, err := executor(ctx, r.db).ExecContext(ctx, query, id, workspace.ID(ctx))
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrNotFound
+	}
 	return nil
 }
+
+func (r *repository) GetOwner(ctx context.Context, id string) (string, error) {
+	const query = "select owner_id from drawings where id = $1 and workspace_id = $2"
+	return getOwner(ctx, executor(ctx, r.db), query, id)
+}
+
+func (r *repository) CountByOwner(ctx context.Context, ownerID string) (int, error) {
+	const query = "select count(*) from drawings where owner_id = $1"
+	return countByOwner(ctx, executor(ctx, r.db), query, ownerID)
+}
+
+// missedUpdate tells why updating the canvas changed nothing: it is gone, or
+// it changed since it was read at its version.
+func missedUpdate(ctx context.Context, repository Repository, canvas Canvas) error {
+	if canvas.Version == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := repository.GetOwner(ctx, canvas.ID); err != nil {
+		return err
+	}
+	return ErrConflict
+}
+
+// getOwner runs a query of the owner of the canvas, taking its id and its
+// workspace.
+func getOwner(ctx context.Context, q sqlx.QueryerContext, query string, id string) (string, error) {
+	var ownerID string
+	if err := sqlx.GetContext(ctx, q, &ownerID, query, id, workspace.ID(ctx)); err != nil {
+		if goerrors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+
+		return "", fmt.Errorf("database err: %w", err)
+	}
+	return ownerID, nil
+}
+
+func countByOwner(ctx context.Context, q sqlx.QueryerContext, query string, ownerID string) (int, error) {
+	var count int
+	if err := sqlx.GetContext(ctx, q, &count, query, ownerID); err != nil {
+		return 0, fmt.Errorf("database err: %w", err)
+	}
+	return count, nil
+}
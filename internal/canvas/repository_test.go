@@ -7,12 +7,13 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"sketch/internal/canvas"
+	"sketch/internal/workspace"
 	"sketch/tests/faker"
 	"testing"
 )
 
 func TestRepository_GetByID(t *testing.T) {
-	const query = "select id, drawing, created_at from drawings where id = $1"
+	const query = "select id, owner_id, workspace_id, drawing, version, created_at from drawings where id = $1 and workspace_id = $2"
 	setup := func() (canvas.Repository, sqlmock.Sqlmock) {
 		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		db := sqlx.NewDb(mockDB, "sqlmock")
@@ -23,11 +24,11 @@ func TestRepository_GetByID(t *testing.T) {
 		repository, mock := setup()
 		fakeDraw := canvas.NewCanvas(":)")
 		rows := sqlmock.
-			NewRows([]string{"id", "drawing", "created_at"}).
-			AddRow(fakeDraw.ID, fakeDraw.Drawing, fakeDraw.CreatedAt)
+			NewRows([]string{"id", "owner_id", "drawing", "created_at"}).
+			AddRow(fakeDraw.ID, fakeDraw.OwnerID, fakeDraw.Drawing, fakeDraw.CreatedAt)
 
 		mock.ExpectQuery(query).
-			WithArgs(fakeDraw.ID).
+			WithArgs(fakeDraw.ID, fakeDraw.WorkspaceID).
 			WillReturnRows(rows)
 
 		result, err := repository.GetByID(context.Background(), fakeDraw.ID)
@@ -42,17 +43,28 @@ func TestRepository_GetByID(t *testing.T) {
 	t.Run("when there are no results, should return not found error", func(t *testing.T) {
 		repository, mock := setup()
 
-		mock.ExpectQuery(query).WithArgs("123").WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(query).WithArgs("123", "").WillReturnError(sql.ErrNoRows)
 		result, err := repository.GetByID(context.Background(), "123")
 
 		assert.Empty(t, result)
 		assert.ErrorIs(t, err, canvas.ErrNotFound)
 	})
 
+	t.Run("when the request is in a workspace, should only look in it", func(t *testing.T) {
+		repository, mock := setup()
+		ctx := workspace.WithScope(context.Background(), workspace.Scope{WorkspaceID: "ws", Role: workspace.RoleViewer})
+
+		mock.ExpectQuery(query).WithArgs("123", "ws").WillReturnError(sql.ErrNoRows)
+		_, err := repository.GetByID(ctx, "123")
+
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
 	t.Run("when there is an error querying the result, should return it", func(t *testing.T) {
 		repository, mock := setup()
 
-		mock.ExpectQuery(query).WithArgs("123").WillReturnError(faker.NewError())
+		mock.ExpectQuery(query).WithArgs("123", "").WillReturnError(faker.NewError())
 		result, err := repository.GetByID(context.Background(), "123")
 
 		assert.Empty(t, result)
@@ -61,7 +73,7 @@ func TestRepository_GetByID(t *testing.T) {
 }
 
 func TestRepository_Save(t *testing.T) {
-	const query = "insert into drawings (id, drawing, created_at) values (?, ?, ?)"
+	const query = "insert into drawings (id, owner_id, workspace_id, drawing, created_at) values (?, ?, ?, ?, ?)"
 	setup := func() (canvas.Repository, sqlmock.Sqlmock) {
 		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
 		db := sqlx.NewDb(mockDB, "sqlmock")
@@ -72,7 +84,7 @@ func TestRepository_Save(t *testing.T) {
 
 		fakeCanvas := faker.NewCanvas(t)
 		mock.ExpectExec(query).
-			WithArgs(fakeCanvas.ID, fakeCanvas.Drawing, fakeCanvas.CreatedAt).
+			WithArgs(fakeCanvas.ID, fakeCanvas.OwnerID, fakeCanvas.WorkspaceID, fakeCanvas.Drawing, fakeCanvas.CreatedAt).
 			WillReturnResult(sqlmock.NewResult(1, 1))
 
 		err := repository.Save(context.Background(), fakeCanvas)
@@ -88,7 +100,7 @@ func TestRepository_Save(t *testing.T) {
 
 		fakeCanvas := faker.NewCanvas(t)
 		mock.ExpectExec(query).
-			WithArgs(fakeCanvas.ID, fakeCanvas.Drawing, fakeCanvas.CreatedAt).
+			WithArgs(fakeCanvas.ID, fakeCanvas.OwnerID, fakeCanvas.WorkspaceID, fakeCanvas.Drawing, fakeCanvas.CreatedAt).
 			WillReturnError(faker.NewError())
 
 		err := repository.Save(context.Background(), fakeCanvas)
@@ -99,3 +111,95 @@ func TestRepository_Save(t *testing.T) {
 		}
 	})
 }
+
+func TestRepository_Update(t *testing.T) {
+	const (
+		query = "update drawings set drawing = $2, version = version + 1 " +
+			"where id = $1 and workspace_id = $3 and ($4 = 0 or version = $4)"
+		selectOwner = "select owner_id from drawings where id = $1 and workspace_id = $2"
+	)
+	setup := func() (canvas.Repository, sqlmock.Sqlmock) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		return canvas.NewRepository(sqlx.NewDb(mockDB, "sqlmock")), mock
+	}
+
+	t.Run("when the canvas is at the version read, should update it", func(t *testing.T) {
+		repository, mock := setup()
+
+		mock.ExpectExec(query).WithArgs("123", "ab", "", 3).WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repository.Update(context.Background(), canvas.Canvas{ID: "123", Drawing: "ab", Version: 3})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when another change got in since the version read, should return a conflict", func(t *testing.T) {
+		repository, mock := setup()
+
+		mock.ExpectExec(query).WithArgs("123", "ab", "", 3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(selectOwner).WithArgs("123", "").WillReturnRows(sqlmock.NewRows([]string{"owner_id"}).AddRow(""))
+
+		err := repository.Update(context.Background(), canvas.Canvas{ID: "123", Drawing: "ab", Version: 3})
+
+		assert.ErrorIs(t, err, canvas.ErrConflict)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when the canvas was deleted since it was read, should return not found", func(t *testing.T) {
+		repository, mock := setup()
+
+		mock.ExpectExec(query).WithArgs("123", "ab", "", 3).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(selectOwner).WithArgs("123", "").WillReturnError(sql.ErrNoRows)
+
+		err := repository.Update(context.Background(), canvas.Canvas{ID: "123", Drawing: "ab", Version: 3})
+
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_Delete(t *testing.T) {
+	const query = "delete from drawings where id = $1 and workspace_id = $2"
+	setup := func() (canvas.Repository, sqlmock.Sqlmock) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		db := sqlx.NewDb(mockDB, "sqlmock")
+		return canvas.NewRepository(db), mock
+	}
+
+	t.Run("when the drawing exists, should delete it", func(t *testing.T) {
+		repository, mock := setup()
+
+		mock.ExpectExec(query).WithArgs("123", "").WillReturnResult(sqlmock.NewResult(0, 1))
+		err := repository.Delete(context.Background(), "123")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when no drawing is deleted, should return not found error", func(t *testing.T) {
+		repository, mock := setup()
+
+		mock.ExpectExec(query).WithArgs("123", "").WillReturnResult(sqlmock.NewResult(0, 0))
+		err := repository.Delete(context.Background(), "123")
+
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+	})
+}
+
+func TestRepository_CountByOwner(t *testing.T) {
+	const query = "select count(*) from drawings where owner_id = $1"
+	mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	repository := canvas.NewRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+	t.Run("when the owner has drawings, should count them in every workspace", func(t *testing.T) {
+		ctx := workspace.WithScope(context.Background(), workspace.Scope{WorkspaceID: "ws", Role: workspace.RoleViewer})
+
+		mock.ExpectQuery(query).WithArgs("owner").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+		count, err := repository.CountByOwner(ctx, "owner")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
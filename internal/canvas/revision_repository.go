@@ -0,0 +1,104 @@
+package canvas
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"sketch/internal/errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrRevisionsNotFound = errors.Error("canvas has no revisions")
+)
+
+type (
+	// RevisionRepository keeps a copy of the drawing every time a canvas is
+	// saved or updated through it, on top of any other Repository.
+	RevisionRepository interface {
+		Repository
+		GetRevisions(ctx context.Context, id string) ([]Revision, error)
+	}
+
+	revisionRepository struct {
+		Repository
+		db *sqlx.DB
+	}
+
+	Revision struct {
+		Drawing   string    `json:"drawing" db:"drawing"`
+		CreatedAt time.Time `json:"created_at" db:"created_at"`
+	}
+)
+
+func NewRevisionRepository(repository Repository, db *sqlx.DB) RevisionRepository {
+	return &revisionRepository{
+		Repository: repository,
+		db:         db,
+	}
+}
+
+func (r *revisionRepository) Save(ctx context.Context, canvas Canvas) error {
+	if err := r.Repository.Save(ctx, canvas); err != nil {
+		return err
+	}
+	return r.addRevision(ctx, canvas.ID, Revision{Drawing: canvas.Drawing, CreatedAt: canvas.CreatedAt})
+}
+
+func (r *revisionRepository) Update(ctx context.Context, canvas Canvas) error {
+	if err := r.Repository.Update(ctx, canvas); err != nil {
+		return err
+	}
+	return r.addRevision(ctx, canvas.ID, Revision{Drawing: canvas.Drawing, CreatedAt: time.Now().UTC()})
+}
+
+// Delete removes the canvas, its revisions and the frames of its animation,
+// if it is one. The canvas may be in any of the storages, so the frames have
+// no foreign key to cascade from.
+func (r *revisionRepository) Delete(ctx context.Context, id string) error {
+	if err := r.Repository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	const deleteRevisions = "delete from drawing_revisions where drawing_id = $1"
+	if _, err := executor(ctx, r.db).ExecContext(ctx, deleteRevisions, id); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	const deleteFrames = "delete from drawing_frames where drawing_id = $1"
+	if _, err := executor(ctx, r.db).ExecContext(ctx, deleteFrames, id); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+// GetRevisions returns the revisions of the canvas from the oldest to the
+// newest one, if the canvas is in the workspace of the context.
+func (r *revisionRepository) GetRevisions(ctx context.Context, id string) ([]Revision, error) {
+	if _, err := r.Repository.GetOwner(ctx, id); err != nil {
+		if goerrors.Is(err, ErrNotFound) {
+			return nil, ErrRevisionsNotFound
+		}
+		return nil, err
+	}
+
+	const query = "select drawing, created_at from drawing_revisions where drawing_id = $1 order by id"
+	revisions := make([]Revision, 0)
+	if err := r.db.SelectContext(ctx, &revisions, query, id); err != nil {
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+	if len(revisions) == 0 {
+		return nil, ErrRevisionsNotFound
+	}
+	return revisions, nil
+}
+
+func (r *revisionRepository) addRevision(ctx context.Context, id string, revision Revision) error {
+	const query = "insert into drawing_revisions (drawing_id, drawing, created_at) values ($1, $2, $3)"
+	if _, err := executor(ctx, r.db).ExecContext(ctx, query, id, revision.Drawing, revision.CreatedAt); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
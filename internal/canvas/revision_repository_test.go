@@ -0,0 +1,138 @@
+package canvas_test
+
+import (
+	"context"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"sketch/tests/faker"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevisionRepository_Save(t *testing.T) {
+	const insertRevision = "insert into drawing_revisions (drawing_id, drawing, created_at) values ($1, $2, $3)"
+	setup := func(t *testing.T) (canvas.RevisionRepository, *mock_canvas.MockRepository, sqlmock.Sqlmock) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		repositoryMock := mock_canvas.NewMockRepository(gomock.NewController(t))
+		return canvas.NewRevisionRepository(repositoryMock, sqlx.NewDb(mockDB, "sqlmock")), repositoryMock, mock
+	}
+
+	t.Run("when the canvas is saved, should keep its drawing as the first revision", func(t *testing.T) {
+		repository, repositoryMock, mock := setup(t)
+		fakeCanvas := faker.NewCanvas(t)
+
+		repositoryMock.EXPECT().Save(gomock.Any(), fakeCanvas).Return(nil)
+		mock.ExpectExec(insertRevision).
+			WithArgs(fakeCanvas.ID, fakeCanvas.Drawing, fakeCanvas.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+
+		err := repository.Save(context.Background(), fakeCanvas)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when updating the canvas fails, should not add a revision", func(t *testing.T) {
+		repository, repositoryMock, mock := setup(t)
+		fakeCanvas := faker.NewCanvas(t)
+
+		repositoryMock.EXPECT().Update(gomock.Any(), fakeCanvas).Return(canvas.ErrNotFound)
+
+		err := repository.Update(context.Background(), fakeCanvas)
+
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRevisionRepository_Delete(t *testing.T) {
+	mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	repositoryMock := mock_canvas.NewMockRepository(gomock.NewController(t))
+	repository := canvas.NewRevisionRepository(repositoryMock, sqlx.NewDb(mockDB, "sqlmock"))
+
+	t.Run("when the canvas is deleted, should delete its revisions and frames", func(t *testing.T) {
+		repositoryMock.EXPECT().Delete(gomock.Any(), "id").Return(nil)
+		mock.ExpectExec("delete from drawing_revisions where drawing_id = $1").
+			WithArgs("id").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("delete from drawing_frames where drawing_id = $1").
+			WithArgs("id").
+			WillReturnResult(sqlmock.NewResult(0, 3))
+
+		err := repository.Delete(context.Background(), "id")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when the canvas does not exist, should not delete anything else", func(t *testing.T) {
+		repositoryMock.EXPECT().Delete(gomock.Any(), "id").Return(canvas.ErrNotFound)
+
+		err := repository.Delete(context.Background(), "id")
+
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRevisionRepository_GetRevisions(t *testing.T) {
+	const selectRevisions = "select drawing, created_at from drawing_revisions where drawing_id = $1 order by id"
+	setup := func(t *testing.T) (canvas.RevisionRepository, *mock_canvas.MockRepository, sqlmock.Sqlmock) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		repositoryMock := mock_canvas.NewMockRepository(gomock.NewController(t))
+		return canvas.NewRevisionRepository(repositoryMock, sqlx.NewDb(mockDB, "sqlmock")), repositoryMock, mock
+	}
+
+	t.Run("when there are revisions, should return them from the oldest", func(t *testing.T) {
+		repository, repositoryMock, mock := setup(t)
+		createdAt := time.Date(2022, 8, 1, 0, 0, 0, 0, time.UTC)
+
+		repositoryMock.EXPECT().GetOwner(gomock.Any(), "id").Return("", nil)
+
+		mock.ExpectQuery(selectRevisions).
+			WithArgs("id").
+			WillReturnRows(sqlmock.NewRows([]string{"drawing", "created_at"}).
+				AddRow("a", createdAt).
+				AddRow("ab", createdAt.Add(time.Minute)))
+
+		revisions, err := repository.GetRevisions(context.Background(), "id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []canvas.Revision{
+			{Drawing: "a", CreatedAt: createdAt},
+			{Drawing: "ab", CreatedAt: createdAt.Add(time.Minute)},
+		}, revisions)
+	})
+
+	t.Run("when there are no revisions, should return not found", func(t *testing.T) {
+		repository, repositoryMock, mock := setup(t)
+
+		repositoryMock.EXPECT().GetOwner(gomock.Any(), "id").Return("", nil)
+
+		mock.ExpectQuery(selectRevisions).
+			WithArgs("id").
+			WillReturnRows(sqlmock.NewRows([]string{"drawing", "created_at"}))
+
+		revisions, err := repository.GetRevisions(context.Background(), "id")
+
+		assert.ErrorIs(t, err, canvas.ErrRevisionsNotFound)
+		assert.Nil(t, revisions)
+	})
+
+	t.Run("when the canvas is not in the workspace, should return not found", func(t *testing.T) {
+		repository, repositoryMock, mock := setup(t)
+
+		repositoryMock.EXPECT().GetOwner(gomock.Any(), "id").Return("", canvas.ErrNotFound)
+
+		revisions, err := repository.GetRevisions(context.Background(), "id")
+
+		assert.ErrorIs(t, err, canvas.ErrRevisionsNotFound)
+		assert.Nil(t, revisions)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
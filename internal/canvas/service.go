@@ -2,24 +2,38 @@ package canvas
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 )
 
+// editAttempts is how many times an edit is drawn again on a canvas another
+// change got to first, before giving up with ErrConflict.
+const editAttempts = 3
+
 type (
 	service struct {
 		repository Repository
 		drawer     Drawer
+		symbols    SymbolRepository
+		events     Publisher
 	}
 	Service interface {
 		GetByID(ctx context.Context, id string) (*Canvas, error)
 		Save(ctx context.Context, requests DrawRequests) (*DrawResponse, error)
+		GetViewport(ctx context.Context, id string, viewport Viewport) (*Canvas, error)
+		Crop(ctx context.Context, id string, viewport Viewport) (*DrawResponse, error)
+		Edit(ctx context.Context, id string, requests DrawRequests) (*DrawResponse, error)
+		Import(ctx context.Context, drawing string) (*DrawResponse, error)
+		Delete(ctx context.Context, id string) error
 	}
 )
 
-func NewService(repository Repository, drawer Drawer) Service {
+func NewService(repository Repository, drawer Drawer, symbols SymbolRepository, events Publisher) Service {
 	return &service{
 		repository: repository,
 		drawer:     drawer,
+		symbols:    symbols,
+		events:     events,
 	}
 }
 
@@ -32,18 +46,147 @@ func (s service) GetByID(ctx context.Context, id string) (*Canvas, error) {
 }
 
 func (s service) Save(ctx context.Context, request DrawRequests) (*DrawResponse, error) {
-	draw, err := s.drawer.Draw(request)
+	draw, linked, err := render(ctx, s.drawer, s.symbols, request)
 	if err != nil {
-		return nil, fmt.Errorf("fail to draw: %w", err)
+		return nil, err
 	}
 
-	canvas := NewCanvas(draw)
+	canvas := newOwnedCanvas(ctx, draw)
 	if err := s.repository.Save(ctx, canvas); err != nil {
 		return nil, fmt.Errorf("error saving canvas: %w", err)
 	}
 
+	if len(linked) > 0 {
+		if err := s.symbols.Link(ctx, canvas.ID, linked, request); err != nil {
+			return nil, fmt.Errorf("error linking symbols: %w", err)
+		}
+	}
+	s.events.Publish(ctx, NewEvent(EventCreated, canvas))
+
 	return &DrawResponse{
 		ID:      canvas.ID,
 		Drawing: draw,
 	}, nil
 }
+
+func (s service) GetViewport(ctx context.Context, id string, viewport Viewport) (*Canvas, error) {
+	canvas, err := s.repository.GetViewport(ctx, id, viewport)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s': %w", id, err)
+	}
+	return &canvas, nil
+}
+
+func (s service) Crop(ctx context.Context, id string, viewport Viewport) (*DrawResponse, error) {
+	source, err := s.GetViewport(ctx, id, viewport)
+	if err != nil {
+		return nil, err
+	}
+
+	canvas := newOwnedCanvas(ctx, source.Drawing)
+	if err := s.repository.Save(ctx, canvas); err != nil {
+		return nil, fmt.Errorf("error saving canvas: %w", err)
+	}
+	s.events.Publish(ctx, NewEvent(EventCreated, canvas))
+
+	return &DrawResponse{
+		ID:      canvas.ID,
+		Drawing: canvas.Drawing,
+	}, nil
+}
+
+// Edit draws the requests on top of a stored canvas. The requests are added to
+// the operations of a linked canvas, so rendering it again when its symbols
+// change keeps them.
+// Edit draws the requests on the canvas as it is when saving them: when another
+// change got in since it was read, they are drawn again on the new one.
+func (s service) Edit(ctx context.Context, id string, requests DrawRequests) (*DrawResponse, error) {
+	for attempt := 1; ; attempt++ {
+		response, err := s.edit(ctx, id, requests)
+		if goerrors.Is(err, ErrConflict) && attempt < editAttempts {
+			continue
+		}
+		return response, err
+	}
+}
+
+func (s service) edit(ctx context.Context, id string, requests DrawRequests) (*DrawResponse, error) {
+	canvas, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s': %w", id, err)
+	}
+
+	current := DrawRequest{Type: OperationText, Text: canvas.Drawing}
+	draw, linked, err := render(ctx, s.drawer, s.symbols, append(DrawRequests{current}, requests...))
+	if err != nil {
+		return nil, err
+	}
+
+	operations, err := s.symbols.GetOperations(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the operations of '%s': %w", id, err)
+	}
+
+	canvas.Drawing = draw
+	if err := s.repository.Update(ctx, canvas); err != nil {
+		return nil, fmt.Errorf("error updating canvas: %w", err)
+	}
+
+	// A canvas linked for the first time starts from what it had drawn.
+	if len(operations) == 0 && len(linked) > 0 {
+		operations = DrawRequests{current}
+	}
+	if len(operations) > 0 {
+		if err := s.symbols.Link(ctx, id, linked, append(operations, requests...)); err != nil {
+			return nil, fmt.Errorf("error linking symbols: %w", err)
+		}
+	}
+	s.events.Publish(ctx, NewEvent(EventUpdated, canvas))
+
+	return &DrawResponse{
+		ID:      canvas.ID,
+		Drawing: canvas.Drawing,
+	}, nil
+}
+
+// Import stores an already drawn text as a new canvas.
+func (s service) Import(ctx context.Context, drawing string) (*DrawResponse, error) {
+	canvas := newOwnedCanvas(ctx, drawing)
+	if err := s.repository.Save(ctx, canvas); err != nil {
+		return nil, fmt.Errorf("error saving canvas: %w", err)
+	}
+	s.events.Publish(ctx, NewEvent(EventCreated, canvas))
+
+	return &DrawResponse{
+		ID:      canvas.ID,
+		Drawing: canvas.Drawing,
+	}, nil
+}
+
+// Delete removes a stored canvas and its links to symbols.
+func (s service) Delete(ctx context.Context, id string) error {
+	if err := s.repository.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete '%s': %w", id, err)
+	}
+
+	if err := s.symbols.Unlink(ctx, id); err != nil {
+		return fmt.Errorf("error unlinking symbols: %w", err)
+	}
+	s.events.Publish(ctx, NewEvent(EventDeleted, Canvas{ID: id}))
+	return nil
+}
+
+// render expands the symbols used by the requests and draws them, returning
+// the names of the symbols the drawing must stay linked to.
+func render(ctx context.Context, drawer Drawer, symbols SymbolRepository, requests DrawRequests) (string, []string, error) {
+	expanded, linked, err := expandSymbols(ctx, symbols, requests)
+	if err != nil {
+		return "", nil, err
+	}
+
+	draw, err := drawer.Draw(expanded)
+	if err != nil {
+		return "", nil, fmt.Errorf("fail to draw: %w", err)
+	}
+	return draw, linked, nil
+}
@@ -51,7 +51,7 @@ func TestService_GetByID(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			repositoryMock := mock_canvas.NewMockRepository(ctrl)
-			service := canvas.NewService(repositoryMock, nil)
+			service := canvas.NewService(repositoryMock, nil, nil, canvas.NewBroker())
 			ctx := context.Background()
 			const id = "fake-id"
 			repositoryMock.EXPECT().GetByID(ctx, id).
@@ -140,7 +140,7 @@ func TestService_Save(t *testing.T) {
 			repositoryMock := mock_canvas.NewMockRepository(ctrl)
 			drawerMock := mock_canvas.NewMockDrawer(ctrl)
 
-			service := canvas.NewService(repositoryMock, drawerMock)
+			service := canvas.NewService(repositoryMock, drawerMock, nil, canvas.NewBroker())
 			ctx := context.Background()
 
 			drawerMock.EXPECT().Draw(requests).
@@ -157,3 +157,226 @@ func TestService_Save(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Crop(t *testing.T) {
+	type repositoryMock struct {
+		getErr  error
+		saveErr error
+		saved   int
+	}
+
+	fakeCanvas := canvas.NewCanvas("XXX")
+	viewport := canvas.Viewport{X: 1, Y: 1, Width: 3, Height: 1}
+
+	testCases := []struct {
+		name       string
+		repository repositoryMock
+		assert     func(t *testing.T, response *canvas.DrawResponse, err error)
+	}{
+		{
+			name:       "when the canvas does not exist, should return an error",
+			repository: repositoryMock{getErr: canvas.ErrNotFound},
+			assert: func(t *testing.T, response *canvas.DrawResponse, err error) {
+				assert.ErrorIs(t, err, canvas.ErrNotFound)
+				assert.Nil(t, response)
+			},
+		},
+		{
+			name:       "when saving the cropped canvas fails, should return an error",
+			repository: repositoryMock{saveErr: faker.NewError(), saved: 1},
+			assert: func(t *testing.T, response *canvas.DrawResponse, err error) {
+				assert.ErrorIs(t, err, faker.NewError())
+				assert.Nil(t, response)
+			},
+		},
+		{
+			name:       "when there are no errors, should save a new canvas with the cropped region",
+			repository: repositoryMock{saved: 1},
+			assert: func(t *testing.T, response *canvas.DrawResponse, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "XXX", response.Drawing)
+				assert.NotEqual(t, fakeCanvas.ID, response.ID)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			repositoryMock := mock_canvas.NewMockRepository(ctrl)
+			service := canvas.NewService(repositoryMock, nil, nil, canvas.NewBroker())
+			ctx := context.Background()
+
+			repositoryMock.EXPECT().GetViewport(ctx, fakeCanvas.ID, viewport).
+				Times(1).
+				Return(fakeCanvas, tc.repository.getErr)
+
+			repositoryMock.EXPECT().Save(ctx, gomock.Any()).
+				Times(tc.repository.saved).
+				Return(tc.repository.saveErr)
+
+			result, err := service.Crop(ctx, fakeCanvas.ID, viewport)
+
+			tc.assert(t, result, err)
+		})
+	}
+}
+
+func TestService_Edit(t *testing.T) {
+	t.Run("when the canvas exists, should draw on top of it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		symbolsMock := mock_canvas.NewMockSymbolRepository(ctrl)
+		service := canvas.NewService(repositoryMock, canvas.NewDrawer(), symbolsMock, canvas.NewBroker())
+		ctx := context.Background()
+		imported := canvas.NewCanvas("+---+\n|   |\n+---+")
+		edited := imported
+		edited.Drawing = "+---+\n| * |\n+---+  @"
+
+		repositoryMock.EXPECT().GetByID(ctx, imported.ID).Return(imported, nil)
+		symbolsMock.EXPECT().GetOperations(ctx, imported.ID).Return(nil, nil)
+		repositoryMock.EXPECT().Update(ctx, edited).Return(nil)
+
+		response, err := service.Edit(ctx, imported.ID, canvas.DrawRequests{
+			{X: 2, Y: 1, Width: 1, Height: 1, Fill: "*"},
+			{X: 7, Y: 2, Width: 1, Height: 1, Fill: "@"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, edited.Drawing, response.Drawing)
+	})
+
+	t.Run("when the canvas does not exist, should return an error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		service := canvas.NewService(repositoryMock, canvas.NewDrawer(), nil, canvas.NewBroker())
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().GetByID(ctx, "123").Return(canvas.Canvas{}, canvas.ErrNotFound)
+
+		response, err := service.Edit(ctx, "123", faker.NewDrawRequests(t))
+
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+		assert.Nil(t, response)
+	})
+
+	t.Run("when another change got in first, should draw again on top of it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		symbolsMock := mock_canvas.NewMockSymbolRepository(ctrl)
+		service := canvas.NewService(repositoryMock, canvas.NewDrawer(), symbolsMock, canvas.NewBroker())
+		ctx := context.Background()
+		read := canvas.Canvas{ID: "123", Drawing: "a", Version: 1}
+		changed := canvas.Canvas{ID: "123", Drawing: "ab", Version: 2}
+		edited := changed
+		edited.Drawing = "abc"
+
+		gomock.InOrder(
+			repositoryMock.EXPECT().GetByID(ctx, "123").Return(read, nil),
+			repositoryMock.EXPECT().Update(ctx, canvas.Canvas{ID: "123", Drawing: "a c", Version: 1}).Return(canvas.ErrConflict),
+			repositoryMock.EXPECT().GetByID(ctx, "123").Return(changed, nil),
+			repositoryMock.EXPECT().Update(ctx, edited).Return(nil),
+		)
+		symbolsMock.EXPECT().GetOperations(ctx, "123").Return(nil, nil).Times(2)
+
+		response, err := service.Edit(ctx, "123", canvas.DrawRequests{{X: 2, Y: 0, Width: 1, Height: 1, Fill: "c"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", response.Drawing)
+	})
+
+	t.Run("when other changes keep getting in first, should give up with a conflict", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		symbolsMock := mock_canvas.NewMockSymbolRepository(ctrl)
+		service := canvas.NewService(repositoryMock, canvas.NewDrawer(), symbolsMock, canvas.NewBroker())
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().GetByID(ctx, "123").Return(canvas.Canvas{ID: "123", Drawing: "a", Version: 1}, nil).Times(3)
+		symbolsMock.EXPECT().GetOperations(ctx, "123").Return(nil, nil).Times(3)
+		repositoryMock.EXPECT().Update(ctx, gomock.Any()).Return(canvas.ErrConflict).Times(3)
+
+		response, err := service.Edit(ctx, "123", canvas.DrawRequests{{X: 2, Y: 0, Width: 1, Height: 1, Fill: "c"}})
+
+		assert.ErrorIs(t, err, canvas.ErrConflict)
+		assert.Nil(t, response)
+	})
+}
+
+func TestService_Publish(t *testing.T) {
+	t.Run("when a canvas is saved, should publish that it was created", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		publisherMock := mock_canvas.NewMockPublisher(ctrl)
+		service := canvas.NewService(repositoryMock, canvas.NewDrawer(), nil, publisherMock)
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+		publisherMock.EXPECT().Publish(ctx, gomock.Any()).
+			Do(func(_ context.Context, event canvas.Event) {
+				assert.Equal(t, canvas.EventCreated, event.Type)
+				assert.Equal(t, "hi", event.Drawing)
+			})
+
+		_, err := service.Import(ctx, "hi")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("when updating the canvas fails, should not publish", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		symbolsMock := mock_canvas.NewMockSymbolRepository(ctrl)
+		publisherMock := mock_canvas.NewMockPublisher(ctrl)
+		service := canvas.NewService(repositoryMock, canvas.NewDrawer(), symbolsMock, publisherMock)
+		ctx := context.Background()
+		fakeCanvas := faker.NewCanvas(t)
+
+		repositoryMock.EXPECT().GetByID(ctx, fakeCanvas.ID).Return(fakeCanvas, nil)
+		symbolsMock.EXPECT().GetOperations(ctx, fakeCanvas.ID).Return(nil, nil)
+		repositoryMock.EXPECT().Update(ctx, gomock.Any()).Return(faker.NewError())
+		publisherMock.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+		_, err := service.Edit(ctx, fakeCanvas.ID, faker.NewDrawRequests(t))
+
+		assert.ErrorIs(t, err, faker.NewError())
+	})
+}
+
+func TestService_Delete(t *testing.T) {
+	t.Run("when the canvas exists, should unlink it and publish that it was deleted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		symbolsMock := mock_canvas.NewMockSymbolRepository(ctrl)
+		publisherMock := mock_canvas.NewMockPublisher(ctrl)
+		service := canvas.NewService(repositoryMock, canvas.NewDrawer(), symbolsMock, publisherMock)
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().Delete(ctx, "123").Return(nil)
+		symbolsMock.EXPECT().Unlink(ctx, "123").Return(nil)
+		publisherMock.EXPECT().Publish(ctx, gomock.Any()).
+			Do(func(_ context.Context, event canvas.Event) {
+				assert.Equal(t, canvas.EventDeleted, event.Type)
+				assert.Equal(t, "123", event.CanvasID)
+			})
+
+		err := service.Delete(ctx, "123")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("when the canvas does not exist, should return not found without publishing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		publisherMock := mock_canvas.NewMockPublisher(ctrl)
+		service := canvas.NewService(repositoryMock, canvas.NewDrawer(), nil, publisherMock)
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().Delete(ctx, "123").Return(canvas.ErrNotFound)
+		publisherMock.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(0)
+
+		err := service.Delete(ctx, "123")
+
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+	})
+}
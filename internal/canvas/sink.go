@@ -0,0 +1,75 @@
+package canvas
+
+import (
+	"context"
+	"sync"
+
+	"github.com/labstack/gommon/log"
+)
+
+type (
+	// Sink is where the Relay hands the events of the outbox. An event may be
+	// sent again after a failure, so sinks should ignore the ids they already
+	// got.
+	Sink interface {
+		Send(ctx context.Context, event Event) error
+	}
+
+	logSink struct{}
+
+	publisherSink struct {
+		publisher Publisher
+	}
+
+	// MemorySink keeps the events it is sent, once each.
+	MemorySink struct {
+		mu     sync.Mutex
+		seen   map[string]struct{}
+		events []Event
+	}
+)
+
+func NewLogSink() Sink {
+	return logSink{}
+}
+
+func (logSink) Send(_ context.Context, event Event) error {
+	log.Infof("event %s: %s of canvas '%s'", event.ID, event.Type, event.CanvasID)
+	return nil
+}
+
+// NewPublisherSink sends the events to a Publisher, such as the Broker.
+func NewPublisherSink(publisher Publisher) Sink {
+	return publisherSink{
+		publisher: publisher,
+	}
+}
+
+func (s publisherSink) Send(ctx context.Context, event Event) error {
+	s.publisher.Publish(ctx, event)
+	return nil
+}
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{
+		seen: make(map[string]struct{}),
+	}
+}
+
+func (s *MemorySink) Send(_ context.Context, event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.seen[event.ID]; ok {
+		return nil
+	}
+	s.seen[event.ID] = struct{}{}
+	s.events = append(s.events, event)
+	return nil
+}
+
+// Events returns the events sent so far, in order.
+func (s *MemorySink) Events() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Event(nil), s.events...)
+}
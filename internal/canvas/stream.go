@@ -0,0 +1,138 @@
+package canvas
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	StreamCreated StreamEventKind = "created"
+	StreamDrawn   StreamEventKind = "drawn"
+	StreamDeleted StreamEventKind = "deleted"
+
+	// snapshotInterval is how many events apart the drawing of a stream is
+	// kept whole, so reads replay at most that many events.
+	snapshotInterval = 20
+)
+
+type (
+	StreamEventKind string
+
+	// StreamEvent is an operation of the stream of a canvas, numbered from 1.
+	StreamEvent struct {
+		Version   int             `json:"version" db:"version"`
+		Kind      StreamEventKind `json:"kind" db:"kind"`
+		Patch     Patch           `json:"patch" db:"patch"`
+		CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	}
+
+	// Patch turns a drawing into the next one: it keeps Rows rows, adding
+	// empty ones or dropping the last ones, then writes its spans.
+	Patch struct {
+		Rows  int    `json:"rows"`
+		Spans []Span `json:"spans,omitempty"`
+	}
+
+	// Span writes Text over row Y from column X. When Cut is set the row ends
+	// with the text.
+	Span struct {
+		X    int    `json:"x"`
+		Y    int    `json:"y"`
+		Text string `json:"text"`
+		Cut  bool   `json:"cut,omitempty"`
+	}
+)
+
+// Diff returns the patch that turns the drawing from into to.
+func Diff(from, to string) Patch {
+	before, after := strings.Split(from, "\n"), strings.Split(to, "\n")
+	patch := Patch{Rows: len(after)}
+	for y, line := range after {
+		previous := ""
+		if y < len(before) {
+			previous = before[y]
+		}
+		if span, changed := diffLine(previous, line); changed {
+			span.Y = y
+			patch.Spans = append(patch.Spans, span)
+		}
+	}
+	return patch
+}
+
+func diffLine(before, after string) (Span, bool) {
+	if before == after {
+		return Span{}, false
+	}
+
+	old, current := []rune(before), []rune(after)
+	prefix := 0
+	for prefix < len(old) && prefix < len(current) && old[prefix] == current[prefix] {
+		prefix++
+	}
+
+	if len(old) != len(current) {
+		return Span{X: prefix, Text: string(current[prefix:]), Cut: true}, true
+	}
+
+	end := len(current)
+	for end > prefix && old[end-1] == current[end-1] {
+		end--
+	}
+	return Span{X: prefix, Text: string(current[prefix:end])}, true
+}
+
+// Apply returns the drawing with the patch written over it.
+func (p Patch) Apply(drawing string) string {
+	lines := strings.Split(drawing, "\n")
+	rows := make([][]rune, p.Rows)
+	for y := range rows {
+		if y < len(lines) {
+			rows[y] = []rune(lines[y])
+		}
+	}
+
+	for _, span := range p.Spans {
+		if span.Y < 0 || span.Y >= len(rows) {
+			continue
+		}
+
+		row := rows[span.Y]
+		text := []rune(span.Text)
+		for len(row) < span.X {
+			row = append(row, []rune(paddingChar)...)
+		}
+		if span.Cut {
+			row = append(row[:span.X], text...)
+		} else {
+			for len(row) < span.X+len(text) {
+				row = append(row, []rune(paddingChar)...)
+			}
+			copy(row[span.X:], text)
+		}
+		rows[span.Y] = row
+	}
+
+	result := make([]string, len(rows))
+	for y, row := range rows {
+		result[y] = string(row)
+	}
+	return strings.Join(result, "\n")
+}
+
+func (p Patch) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+func (p *Patch) Scan(src any) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, p)
+	case string:
+		return json.Unmarshal([]byte(value), p)
+	}
+	return fmt.Errorf("cannot scan %T into patch", src)
+}
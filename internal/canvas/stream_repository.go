@@ -0,0 +1,289 @@
+package canvas
+
+import (
+	"context"
+	"database/sql"
+	goerrors "errors"
+	"fmt"
+	"time"
+
+	"sketch/internal/workspace"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	// StreamRepository keeps every canvas as the append-only stream of the
+	// patches that drew it, with a snapshot of the drawing every
+	// snapshotInterval events. Deleting a canvas appends to its stream too, so
+	// it can still be replayed.
+	StreamRepository interface {
+		Repository
+		// Replay rebuilds the canvas as it was at the given time.
+		Replay(ctx context.Context, id string, at time.Time) (Canvas, error)
+		GetEvents(ctx context.Context, id string) ([]StreamEvent, error)
+	}
+
+	streamRepository struct {
+		db *sqlx.DB
+	}
+
+	streamHead struct {
+		ID          string    `db:"id"`
+		OwnerID     string    `db:"owner_id"`
+		WorkspaceID string    `db:"workspace_id"`
+		Version     int       `db:"version"`
+		Deleted     bool      `db:"deleted"`
+		CreatedAt   time.Time `db:"created_at"`
+	}
+
+	snapshot struct {
+		Version int    `db:"version"`
+		Drawing string `db:"drawing"`
+	}
+)
+
+func NewStreamRepository(db *sqlx.DB) StreamRepository {
+	return &streamRepository{
+		db: db,
+	}
+}
+
+func (r *streamRepository) GetByID(ctx context.Context, id string) (Canvas, error) {
+	head, err := r.getHead(ctx, r.db, id)
+	if err != nil {
+		return Canvas{}, err
+	}
+
+	if head.Deleted {
+		return Canvas{}, ErrNotFound
+	}
+
+	drawing, err := r.build(ctx, r.db, id, head.Version)
+	if err != nil {
+		return Canvas{}, err
+	}
+	return Canvas{ID: id, OwnerID: head.OwnerID, WorkspaceID: head.WorkspaceID, Drawing: drawing, CreatedAt: head.CreatedAt,
+		Version: head.Version}, nil
+}
+
+func (r *streamRepository) GetViewport(ctx context.Context, id string, viewport Viewport) (Canvas, error) {
+	canvas, err := r.GetByID(ctx, id)
+	if err != nil {
+		return canvas, err
+	}
+
+	canvas.Drawing = ParseDraw(canvas.Drawing).Crop(viewport).String()
+	return canvas, nil
+}
+
+func (r *streamRepository) Save(ctx context.Context, canvas Canvas) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	defer tx.Rollback()
+
+	const query = "insert into canvas_streams (id, owner_id, workspace_id, version, deleted, created_at) values ($1, $2, $3, 1, false, $4)"
+	if _, err := tx.ExecContext(ctx, query, canvas.ID, canvas.OwnerID, canvas.WorkspaceID, canvas.CreatedAt); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	event := StreamEvent{Version: 1, Kind: StreamCreated, Patch: Diff("", canvas.Drawing), CreatedAt: canvas.CreatedAt}
+	if err := r.append(ctx, tx, canvas.ID, event, canvas.Drawing); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+// Update appends the patch from the current drawing to the new one. Updates
+// of a canvas wait for each other, as each is a patch of the previous one, and
+// the version of the canvas is the one of its stream.
+func (r *streamRepository) Update(ctx context.Context, canvas Canvas) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	defer tx.Rollback()
+
+	const query = "update canvas_streams set version = version + 1 " +
+		"where id = $1 and workspace_id = $2 and not deleted and ($3 = 0 or version = $3) returning version"
+	version, err := r.nextVersion(ctx, tx, query, canvas.ID, canvas.Version)
+	if goerrors.Is(err, ErrNotFound) {
+		return missedUpdate(ctx, r, canvas)
+	}
+	if err != nil {
+		return err
+	}
+
+	current, err := r.build(ctx, tx, canvas.ID, version-1)
+	if err != nil {
+		return err
+	}
+
+	event := StreamEvent{Version: version, Kind: StreamDrawn, Patch: Diff(current, canvas.Drawing), CreatedAt: time.Now().UTC()}
+	if err := r.append(ctx, tx, canvas.ID, event, canvas.Drawing); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+func (r *streamRepository) Delete(ctx context.Context, id string) error {
+	tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	defer tx.Rollback()
+
+	const query = "update canvas_streams set version = version + 1, deleted = true " +
+		"where id = $1 and workspace_id = $2 and not deleted returning version"
+	version, err := r.nextVersion(ctx, tx, query, id)
+	if err != nil {
+		return err
+	}
+
+	event := StreamEvent{Version: version, Kind: StreamDeleted, CreatedAt: time.Now().UTC()}
+	if err := r.append(ctx, tx, id, event, ""); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+func (r *streamRepository) GetOwner(ctx context.Context, id string) (string, error) {
+	const query = "select owner_id from canvas_streams where id = $1 and workspace_id = $2 and not deleted"
+	return getOwner(ctx, executor(ctx, r.db), query, id)
+}
+
+func (r *streamRepository) CountByOwner(ctx context.Context, ownerID string) (int, error) {
+	const query = "select count(*) from canvas_streams where owner_id = $1 and not deleted"
+	return countByOwner(ctx, executor(ctx, r.db), query, ownerID)
+}
+
+func (r *streamRepository) Replay(ctx context.Context, id string, at time.Time) (Canvas, error) {
+	head, err := r.getHead(ctx, r.db, id)
+	if err != nil {
+		return Canvas{}, err
+	}
+
+	const query = "select version, kind from canvas_stream_events where drawing_id = $1 and created_at <= $2 " +
+		"order by version desc limit 1"
+	var last StreamEvent
+	if err := r.db.GetContext(ctx, &last, query, id, at); err != nil {
+		if goerrors.Is(err, sql.ErrNoRows) {
+			return Canvas{}, ErrNotFound
+		}
+
+		return Canvas{}, fmt.Errorf("database err: %w", err)
+	}
+
+	if last.Kind == StreamDeleted {
+		return Canvas{}, ErrNotFound
+	}
+
+	drawing, err := r.build(ctx, r.db, id, last.Version)
+	if err != nil {
+		return Canvas{}, err
+	}
+	return Canvas{ID: id, OwnerID: head.OwnerID, WorkspaceID: head.WorkspaceID, Drawing: drawing, CreatedAt: head.CreatedAt}, nil
+}
+
+func (r *streamRepository) GetEvents(ctx context.Context, id string) ([]StreamEvent, error) {
+	if _, err := r.getHead(ctx, r.db, id); err != nil {
+		return nil, err
+	}
+
+	const query = "select version, kind, patch, created_at from canvas_stream_events where drawing_id = $1 order by version"
+	var events []StreamEvent
+	if err := r.db.SelectContext(ctx, &events, query, id); err != nil {
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+
+	if len(events) == 0 {
+		return nil, ErrNotFound
+	}
+	return events, nil
+}
+
+func (r *streamRepository) getHead(ctx context.Context, q sqlx.QueryerContext, id string) (streamHead, error) {
+	const query = "select id, owner_id, workspace_id, version, deleted, created_at from canvas_streams " +
+		"where id = $1 and workspace_id = $2"
+	var head streamHead
+	if err := sqlx.GetContext(ctx, q, &head, query, id, workspace.ID(ctx)); err != nil {
+		if goerrors.Is(err, sql.ErrNoRows) {
+			return head, ErrNotFound
+		}
+
+		return head, fmt.Errorf("database err: %w", err)
+	}
+	return head, nil
+}
+
+// nextVersion runs the query that moves the stream to its next version, which
+// locks it until the transaction ends. The query takes the id and workspace,
+// then the args.
+func (r *streamRepository) nextVersion(ctx context.Context, q sqlx.QueryerContext, query string, id string, args ...interface{}) (int, error) {
+	var version int
+	if err := sqlx.GetContext(ctx, q, &version, query, append([]interface{}{id, workspace.ID(ctx)}, args...)...); err != nil {
+		if goerrors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNotFound
+		}
+
+		return 0, fmt.Errorf("database err: %w", err)
+	}
+	return version, nil
+}
+
+// build replays the events of the stream up to the version, from the latest
+// snapshot before it.
+func (r *streamRepository) build(ctx context.Context, q sqlx.QueryerContext, id string, version int) (string, error) {
+	const snapshotQuery = "select version, drawing from canvas_snapshots where drawing_id = $1 and version <= $2 " +
+		"order by version desc limit 1"
+	var from snapshot
+	if err := sqlx.GetContext(ctx, q, &from, snapshotQuery, id, version); err != nil && !goerrors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("database err: %w", err)
+	}
+
+	const eventsQuery = "select version, kind, patch, created_at from canvas_stream_events " +
+		"where drawing_id = $1 and version > $2 and version <= $3 order by version"
+	var events []StreamEvent
+	if err := sqlx.SelectContext(ctx, q, &events, eventsQuery, id, from.Version, version); err != nil {
+		return "", fmt.Errorf("database err: %w", err)
+	}
+
+	drawing := from.Drawing
+	for _, event := range events {
+		drawing = event.Patch.Apply(drawing)
+	}
+	return drawing, nil
+}
+
+// append adds the event to the stream, with a snapshot of the drawing it
+// results in when it is due.
+func (r *streamRepository) append(ctx context.Context, tx *transaction, id string, event StreamEvent, drawing string) error {
+	const query = "insert into canvas_stream_events (drawing_id, version, kind, patch, created_at) values ($1, $2, $3, $4, $5)"
+	if _, err := tx.ExecContext(ctx, query, id, event.Version, event.Kind, event.Patch, event.CreatedAt); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	if event.Version%snapshotInterval != 0 || event.Kind == StreamDeleted {
+		return nil
+	}
+
+	const snapshotQuery = "insert into canvas_snapshots (drawing_id, version, drawing, created_at) values ($1, $2, $3, $4)"
+	if _, err := tx.ExecContext(ctx, snapshotQuery, id, event.Version, drawing, event.CreatedAt); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
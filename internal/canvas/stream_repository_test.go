@@ -0,0 +1,197 @@
+package canvas_test
+
+import (
+	"context"
+	"database/sql"
+	"sketch/internal/canvas"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	streamHeadQuery = "select id, owner_id, workspace_id, version, deleted, created_at from canvas_streams " +
+		"where id = $1 and workspace_id = $2"
+	streamSnapshotQuery = "select version, drawing from canvas_snapshots where drawing_id = $1 and version <= $2 " +
+		"order by version desc limit 1"
+	streamEventsQuery = "select version, kind, patch, created_at from canvas_stream_events " +
+		"where drawing_id = $1 and version > $2 and version <= $3 order by version"
+	streamAppendQuery = "insert into canvas_stream_events (drawing_id, version, kind, patch, created_at) values ($1, $2, $3, $4, $5)"
+)
+
+func newStreamRepository() (canvas.StreamRepository, sqlmock.Sqlmock) {
+	mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	return canvas.NewStreamRepository(sqlx.NewDb(mockDB, "sqlmock")), mock
+}
+
+func TestStreamRepository_GetByID(t *testing.T) {
+	createdAt := time.Now().UTC()
+
+	t.Run("should replay the events after the latest snapshot", func(t *testing.T) {
+		repository, mock := newStreamRepository()
+
+		mock.ExpectQuery(streamHeadQuery).WithArgs("123", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "version", "deleted", "created_at"}).
+				AddRow("123", 22, false, createdAt))
+		mock.ExpectQuery(streamSnapshotQuery).WithArgs("123", 22).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "drawing"}).AddRow(20, "+---+\n|   |\n+---+"))
+		mock.ExpectQuery(streamEventsQuery).WithArgs("123", 20, 22).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "kind", "patch", "created_at"}).
+				AddRow(21, canvas.StreamDrawn, []byte(`{"rows":3,"spans":[{"x":2,"y":1,"text":"*"}]}`), createdAt).
+				AddRow(22, canvas.StreamDrawn, []byte(`{"rows":3,"spans":[{"x":5,"y":2,"text":" @","cut":true}]}`), createdAt))
+
+		result, err := repository.GetByID(context.Background(), "123")
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Equal(t, canvas.Canvas{ID: "123", Drawing: "+---+\n| * |\n+---+ @", CreatedAt: createdAt, Version: 22}, result)
+	})
+
+	t.Run("when the canvas was deleted, should return not found error", func(t *testing.T) {
+		repository, mock := newStreamRepository()
+
+		mock.ExpectQuery(streamHeadQuery).WithArgs("123", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "version", "deleted", "created_at"}).
+				AddRow("123", 3, true, createdAt))
+
+		_, err := repository.GetByID(context.Background(), "123")
+
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+	})
+}
+
+func TestStreamRepository_Save(t *testing.T) {
+	t.Run("should start the stream with the whole drawing", func(t *testing.T) {
+		repository, mock := newStreamRepository()
+		fakeCanvas := canvas.NewCanvas("ab\ncd")
+
+		mock.ExpectBegin()
+		mock.ExpectExec("insert into canvas_streams (id, owner_id, workspace_id, version, deleted, created_at) values ($1, $2, $3, 1, false, $4)").
+			WithArgs(fakeCanvas.ID, fakeCanvas.OwnerID, fakeCanvas.WorkspaceID, fakeCanvas.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(streamAppendQuery).
+			WithArgs(fakeCanvas.ID, 1, canvas.StreamCreated, canvas.Diff("", "ab\ncd"), fakeCanvas.CreatedAt).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repository.Save(context.Background(), fakeCanvas)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestStreamRepository_Update(t *testing.T) {
+	const nextVersion = "update canvas_streams set version = version + 1 " +
+		"where id = $1 and workspace_id = $2 and not deleted and ($3 = 0 or version = $3) returning version"
+
+	t.Run("should append the patch from the current drawing and snapshot it when due", func(t *testing.T) {
+		repository, mock := newStreamRepository()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(nextVersion).WithArgs("123", "", 0).
+			WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(20))
+		mock.ExpectQuery(streamSnapshotQuery).WithArgs("123", 19).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(streamEventsQuery).WithArgs("123", 0, 19).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "kind", "patch", "created_at"}).
+				AddRow(1, canvas.StreamCreated, []byte(`{"rows":1,"spans":[{"x":0,"y":0,"text":"ab","cut":true}]}`), time.Now()))
+		mock.ExpectExec(streamAppendQuery).
+			WithArgs("123", 20, canvas.StreamDrawn, canvas.Patch{Rows: 1, Spans: []canvas.Span{{X: 1, Text: "c"}}}, sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("insert into canvas_snapshots (drawing_id, version, drawing, created_at) values ($1, $2, $3, $4)").
+			WithArgs("123", 20, "ac", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repository.Update(context.Background(), canvas.Canvas{ID: "123", Drawing: "ac"})
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when the stream does not exist or was deleted, should return not found error", func(t *testing.T) {
+		repository, mock := newStreamRepository()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(nextVersion).WithArgs("123", "", 0).WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		err := repository.Update(context.Background(), canvas.Canvas{ID: "123", Drawing: "ac"})
+
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when the stream moved past the version read, should return a conflict", func(t *testing.T) {
+		repository, mock := newStreamRepository()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(nextVersion).WithArgs("123", "", 4).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery("select owner_id from canvas_streams where id = $1 and workspace_id = $2 and not deleted").
+			WithArgs("123", "").
+			WillReturnRows(sqlmock.NewRows([]string{"owner_id"}).AddRow("owner"))
+		mock.ExpectRollback()
+
+		err := repository.Update(context.Background(), canvas.Canvas{ID: "123", Drawing: "ac", Version: 4})
+
+		assert.ErrorIs(t, err, canvas.ErrConflict)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestStreamRepository_Replay(t *testing.T) {
+	const lastQuery = "select version, kind from canvas_stream_events where drawing_id = $1 and created_at <= $2 " +
+		"order by version desc limit 1"
+	createdAt := time.Now().UTC().Add(-time.Hour)
+
+	t.Run("should rebuild the canvas up to the last event before the time", func(t *testing.T) {
+		repository, mock := newStreamRepository()
+		at := createdAt.Add(time.Minute)
+
+		mock.ExpectQuery(streamHeadQuery).WithArgs("123", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "version", "deleted", "created_at"}).
+				AddRow("123", 5, true, createdAt))
+		mock.ExpectQuery(lastQuery).WithArgs("123", at).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "kind"}).AddRow(2, canvas.StreamDrawn))
+		mock.ExpectQuery(streamSnapshotQuery).WithArgs("123", 2).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(streamEventsQuery).WithArgs("123", 0, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "kind", "patch", "created_at"}).
+				AddRow(1, canvas.StreamCreated, []byte(`{"rows":1,"spans":[{"x":0,"y":0,"text":"ab","cut":true}]}`), createdAt).
+				AddRow(2, canvas.StreamDrawn, []byte(`{"rows":2,"spans":[{"x":0,"y":1,"text":"cd","cut":true}]}`), createdAt))
+
+		result, err := repository.Replay(context.Background(), "123", at)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ab\ncd", result.Drawing)
+	})
+
+	t.Run("when the canvas was deleted at that time, should return not found error", func(t *testing.T) {
+		repository, mock := newStreamRepository()
+
+		mock.ExpectQuery(streamHeadQuery).WithArgs("123", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "version", "deleted", "created_at"}).
+				AddRow("123", 5, true, createdAt))
+		mock.ExpectQuery(lastQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"version", "kind"}).AddRow(5, canvas.StreamDeleted))
+
+		_, err := repository.Replay(context.Background(), "123", time.Now())
+
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+	})
+
+	t.Run("when the canvas did not exist yet, should return not found error", func(t *testing.T) {
+		repository, mock := newStreamRepository()
+
+		mock.ExpectQuery(streamHeadQuery).WithArgs("123", "").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "version", "deleted", "created_at"}).
+				AddRow("123", 5, false, createdAt))
+		mock.ExpectQuery(lastQuery).WillReturnError(sql.ErrNoRows)
+
+		_, err := repository.Replay(context.Background(), "123", createdAt.Add(-time.Hour))
+
+		assert.ErrorIs(t, err, canvas.ErrNotFound)
+	})
+}
@@ -0,0 +1,72 @@
+package canvas_test
+
+import (
+	"sketch/internal/canvas"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name  string
+		from  string
+		to    string
+		spans []canvas.Span
+	}{
+		{
+			name:  "when a cell changes, should write only that cell",
+			from:  "+---+\n|   |\n+---+",
+			to:    "+---+\n| * |\n+---+",
+			spans: []canvas.Span{{X: 2, Y: 1, Text: "*"}},
+		},
+		{
+			name:  "when a row grows, should write its end and cut it there",
+			from:  "ab\ncd",
+			to:    "ab\ncd  @",
+			spans: []canvas.Span{{X: 2, Y: 1, Text: "  @", Cut: true}},
+		},
+		{
+			name:  "when a row shrinks, should cut it",
+			from:  "abcd",
+			to:    "ab",
+			spans: []canvas.Span{{X: 2, Y: 0, Text: "", Cut: true}},
+		},
+		{
+			name:  "when rows are added, should write them whole",
+			from:  "ab",
+			to:    "ab\n\n xy",
+			spans: []canvas.Span{{X: 0, Y: 2, Text: " xy", Cut: true}},
+		},
+		{
+			name: "when nothing changes, should have no spans",
+			from: "ab\ncd",
+			to:   "ab\ncd",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch := canvas.Diff(tt.from, tt.to)
+
+			assert.Equal(t, tt.spans, patch.Spans)
+			assert.Equal(t, tt.to, patch.Apply(tt.from))
+		})
+	}
+}
+
+func TestPatch_Apply(t *testing.T) {
+	t.Run("should rebuild every drawing of a history from the previous one", func(t *testing.T) {
+		history := []string{"", "hello", "hello\n  wörld", "héllo\n  wörld!", "", "a\nb\nc", "a", "  ┌─┐\n  └─┘"}
+		drawing := ""
+		for _, next := range history {
+			drawing = canvas.Diff(drawing, next).Apply(drawing)
+			assert.Equal(t, next, drawing)
+		}
+	})
+
+	t.Run("when a span starts past the end of its row, should pad the row", func(t *testing.T) {
+		patch := canvas.Patch{Rows: 2, Spans: []canvas.Span{{X: 3, Y: 1, Text: "x"}}}
+
+		assert.Equal(t, "ab\n   x", patch.Apply("ab"))
+	})
+}
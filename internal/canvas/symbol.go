@@ -0,0 +1,115 @@
+package canvas
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sketch/internal/errors"
+	"time"
+)
+
+var (
+	ErrInvalidSymbolName = errors.Error("symbol name must contain only lowercase letters, digits, '-' or '_'")
+	ErrSymbolSource      = errors.Error("a symbol must be made of either requests or a canvas_id")
+	ErrNestedSymbol      = errors.Error("a symbol cannot contain other symbols")
+
+	namePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+)
+
+type (
+	// Symbol is a reusable component. It is either a group of draw operations
+	// or the rendered drawing of a stored canvas.
+	Symbol struct {
+		// OwnerID is empty for the symbols of a workspace, which belong to
+		// all of it.
+		OwnerID string `json:"owner_id,omitempty" db:"owner_id"`
+		// WorkspaceID is empty for the symbols outside every workspace.
+		WorkspaceID string       `json:"workspace_id,omitempty" db:"workspace_id"`
+		Name        string       `json:"name" db:"name"`
+		Requests    DrawRequests `json:"requests,omitempty" db:"requests"`
+		Drawing     string       `json:"drawing,omitempty" db:"drawing"`
+		UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+	}
+
+	SymbolRequest struct {
+		Name     string       `json:"name"`
+		Requests DrawRequests `json:"requests"`
+		CanvasID string       `json:"canvas_id"`
+	}
+
+	// LinkedDrawing is a canvas that asked to be re-rendered whenever one of
+	// the symbols it uses changes.
+	LinkedDrawing struct {
+		ID       string       `db:"drawing_id"`
+		Requests DrawRequests `db:"requests"`
+	}
+)
+
+func (s SymbolRequest) Validate() error {
+	if !namePattern.MatchString(s.Name) {
+		return ErrInvalidSymbolName
+	}
+
+	hasRequests := len(s.Requests) > 0
+	hasCanvas := s.CanvasID != ""
+	if hasRequests == hasCanvas {
+		return ErrSymbolSource
+	}
+
+	if !hasRequests {
+		return nil
+	}
+
+	for _, request := range s.Requests {
+		if request.Type == OperationSymbol {
+			return ErrNestedSymbol
+		}
+	}
+	return s.Requests.Validate()
+}
+
+// expandSymbols replaces every symbol operation with the operations of the
+// symbol moved to the instance position. It also returns the names of the
+// symbols whose instances are linked.
+func expandSymbols(ctx context.Context, symbols SymbolRepository, requests DrawRequests) (DrawRequests, []string, error) {
+	expanded := make(DrawRequests, 0, len(requests))
+	linked := make([]string, 0)
+
+	for _, request := range requests {
+		if request.Type != OperationSymbol {
+			expanded = append(expanded, request)
+			continue
+		}
+
+		symbol, err := symbols.GetByName(ctx, request.Symbol)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get symbol '%s': %w", request.Symbol, err)
+		}
+
+		if request.Linked {
+			linked = append(linked, symbol.Name)
+		}
+
+		if symbol.Drawing != "" {
+			expanded = append(expanded, DrawRequest{
+				Type: OperationText,
+				X:    request.X,
+				Y:    request.Y,
+				Text: symbol.Drawing,
+			})
+			continue
+		}
+
+		for _, operation := range symbol.Requests {
+			operation.X += request.X
+			operation.Y += request.Y
+			if operation.Type == OperationLine {
+				operation.ToX += request.X
+				operation.ToY += request.Y
+			}
+			expanded = append(expanded, operation)
+		}
+	}
+
+	return expanded, linked, nil
+}
@@ -0,0 +1,51 @@
+package canvas
+
+import (
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"sketch/internal/routing"
+)
+
+type SymbolHandler struct {
+	service SymbolService
+}
+
+func NewSymbolHandler(service SymbolService) *SymbolHandler {
+	return &SymbolHandler{
+		service: service,
+	}
+}
+
+func (c *SymbolHandler) Save(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+	request, err := routing.FromJSON[SymbolRequest](r)
+	if err != nil {
+		return fmt.Errorf("failed to get json body: %w", err)
+	}
+
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	symbol, err := c.service.Save(r.Context(), request)
+	if err := accessError(w, err); err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, symbol)
+}
+
+func (c *SymbolHandler) GetByName(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	symbol, err := c.service.GetByName(r.Context(), params.ByName("name"))
+
+	if errors.Is(err, ErrSymbolNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, symbol)
+}
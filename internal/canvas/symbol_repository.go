@@ -0,0 +1,156 @@
+package canvas
+
+import (
+	"context"
+	"database/sql"
+	goerrors "errors"
+	"fmt"
+	"sketch/internal/errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrSymbolNotFound = errors.Error("symbol not found")
+)
+
+type (
+	SymbolRepository interface {
+		GetByName(ctx context.Context, name string) (Symbol, error)
+		Save(ctx context.Context, symbol Symbol) error
+		Link(ctx context.Context, drawingID string, symbols []string, requests DrawRequests) error
+		GetLinked(ctx context.Context, symbol string) ([]LinkedDrawing, error)
+		GetOperations(ctx context.Context, drawingID string) (DrawRequests, error)
+		Unlink(ctx context.Context, drawingID string) error
+	}
+
+	symbolRepository struct {
+		db *sqlx.DB
+	}
+)
+
+func NewSymbolRepository(db *sqlx.DB) SymbolRepository {
+	return &symbolRepository{
+		db: db,
+	}
+}
+
+// GetByName returns the symbol of the owner, or of the workspace, of the
+// request.
+func (r *symbolRepository) GetByName(ctx context.Context, name string) (Symbol, error) {
+	const query = "select owner_id, workspace_id, name, requests, coalesce(drawing, '') as drawing, updated_at from symbols " +
+		"where owner_id = $1 and workspace_id = $2 and name = $3"
+	ownerID, workspaceID := ownedScope(ctx)
+	var symbol Symbol
+	if err := r.db.GetContext(ctx, &symbol, query, ownerID, workspaceID, name); err != nil {
+		if goerrors.Is(err, sql.ErrNoRows) {
+			return symbol, ErrSymbolNotFound
+		}
+
+		return symbol, fmt.Errorf("database err: %w", err)
+	}
+	return symbol, nil
+}
+
+func (r *symbolRepository) Save(ctx context.Context, symbol Symbol) error {
+	const query = "insert into symbols (owner_id, workspace_id, name, requests, drawing, updated_at) values ($1, $2, $3, $4, $5, $6) " +
+		"on conflict (owner_id, workspace_id, name) do update " +
+		"set requests = excluded.requests, drawing = excluded.drawing, updated_at = excluded.updated_at"
+	var requests any
+	if len(symbol.Requests) > 0 {
+		requests = symbol.Requests
+	}
+	var drawing any
+	if symbol.Drawing != "" {
+		drawing = symbol.Drawing
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, symbol.OwnerID, symbol.WorkspaceID, symbol.Name, requests, drawing, symbol.UpdatedAt); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+// Link keeps the operations of a drawing so it can be rendered again when any
+// of the given symbols, of the owner or workspace of the request, change.
+func (r *symbolRepository) Link(ctx context.Context, drawingID string, symbols []string, requests DrawRequests) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	defer tx.Rollback()
+
+	const saveRequests = "insert into drawing_operations (drawing_id, requests) values ($1, $2) " +
+		"on conflict (drawing_id) do update set requests = excluded.requests"
+	if _, err := tx.ExecContext(ctx, saveRequests, drawingID, requests); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	const saveLink = "insert into symbol_links (owner_id, workspace_id, symbol_name, drawing_id) values ($1, $2, $3, $4) " +
+		"on conflict do nothing"
+	ownerID, workspaceID := ownedScope(ctx)
+	for _, symbol := range symbols {
+		if _, err := tx.ExecContext(ctx, saveLink, ownerID, workspaceID, symbol, drawingID); err != nil {
+			return fmt.Errorf("database err: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+// GetLinked returns the drawings linked to the symbol of the owner, or of the
+// workspace, of the request.
+func (r *symbolRepository) GetLinked(ctx context.Context, symbol string) ([]LinkedDrawing, error) {
+	const query = "select o.drawing_id, o.requests from symbol_links l " +
+		"join drawing_operations o on o.drawing_id = l.drawing_id " +
+		"where l.owner_id = $1 and l.workspace_id = $2 and l.symbol_name = $3"
+	ownerID, workspaceID := ownedScope(ctx)
+	var drawings []LinkedDrawing
+	if err := r.db.SelectContext(ctx, &drawings, query, ownerID, workspaceID, symbol); err != nil {
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+	return drawings, nil
+}
+
+// GetOperations returns the operations kept for a linked drawing, none when
+// it is not linked to any symbol.
+func (r *symbolRepository) GetOperations(ctx context.Context, drawingID string) (DrawRequests, error) {
+	const query = "select requests from drawing_operations where drawing_id = $1"
+	var requests DrawRequests
+	if err := r.db.GetContext(ctx, &requests, query, drawingID); err != nil {
+		if goerrors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+	return requests, nil
+}
+
+// Unlink forgets the operations of a drawing, so it is no longer rendered
+// when its symbols change.
+func (r *symbolRepository) Unlink(ctx context.Context, drawingID string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	defer tx.Rollback()
+
+	const deleteLinks = "delete from symbol_links where drawing_id = $1"
+	if _, err := tx.ExecContext(ctx, deleteLinks, drawingID); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	const deleteRequests = "delete from drawing_operations where drawing_id = $1"
+	if _, err := tx.ExecContext(ctx, deleteRequests, drawingID); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
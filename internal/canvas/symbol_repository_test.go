@@ -0,0 +1,126 @@
+package canvas_test
+
+import (
+	"context"
+	"database/sql"
+	"sketch/internal/auth"
+	"sketch/internal/canvas"
+	"sketch/internal/workspace"
+	"sketch/tests/faker"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbolRepository_GetByName(t *testing.T) {
+	owner := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+	const query = "select owner_id, workspace_id, name, requests, coalesce(drawing, '') as drawing, updated_at from symbols " +
+		"where owner_id = $1 and workspace_id = $2 and name = $3"
+	setup := func() (canvas.SymbolRepository, sqlmock.Sqlmock) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		return canvas.NewSymbolRepository(sqlx.NewDb(mockDB, "sqlmock")), mock
+	}
+
+	t.Run("when there is a result, should decode its requests", func(t *testing.T) {
+		repository, mock := setup()
+		updatedAt := time.Now().UTC()
+		rows := sqlmock.NewRows([]string{"owner_id", "workspace_id", "name", "requests", "drawing", "updated_at"}).
+			AddRow("owner", "", "server", []byte(`[{"x":1,"y":0,"width":2,"height":1,"fill":"#"}]`), "", updatedAt)
+		mock.ExpectQuery(query).WithArgs("owner", "", "server").WillReturnRows(rows)
+
+		result, err := repository.GetByName(owner, "server")
+
+		assert.NoError(t, err)
+		assert.Equal(t, canvas.Symbol{
+			OwnerID:   "owner",
+			Name:      "server",
+			Requests:  canvas.DrawRequests{{X: 1, Width: 2, Height: 1, Fill: "#"}},
+			UpdatedAt: updatedAt,
+		}, result)
+	})
+
+	t.Run("when there are no results, should return symbol not found error", func(t *testing.T) {
+		repository, mock := setup()
+		mock.ExpectQuery(query).WithArgs("", "ws", "server").WillReturnError(sql.ErrNoRows)
+
+		_, err := repository.GetByName(workspace.WithScope(owner, workspace.Scope{WorkspaceID: "ws", Role: workspace.RoleViewer}), "server")
+
+		assert.ErrorIs(t, err, canvas.ErrSymbolNotFound)
+	})
+}
+
+func TestSymbolRepository_GetOperations(t *testing.T) {
+	const query = "select requests from drawing_operations where drawing_id = $1"
+	setup := func() (canvas.SymbolRepository, sqlmock.Sqlmock) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		return canvas.NewSymbolRepository(sqlx.NewDb(mockDB, "sqlmock")), mock
+	}
+
+	t.Run("when the drawing is linked, should decode its operations", func(t *testing.T) {
+		repository, mock := setup()
+		rows := sqlmock.NewRows([]string{"requests"}).AddRow([]byte(`[{"type":"symbol","symbol":"server","linked":true}]`))
+		mock.ExpectQuery(query).WithArgs("123").WillReturnRows(rows)
+
+		result, err := repository.GetOperations(context.Background(), "123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, canvas.DrawRequests{{Type: canvas.OperationSymbol, Symbol: "server", Linked: true}}, result)
+	})
+
+	t.Run("when the drawing is not linked, should return no operations", func(t *testing.T) {
+		repository, mock := setup()
+		mock.ExpectQuery(query).WithArgs("123").WillReturnError(sql.ErrNoRows)
+
+		result, err := repository.GetOperations(context.Background(), "123")
+
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestSymbolRepository_Link(t *testing.T) {
+	const (
+		saveRequests = "insert into drawing_operations (drawing_id, requests) values ($1, $2) " +
+			"on conflict (drawing_id) do update set requests = excluded.requests"
+		saveLink = "insert into symbol_links (owner_id, workspace_id, symbol_name, drawing_id) values ($1, $2, $3, $4) " +
+			"on conflict do nothing"
+	)
+	requests := canvas.DrawRequests{{Type: canvas.OperationSymbol, Symbol: "server", Linked: true}}
+
+	t.Run("when there are no errors, should store the requests and every link", func(t *testing.T) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		repository := canvas.NewSymbolRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+		mock.ExpectBegin()
+		mock.ExpectExec(saveRequests).
+			WithArgs("123", sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(saveLink).WithArgs("owner", "", "server", "123").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(saveLink).WithArgs("owner", "", "rack", "123").WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+		err := repository.Link(ctx, "123", []string{"server", "rack"}, requests)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("when a link fails, should rollback and return the error", func(t *testing.T) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		repository := canvas.NewSymbolRepository(sqlx.NewDb(mockDB, "sqlmock"))
+
+		mock.ExpectBegin()
+		mock.ExpectExec(saveRequests).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(saveLink).WillReturnError(faker.NewError())
+		mock.ExpectRollback()
+
+		err := repository.Link(context.Background(), "123", []string{"server"}, requests)
+
+		assert.ErrorIs(t, err, faker.NewError())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
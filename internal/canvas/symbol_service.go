@@ -0,0 +1,106 @@
+package canvas
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type (
+	symbolService struct {
+		repository Repository
+		drawer     Drawer
+		symbols    SymbolRepository
+		access     AccessService
+		events     Publisher
+	}
+	SymbolService interface {
+		GetByName(ctx context.Context, name string) (*Symbol, error)
+		Save(ctx context.Context, request SymbolRequest) (*Symbol, error)
+	}
+)
+
+func NewSymbolService(repository Repository, drawer Drawer, symbols SymbolRepository, access AccessService, events Publisher) SymbolService {
+	return &symbolService{
+		repository: repository,
+		drawer:     drawer,
+		symbols:    symbols,
+		access:     access,
+		events:     events,
+	}
+}
+
+func (s symbolService) GetByName(ctx context.Context, name string) (*Symbol, error) {
+	symbol, err := s.symbols.GetByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get symbol '%s': %w", name, err)
+	}
+	return &symbol, nil
+}
+
+// Save creates or replaces the symbol of the owner, or of the workspace, of
+// the request and renders again every drawing linked to it.
+func (s symbolService) Save(ctx context.Context, request SymbolRequest) (*Symbol, error) {
+	ownerID, workspaceID := ownedScope(ctx)
+	symbol := Symbol{
+		OwnerID:     ownerID,
+		WorkspaceID: workspaceID,
+		Name:        request.Name,
+		Requests:    request.Requests,
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	if request.CanvasID != "" {
+		if err := s.access.CanRead(ctx, request.CanvasID); err != nil {
+			return nil, err
+		}
+
+		canvas, err := s.repository.GetByID(ctx, request.CanvasID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get '%s': %w", request.CanvasID, err)
+		}
+		symbol.Drawing = canvas.Drawing
+	}
+
+	if err := s.symbols.Save(ctx, symbol); err != nil {
+		return nil, fmt.Errorf("error saving symbol: %w", err)
+	}
+
+	if err := s.renderLinked(ctx, symbol.Name); err != nil {
+		return nil, err
+	}
+
+	return &symbol, nil
+}
+
+// renderLinked renders again the drawings linked to the symbol. Those the
+// request may no longer change keep the symbol as it was.
+func (s symbolService) renderLinked(ctx context.Context, name string) error {
+	drawings, err := s.symbols.GetLinked(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed to get drawings linked to '%s': %w", name, err)
+	}
+
+	for _, linked := range drawings {
+		err := s.access.CanModify(ctx, linked.ID)
+		if isDenied(err) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to check '%s': %w", linked.ID, err)
+		}
+
+		draw, _, err := render(ctx, s.drawer, s.symbols, linked.Requests)
+		if err != nil {
+			return fmt.Errorf("failed to render '%s': %w", linked.ID, err)
+		}
+
+		canvas := Canvas{ID: linked.ID, Drawing: draw}
+		if err := s.repository.Update(ctx, canvas); err != nil {
+			return fmt.Errorf("error updating canvas '%s': %w", linked.ID, err)
+		}
+		s.events.Publish(ctx, NewEvent(EventUpdated, canvas))
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package canvas_test
+
+import (
+	"context"
+	"fmt"
+	"sketch/internal/auth"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"sketch/tests/faker"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbolService_Save(t *testing.T) {
+	server := canvas.DrawRequests{{Width: 3, Height: 2, Fill: "#"}}
+
+	t.Run("when the symbol comes from a canvas, should store its drawing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		symbolsMock := mock_canvas.NewMockSymbolRepository(ctrl)
+		accessMock := mock_canvas.NewMockAccessService(ctrl)
+		service := canvas.NewSymbolService(repositoryMock, canvas.NewDrawer(), symbolsMock, accessMock, canvas.NewBroker())
+		ctx := context.Background()
+		fakeCanvas := faker.NewCanvas(t)
+
+		accessMock.EXPECT().CanRead(ctx, fakeCanvas.ID).Return(nil)
+		repositoryMock.EXPECT().GetByID(ctx, fakeCanvas.ID).Return(fakeCanvas, nil)
+		symbolsMock.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+		symbolsMock.EXPECT().GetLinked(ctx, "smile").Return(nil, nil)
+
+		symbol, err := service.Save(ctx, canvas.SymbolRequest{Name: "smile", CanvasID: fakeCanvas.ID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, fakeCanvas.Drawing, symbol.Drawing)
+	})
+
+	t.Run("when the request may not see the canvas, should not copy its drawing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		symbolsMock := mock_canvas.NewMockSymbolRepository(ctrl)
+		accessMock := mock_canvas.NewMockAccessService(ctrl)
+		service := canvas.NewSymbolService(nil, canvas.NewDrawer(), symbolsMock, accessMock, canvas.NewBroker())
+		ctx := context.Background()
+
+		accessMock.EXPECT().CanRead(ctx, "123").Return(canvas.ErrForbidden)
+
+		symbol, err := service.Save(ctx, canvas.SymbolRequest{Name: "smile", CanvasID: "123"})
+
+		assert.ErrorIs(t, err, canvas.ErrForbidden)
+		assert.Nil(t, symbol)
+	})
+
+	t.Run("when saving the symbol fails, should return the error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		symbolsMock := mock_canvas.NewMockSymbolRepository(ctrl)
+		service := canvas.NewSymbolService(nil, canvas.NewDrawer(), symbolsMock, nil, canvas.NewBroker())
+		ctx := context.Background()
+
+		symbolsMock.EXPECT().Save(ctx, gomock.Any()).Return(faker.NewError())
+
+		symbol, err := service.Save(ctx, canvas.SymbolRequest{Name: "server", Requests: server})
+
+		assert.ErrorIs(t, err, faker.NewError())
+		assert.Nil(t, symbol)
+	})
+
+	t.Run("when drawings are linked to the symbol, should render them again", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		symbolsMock := mock_canvas.NewMockSymbolRepository(ctrl)
+		accessMock := mock_canvas.NewMockAccessService(ctrl)
+		service := canvas.NewSymbolService(repositoryMock, canvas.NewDrawer(), symbolsMock, accessMock, canvas.NewBroker())
+		ctx := context.Background()
+		linked := canvas.LinkedDrawing{
+			ID:       "linked-id",
+			Requests: canvas.DrawRequests{{Type: canvas.OperationSymbol, Symbol: "server", X: 1, Linked: true}},
+		}
+
+		symbolsMock.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+		symbolsMock.EXPECT().GetLinked(ctx, "server").Return([]canvas.LinkedDrawing{linked}, nil)
+		accessMock.EXPECT().CanModify(ctx, "linked-id").Return(nil)
+		symbolsMock.EXPECT().GetByName(ctx, "server").Return(canvas.Symbol{Name: "server", Requests: server}, nil)
+		repositoryMock.EXPECT().Update(ctx, canvas.Canvas{ID: "linked-id", Drawing: " ###\n ###"}).Return(nil)
+
+		_, err := service.Save(ctx, canvas.SymbolRequest{Name: "server", Requests: server})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("when the request may not change a linked drawing, should leave it as it was", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		symbolsMock := mock_canvas.NewMockSymbolRepository(ctrl)
+		accessMock := mock_canvas.NewMockAccessService(ctrl)
+		service := canvas.NewSymbolService(nil, canvas.NewDrawer(), symbolsMock, accessMock, canvas.NewBroker())
+		ctx := context.Background()
+
+		symbolsMock.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+		symbolsMock.EXPECT().GetLinked(ctx, "server").Return([]canvas.LinkedDrawing{{ID: "other-id"}}, nil)
+		accessMock.EXPECT().CanModify(ctx, "other-id").Return(fmt.Errorf("failed to get 'other-id': %w", canvas.ErrForbidden))
+
+		_, err := service.Save(ctx, canvas.SymbolRequest{Name: "server", Requests: server})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("when the request has an owner, should save the symbol as theirs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		symbolsMock := mock_canvas.NewMockSymbolRepository(ctrl)
+		service := canvas.NewSymbolService(nil, canvas.NewDrawer(), symbolsMock, nil, canvas.NewBroker())
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+
+		symbolsMock.EXPECT().Save(ctx, gomock.Any()).
+			Do(func(_ context.Context, symbol canvas.Symbol) {
+				assert.Equal(t, "owner", symbol.OwnerID)
+				assert.Empty(t, symbol.WorkspaceID)
+			})
+		symbolsMock.EXPECT().GetLinked(ctx, "server").Return(nil, nil)
+
+		_, err := service.Save(ctx, canvas.SymbolRequest{Name: "server", Requests: server})
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestService_SaveWithSymbols(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repositoryMock := mock_canvas.NewMockRepository(ctrl)
+	symbolsMock := mock_canvas.NewMockSymbolRepository(ctrl)
+	service := canvas.NewService(repositoryMock, canvas.NewDrawer(), symbolsMock, canvas.NewBroker())
+	ctx := context.Background()
+	requests := canvas.DrawRequests{
+		{Type: canvas.OperationSymbol, Symbol: "server"},
+		{Type: canvas.OperationSymbol, Symbol: "server", X: 4, Y: 1, Linked: true},
+	}
+
+	symbolsMock.EXPECT().GetByName(ctx, "server").
+		Times(2).
+		Return(canvas.Symbol{Name: "server", Requests: canvas.DrawRequests{{Width: 3, Height: 1, Fill: "#"}}}, nil)
+	repositoryMock.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+	symbolsMock.EXPECT().Link(ctx, gomock.Any(), []string{"server"}, requests).Return(nil)
+
+	response, err := service.Save(ctx, requests)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "###\n    ###", response.Drawing)
+}
+
+func TestService_SaveWithLineSymbol(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repositoryMock := mock_canvas.NewMockRepository(ctrl)
+	symbolsMock := mock_canvas.NewMockSymbolRepository(ctrl)
+	service := canvas.NewService(repositoryMock, canvas.NewDrawer(), symbolsMock, canvas.NewBroker())
+	ctx := context.Background()
+	requests := canvas.DrawRequests{{Type: canvas.OperationSymbol, Symbol: "wire", X: 4, Y: 1}}
+
+	symbolsMock.EXPECT().GetByName(ctx, "wire").
+		Return(canvas.Symbol{Name: "wire", Requests: canvas.DrawRequests{{Type: canvas.OperationLine, X: 0, Y: 0, ToX: 2, ToY: 0}}}, nil)
+	repositoryMock.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+
+	response, err := service.Save(ctx, requests)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "\n    ---", response.Drawing)
+}
+
+func TestService_EditLinked(t *testing.T) {
+	linked := canvas.DrawRequests{{Type: canvas.OperationSymbol, Symbol: "server", X: 1, Linked: true}}
+	edit := canvas.DrawRequests{{X: 5, Width: 1, Height: 1, Fill: "@"}}
+
+	t.Run("when a linked canvas is edited, should keep the edit once its symbol changes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		symbolsMock := mock_canvas.NewMockSymbolRepository(ctrl)
+		service := canvas.NewService(repositoryMock, canvas.NewDrawer(), symbolsMock, canvas.NewBroker())
+		accessMock := mock_canvas.NewMockAccessService(ctrl)
+		symbolService := canvas.NewSymbolService(repositoryMock, canvas.NewDrawer(), symbolsMock, accessMock, canvas.NewBroker())
+		ctx := context.Background()
+
+		var operations canvas.DrawRequests
+		repositoryMock.EXPECT().GetByID(ctx, "linked-id").Return(canvas.Canvas{ID: "linked-id", Drawing: " ###"}, nil)
+		symbolsMock.EXPECT().GetOperations(ctx, "linked-id").Return(linked, nil)
+		repositoryMock.EXPECT().Update(ctx, canvas.Canvas{ID: "linked-id", Drawing: " ### @"}).Return(nil)
+		symbolsMock.EXPECT().Link(ctx, "linked-id", gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, _ []string, requests canvas.DrawRequests) error {
+				operations = requests
+				return nil
+			})
+
+		_, err := service.Edit(ctx, "linked-id", edit)
+		assert.NoError(t, err)
+		assert.Equal(t, append(linked, edit...), operations)
+
+		symbolsMock.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+		symbolsMock.EXPECT().GetLinked(ctx, "server").Return([]canvas.LinkedDrawing{{ID: "linked-id", Requests: operations}}, nil)
+		accessMock.EXPECT().CanModify(ctx, "linked-id").Return(nil)
+		symbolsMock.EXPECT().GetByName(ctx, "server").
+			Return(canvas.Symbol{Name: "server", Requests: canvas.DrawRequests{{Width: 2, Height: 1, Fill: "%"}}}, nil)
+		repositoryMock.EXPECT().Update(ctx, canvas.Canvas{ID: "linked-id", Drawing: " %%  @"}).Return(nil)
+
+		_, err = symbolService.Save(ctx, canvas.SymbolRequest{Name: "server", Requests: canvas.DrawRequests{{Width: 2, Height: 1, Fill: "%"}}})
+		assert.NoError(t, err)
+	})
+
+	t.Run("when an edit links a canvas for the first time, should keep what it had drawn", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockRepository(ctrl)
+		symbolsMock := mock_canvas.NewMockSymbolRepository(ctrl)
+		service := canvas.NewService(repositoryMock, canvas.NewDrawer(), symbolsMock, canvas.NewBroker())
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().GetByID(ctx, "id").Return(canvas.Canvas{ID: "id", Drawing: "hi"}, nil)
+		symbolsMock.EXPECT().GetByName(ctx, "server").
+			Return(canvas.Symbol{Name: "server", Requests: canvas.DrawRequests{{Width: 3, Height: 1, Fill: "#"}}}, nil)
+		symbolsMock.EXPECT().GetOperations(ctx, "id").Return(nil, nil)
+		repositoryMock.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+		symbolsMock.EXPECT().Link(ctx, "id", []string{"server"}, append(canvas.DrawRequests{{Type: canvas.OperationText, Text: "hi"}}, linked...)).
+			Return(nil)
+
+		response, err := service.Edit(ctx, "id", linked)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "h###", response.Drawing)
+	})
+}
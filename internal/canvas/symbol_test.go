@@ -0,0 +1,61 @@
+package canvas_test
+
+import (
+	"sketch/internal/canvas"
+	"sketch/tests/faker"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbolRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request canvas.SymbolRequest
+		assert  func(t *testing.T, err error)
+	}{
+		{
+			name:    "when the name has invalid characters, should return an error",
+			request: canvas.SymbolRequest{Name: "My Server", CanvasID: "123"},
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, canvas.ErrInvalidSymbolName)
+			},
+		},
+		{
+			name:    "when neither requests nor canvas are informed, should return an error",
+			request: canvas.SymbolRequest{Name: "server"},
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, canvas.ErrSymbolSource)
+			},
+		},
+		{
+			name:    "when both requests and canvas are informed, should return an error",
+			request: canvas.SymbolRequest{Name: "server", CanvasID: "123", Requests: faker.NewDrawRequests(t)},
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, canvas.ErrSymbolSource)
+			},
+		},
+		{
+			name: "when requests contain another symbol, should return an error",
+			request: canvas.SymbolRequest{Name: "server", Requests: canvas.DrawRequests{
+				{Type: canvas.OperationSymbol, Symbol: "rack"},
+			}},
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, canvas.ErrNestedSymbol)
+			},
+		},
+		{
+			name:    "when requests are valid, should return no error",
+			request: canvas.SymbolRequest{Name: "server_1", Requests: faker.NewDrawRequests(t)},
+			assert: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.assert(t, tc.request.Validate())
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package canvas
+
+import (
+	"sketch/internal/errors"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	BorderASCII   TableBorder = "ascii"
+	BorderUnicode TableBorder = "unicode"
+
+	AlignLeft   Alignment = "left"
+	AlignCenter Alignment = "center"
+	AlignRight  Alignment = "right"
+
+	OverflowWrap     Overflow = "wrap"
+	OverflowTruncate Overflow = "truncate"
+)
+
+var (
+	ErrMissingTable     = errors.Error("table operations require a table")
+	ErrEmptyTable       = errors.Error("table must have at least one column")
+	ErrInvalidBorder    = errors.Error("table border must be ascii or unicode")
+	ErrInvalidAlignment = errors.Error("table alignment must be left, center or right, one per column")
+	ErrInvalidOverflow  = errors.Error("table overflow must be wrap or truncate")
+	ErrInvalidMaxWidth  = errors.Error("table max width must be equal or greater than zero")
+
+	// borders lists, in order, the horizontal and vertical lines followed by
+	// the top, middle and bottom joints from left to right.
+	borders = map[TableBorder][]string{
+		BorderASCII:   {"-", "|", "+", "+", "+", "+", "+", "+", "+", "+", "+"},
+		BorderUnicode: {"─", "│", "┌", "┬", "┐", "├", "┼", "┤", "└", "┴", "┘"},
+	}
+)
+
+type (
+	// TableBorder is the set of characters used to draw the lines of a table.
+	TableBorder string
+
+	Alignment string
+
+	// Overflow tells what happens to cells wider than the table max width:
+	// they are wrapped into more lines or cut.
+	Overflow string
+
+	Table struct {
+		Headers  []string    `json:"headers,omitempty"`
+		Rows     [][]string  `json:"rows,omitempty"`
+		Border   TableBorder `json:"border,omitempty"`
+		Align    []Alignment `json:"align,omitempty"`
+		MaxWidth int         `json:"max_width,omitempty"`
+		Overflow Overflow    `json:"overflow,omitempty"`
+	}
+)
+
+func (t Table) Validate() error {
+	if t.columns() == 0 {
+		return ErrEmptyTable
+	}
+
+	if _, ok := borders[t.border()]; !ok {
+		return ErrInvalidBorder
+	}
+
+	if len(t.Align) > t.columns() {
+		return ErrInvalidAlignment
+	}
+	for _, align := range t.Align {
+		switch align {
+		case "", AlignLeft, AlignCenter, AlignRight:
+		default:
+			return ErrInvalidAlignment
+		}
+	}
+
+	switch t.Overflow {
+	case "", OverflowWrap, OverflowTruncate:
+	default:
+		return ErrInvalidOverflow
+	}
+
+	if t.MaxWidth < 0 {
+		return ErrInvalidMaxWidth
+	}
+	return nil
+}
+
+// Render returns the lines of the bordered table. Headers, when informed, are
+// separated from the rows by a line.
+func (t Table) Render() []string {
+	chars := borders[t.border()]
+	columns := t.columns()
+	headers, rows, widths := t.layout()
+
+	separator := func(left, middle, right string) string {
+		parts := make([]string, columns)
+		for column, width := range widths {
+			parts[column] = strings.Repeat(chars[0], width+2)
+		}
+		return left + strings.Join(parts, middle) + right
+	}
+
+	result := []string{separator(chars[2], chars[3], chars[4])}
+	if len(t.Headers) != 0 {
+		result = append(result, t.renderRow(headers, widths, chars[1])...)
+		if len(rows) != 0 {
+			result = append(result, separator(chars[5], chars[6], chars[7]))
+		}
+	}
+	for _, row := range rows {
+		result = append(result, t.renderRow(row, widths, chars[1])...)
+	}
+	return append(result, separator(chars[8], chars[9], chars[10]))
+}
+
+// Size returns the columns and lines the table takes, without rendering it.
+func (t Table) Size() (int, int) {
+	headers, rows, widths := t.layout()
+
+	width := 1
+	for _, columnWidth := range widths {
+		width += columnWidth + 3
+	}
+
+	height := 2
+	if len(t.Headers) != 0 {
+		height += rowHeight(headers)
+		if len(rows) != 0 {
+			height++
+		}
+	}
+	for _, row := range rows {
+		height += rowHeight(row)
+	}
+	return width, height
+}
+
+// layout splits the headers and the rows into the lines of their cells, and
+// returns the width of each column.
+func (t Table) layout() ([][]string, [][][]string, []int) {
+	columns := t.columns()
+	headers := t.cells(t.Headers, columns)
+	rows := make([][][]string, len(t.Rows))
+	for i, row := range t.Rows {
+		rows[i] = t.cells(row, columns)
+	}
+
+	widths := make([]int, columns)
+	for _, row := range append([][][]string{headers}, rows...) {
+		for column, lines := range row {
+			if length := longest(lines); length > widths[column] {
+				widths[column] = length
+			}
+		}
+	}
+	return headers, rows, widths
+}
+
+func (t Table) renderRow(cells [][]string, widths []int, vertical string) []string {
+	result := make([]string, rowHeight(cells))
+	for i := range result {
+		line := strings.Builder{}
+		line.WriteString(vertical)
+		for column, lines := range cells {
+			value := ""
+			if i < len(lines) {
+				value = lines[i]
+			}
+			line.WriteString(" " + t.align(value, widths[column], column) + " " + vertical)
+		}
+		result[i] = line.String()
+	}
+	return result
+}
+
+// rowHeight returns the lines of the tallest cell of the row.
+func rowHeight(cells [][]string) int {
+	height := 1
+	for _, lines := range cells {
+		if len(lines) > height {
+			height = len(lines)
+		}
+	}
+	return height
+}
+
+func (t Table) align(value string, width, column int) string {
+	missing := width - utf8.RuneCountInString(value)
+	alignment := AlignLeft
+	if column < len(t.Align) && t.Align[column] != "" {
+		alignment = t.Align[column]
+	}
+
+	switch alignment {
+	case AlignRight:
+		return strings.Repeat(" ", missing) + value
+	case AlignCenter:
+		left := missing / 2
+		return strings.Repeat(" ", left) + value + strings.Repeat(" ", missing-left)
+	}
+	return value + strings.Repeat(" ", missing)
+}
+
+// cells splits every value of the row into the lines it takes, filling the
+// columns the row does not have with empty cells.
+func (t Table) cells(row []string, columns int) [][]string {
+	result := make([][]string, columns)
+	for column := range result {
+		value := ""
+		if column < len(row) {
+			value = row[column]
+		}
+
+		for _, line := range strings.Split(value, "\n") {
+			result[column] = append(result[column], t.fit(line)...)
+		}
+	}
+	return result
+}
+
+func (t Table) fit(line string) []string {
+	if t.MaxWidth == 0 || utf8.RuneCountInString(line) <= t.MaxWidth {
+		return []string{line}
+	}
+	if t.Overflow == OverflowTruncate {
+		return []string{truncate(line, t.MaxWidth)}
+	}
+	return wrap(line, t.MaxWidth)
+}
+
+// wrap breaks the line between words, splitting the words longer than the
+// width.
+func wrap(line string, width int) []string {
+	result := make([]string, 0)
+	current := make([]rune, 0, width)
+	for _, word := range strings.Fields(line) {
+		runes := []rune(word)
+		if len(current) != 0 && len(current)+1+len(runes) > width {
+			result = append(result, string(current))
+			current = current[:0]
+		}
+		if len(current) != 0 {
+			current = append(current, ' ')
+		}
+
+		for len(current)+len(runes) > width {
+			split := width - len(current)
+			result = append(result, string(append(current, runes[:split]...)))
+			current, runes = current[:0], runes[split:]
+		}
+		current = append(current, runes...)
+	}
+	return append(result, string(current))
+}
+
+func (t Table) border() TableBorder {
+	if t.Border == "" {
+		return BorderASCII
+	}
+	return t.Border
+}
+
+func (t Table) columns() int {
+	columns := len(t.Headers)
+	for _, row := range t.Rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	return columns
+}
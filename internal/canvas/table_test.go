@@ -0,0 +1,153 @@
+package canvas_test
+
+import (
+	"sketch/internal/canvas"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTable_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		table       canvas.Table
+		expectedErr error
+	}{
+		{
+			name:        "when there are no columns, should return an error",
+			table:       canvas.Table{Rows: [][]string{{}}},
+			expectedErr: canvas.ErrEmptyTable,
+		},
+		{
+			name:        "when the border is unknown, should return an error",
+			table:       canvas.Table{Headers: []string{"a"}, Border: "double"},
+			expectedErr: canvas.ErrInvalidBorder,
+		},
+		{
+			name:        "when there are more alignments than columns, should return an error",
+			table:       canvas.Table{Headers: []string{"a"}, Align: []canvas.Alignment{canvas.AlignLeft, canvas.AlignRight}},
+			expectedErr: canvas.ErrInvalidAlignment,
+		},
+		{
+			name:        "when the alignment is unknown, should return an error",
+			table:       canvas.Table{Headers: []string{"a"}, Align: []canvas.Alignment{"justify"}},
+			expectedErr: canvas.ErrInvalidAlignment,
+		},
+		{
+			name:        "when the overflow is unknown, should return an error",
+			table:       canvas.Table{Headers: []string{"a"}, Overflow: "hide"},
+			expectedErr: canvas.ErrInvalidOverflow,
+		},
+		{
+			name:        "when the max width is negative, should return an error",
+			table:       canvas.Table{Headers: []string{"a"}, MaxWidth: -1},
+			expectedErr: canvas.ErrInvalidMaxWidth,
+		},
+		{
+			name:  "when the table is valid, should return no error",
+			table: canvas.Table{Rows: [][]string{{"a", "b"}}, Align: []canvas.Alignment{"", canvas.AlignCenter}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorIs(t, tc.table.Validate(), tc.expectedErr)
+		})
+	}
+}
+
+func TestTable_Size(t *testing.T) {
+	tables := map[string]canvas.Table{
+		"when the table has headers and rows, should count the separators": {
+			Headers: []string{"name", "qty"},
+			Rows:    [][]string{{"apple", "1"}, {"kiwi\nfruit", "10"}},
+		},
+		"when the table has only rows, should leave the header out":    {Rows: [][]string{{"a", "b", "c"}}},
+		"when the table has only headers, should leave the rows out":   {Headers: []string{"a"}},
+		"when the cells are wrapped, should count the lines they take": {Rows: [][]string{{"a long cell to wrap"}}, MaxWidth: 6},
+	}
+
+	for name, table := range tables {
+		t.Run(name+", should match its rendering", func(t *testing.T) {
+			rendered := table.Render()
+			width, height := table.Size()
+
+			assert.Equal(t, len(rendered), height)
+			assert.Equal(t, len([]rune(rendered[0])), width)
+		})
+	}
+}
+
+func TestDrawer_DrawTable(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+		request  canvas.DrawRequest
+	}{
+		{
+			name: "ascii borders, should separate the headers and align the columns",
+			expected: `
+  +------+-----+
+  | name | age |
+  +------+-----+
+  | bob  |   3 |
+  | ana  |  41 |
+  +------+-----+`,
+			request: canvas.DrawRequest{Type: canvas.OperationTable, X: 2, Y: 1, Table: &canvas.Table{
+				Headers: []string{"name", "age"},
+				Rows:    [][]string{{"bob", "3"}, {"ana", "41"}},
+				Align:   []canvas.Alignment{canvas.AlignLeft, canvas.AlignRight},
+			}},
+		},
+		{
+			name: "unicode borders without headers, should fill the missing cells",
+			expected: `┌───┬───┐
+│ a │ b │
+│ c │   │
+└───┴───┘`,
+			request: canvas.DrawRequest{Type: canvas.OperationTable, Table: &canvas.Table{
+				Rows:   [][]string{{"a", "b"}, {"c"}},
+				Border: canvas.BorderUnicode,
+			}},
+		},
+		{
+			name: "max width with wrap, should break the cells between words",
+			expected: `+-------+
+| a     |
+| quick |
+| fox   |
+| jumpe |
+| d     |
++-------+`,
+			request: canvas.DrawRequest{Type: canvas.OperationTable, Table: &canvas.Table{
+				Rows:     [][]string{{"a quick fox"}, {"jumped"}},
+				MaxWidth: 5,
+			}},
+		},
+		{
+			name: "max width with truncate, should cut the cells",
+			expected: `+-----+
+| abc |
+| de  |
++-----+`,
+			request: canvas.DrawRequest{Type: canvas.OperationTable, Table: &canvas.Table{
+				Rows:     [][]string{{"abcdef"}, {"de"}},
+				Align:    []canvas.Alignment{canvas.AlignCenter},
+				MaxWidth: 3,
+				Overflow: canvas.OverflowTruncate,
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.NoError(t, tc.request.Validate())
+
+			drawer := canvas.NewDrawer()
+			got, err := drawer.Draw([]canvas.DrawRequest{tc.request})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
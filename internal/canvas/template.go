@@ -0,0 +1,213 @@
+package canvas
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sketch/internal/errors"
+	"time"
+)
+
+const (
+	VariableString  VariableType = "string"
+	VariableInteger VariableType = "integer"
+)
+
+var (
+	ErrInvalidTemplateName  = errors.Error("template name must contain only lowercase letters, digits, '-' or '_'")
+	ErrInvalidVariable      = errors.Error("invalid template variable")
+	ErrUndeclaredVariable   = errors.Error("template uses an undeclared variable")
+	ErrMissingVariable      = errors.Error("missing value for template variable")
+	ErrUnknownVariable      = errors.Error("unknown template variable")
+	ErrInvalidVariableValue = errors.Error("invalid value for template variable")
+	ErrInvalidTemplateBody  = errors.Error("template requests must be a json array of draw requests")
+
+	placeholderPattern = regexp.MustCompile(`{{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*}}`)
+)
+
+type (
+	VariableType string
+
+	TemplateVariable struct {
+		Name    string       `json:"name"`
+		Type    VariableType `json:"type"`
+		Default any          `json:"default,omitempty"`
+	}
+
+	TemplateVariables []TemplateVariable
+
+	// Template is a list of draw requests where any string may reference a
+	// variable with {{name}}. A string made only of an integer placeholder
+	// becomes a number, so "width": "{{size}}" is a valid template field.
+	Template struct {
+		// OwnerID is empty for the templates of a workspace, which belong to
+		// all of it.
+		OwnerID string `json:"owner_id,omitempty"`
+		// WorkspaceID is empty for the templates outside every workspace.
+		WorkspaceID string            `json:"workspace_id,omitempty"`
+		Name        string            `json:"name"`
+		Variables   TemplateVariables `json:"variables"`
+		Requests    json.RawMessage   `json:"requests"`
+		UpdatedAt   time.Time         `json:"updated_at"`
+	}
+
+	TemplateValues map[string]any
+)
+
+func (t Template) Validate() error {
+	if !namePattern.MatchString(t.Name) {
+		return ErrInvalidTemplateName
+	}
+
+	declared := make(map[string]TemplateVariable, len(t.Variables))
+	for _, variable := range t.Variables {
+		if variable.Name == "" || (variable.Type != VariableString && variable.Type != VariableInteger) {
+			return fmt.Errorf("%w: '%s' must have a name and a type (string or integer)", ErrInvalidVariable, variable.Name)
+		}
+		if _, ok := declared[variable.Name]; ok {
+			return fmt.Errorf("%w: '%s' is declared twice", ErrInvalidVariable, variable.Name)
+		}
+		if variable.Default != nil {
+			if _, err := variable.convert(variable.Default); err != nil {
+				return err
+			}
+		}
+		declared[variable.Name] = variable
+	}
+
+	var body []any
+	if err := json.Unmarshal(t.Requests, &body); err != nil {
+		return ErrInvalidTemplateBody
+	}
+
+	for _, name := range placeholders(body) {
+		if _, ok := declared[name]; !ok {
+			return fmt.Errorf("%w: '%s'", ErrUndeclaredVariable, name)
+		}
+	}
+	return nil
+}
+
+// Render substitutes the values in the template and decodes the result into
+// draw requests. Variables without a value use their default.
+func (t Template) Render(values TemplateValues) (DrawRequests, error) {
+	resolved := make(map[string]any, len(t.Variables))
+	for _, variable := range t.Variables {
+		value, ok := values[variable.Name]
+		if !ok {
+			value = variable.Default
+		}
+		if value == nil {
+			return nil, fmt.Errorf("%w: '%s'", ErrMissingVariable, variable.Name)
+		}
+
+		converted, err := variable.convert(value)
+		if err != nil {
+			return nil, err
+		}
+		resolved[variable.Name] = converted
+	}
+
+	for name := range values {
+		if _, ok := resolved[name]; !ok {
+			return nil, fmt.Errorf("%w: '%s'", ErrUnknownVariable, name)
+		}
+	}
+
+	var body any
+	if err := json.Unmarshal(t.Requests, &body); err != nil {
+		return nil, ErrInvalidTemplateBody
+	}
+
+	substituted, err := json.Marshal(substitute(body, resolved))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode template: %w", err)
+	}
+
+	var requests DrawRequests
+	if err := json.Unmarshal(substituted, &requests); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTemplateBody, err.Error())
+	}
+	return requests, nil
+}
+
+func (v TemplateVariable) convert(value any) (any, error) {
+	switch v.Type {
+	case VariableString:
+		if text, ok := value.(string); ok {
+			return text, nil
+		}
+	case VariableInteger:
+		if number, ok := value.(float64); ok && number == math.Trunc(number) {
+			return int(number), nil
+		}
+		if number, ok := value.(int); ok {
+			return number, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: '%s' must be a %s", ErrInvalidVariableValue, v.Name, v.Type)
+}
+
+func substitute(node any, values map[string]any) any {
+	switch value := node.(type) {
+	case []any:
+		for i, item := range value {
+			value[i] = substitute(item, values)
+		}
+	case map[string]any:
+		for key, item := range value {
+			value[key] = substitute(item, values)
+		}
+	case string:
+		if match := placeholderPattern.FindStringSubmatch(value); match != nil && match[0] == value {
+			if number, ok := values[match[1]].(int); ok {
+				return number
+			}
+		}
+		return placeholderPattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+			name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+			return fmt.Sprint(values[name])
+		})
+	}
+	return node
+}
+
+// placeholders returns the names of the placeholders of the strings substitute
+// replaces, the values of the node and not its keys.
+func placeholders(node any) []string {
+	names := make([]string, 0)
+	switch value := node.(type) {
+	case []any:
+		for _, item := range value {
+			names = append(names, placeholders(item)...)
+		}
+	case map[string]any:
+		for _, item := range value {
+			names = append(names, placeholders(item)...)
+		}
+	case string:
+		for _, match := range placeholderPattern.FindAllStringSubmatch(value, -1) {
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+func (t TemplateVariables) Value() (driver.Value, error) {
+	return json.Marshal(t)
+}
+
+func (t *TemplateVariables) Scan(src any) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, t)
+	case string:
+		return json.Unmarshal([]byte(value), t)
+	case nil:
+		*t = nil
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into template variables", src)
+}
@@ -0,0 +1,97 @@
+package canvas
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"sketch/internal/routing"
+)
+
+type TemplateHandler struct {
+	service TemplateService
+}
+
+func NewTemplateHandler(service TemplateService) *TemplateHandler {
+	return &TemplateHandler{
+		service: service,
+	}
+}
+
+func (c *TemplateHandler) Save(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+	template, err := routing.FromJSON[Template](r)
+	if err != nil {
+		return fmt.Errorf("failed to get json body: %w", err)
+	}
+
+	if err := template.Validate(); err != nil {
+		return err
+	}
+
+	saved, err := c.service.Save(r.Context(), template)
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, saved)
+}
+
+func (c *TemplateHandler) GetByName(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	template, err := c.service.GetByName(r.Context(), params.ByName("name"))
+
+	if errors.Is(err, ErrTemplateNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, template)
+}
+
+func (c *TemplateHandler) Render(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	values, err := routing.FromJSON[TemplateValues](r)
+	if err != nil {
+		return fmt.Errorf("failed to get json body: %w", err)
+	}
+
+	response, err := c.service.Render(r.Context(), params.ByName("name"), values)
+
+	if errors.Is(err, ErrTemplateNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, response)
+}
+
+// RenderCost weighs a render by the area of the canvas the template draws
+// with the values of the body. Templates or values it cannot read weigh one,
+// as they fail later anyway.
+func (c *TemplateHandler) RenderCost(r *http.Request, params httprouter.Params) int {
+	body, ok := peekBody(r)
+	if !ok {
+		return 1
+	}
+
+	var values TemplateValues
+	if err := json.Unmarshal(body, &values); err != nil {
+		return 1
+	}
+
+	template, err := c.service.GetByName(r.Context(), params.ByName("name"))
+	if err != nil {
+		return 1
+	}
+
+	requests, err := template.Render(values)
+	if err != nil {
+		return 1
+	}
+	return renderCost(requests)
+}
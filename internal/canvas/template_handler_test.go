@@ -0,0 +1,50 @@
+package canvas_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateHandler_RenderCost(t *testing.T) {
+	params := httprouter.Params{{Key: "name", Value: "labeled-box"}}
+
+	t.Run("when the template draws a canvas, should weigh it by its area and leave the body", func(t *testing.T) {
+		serviceMock := mock_canvas.NewMockTemplateService(gomock.NewController(t))
+		body := `{"label": "hi", "width": 10}`
+		r := httptest.NewRequest(http.MethodPost, "/templates/labeled-box/render", bytes.NewReader([]byte(body)))
+		template := newFakeTemplate()
+
+		serviceMock.EXPECT().GetByName(gomock.Any(), "labeled-box").Return(&template, nil)
+
+		cost := canvas.NewTemplateHandler(serviceMock).RenderCost(r, params)
+		left, _ := io.ReadAll(r.Body)
+
+		assert.Equal(t, 10*3, cost)
+		assert.Equal(t, body, string(left))
+	})
+
+	t.Run("when the template does not exist, should weigh it as one", func(t *testing.T) {
+		serviceMock := mock_canvas.NewMockTemplateService(gomock.NewController(t))
+		r := httptest.NewRequest(http.MethodPost, "/templates/labeled-box/render", bytes.NewReader([]byte(`{}`)))
+
+		serviceMock.EXPECT().GetByName(gomock.Any(), "labeled-box").Return(nil, canvas.ErrTemplateNotFound)
+
+		assert.Equal(t, 1, canvas.NewTemplateHandler(serviceMock).RenderCost(r, params))
+	})
+
+	t.Run("when the values cannot be read, should weigh them as one without getting the template", func(t *testing.T) {
+		serviceMock := mock_canvas.NewMockTemplateService(gomock.NewController(t))
+		r := httptest.NewRequest(http.MethodPost, "/templates/labeled-box/render", bytes.NewReader([]byte("not json")))
+
+		assert.Equal(t, 1, canvas.NewTemplateHandler(serviceMock).RenderCost(r, params))
+	})
+}
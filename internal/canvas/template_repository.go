@@ -0,0 +1,80 @@
+package canvas
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"sketch/internal/errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrTemplateNotFound = errors.Error("template not found")
+)
+
+type (
+	TemplateRepository interface {
+		GetByName(ctx context.Context, name string) (Template, error)
+		Save(ctx context.Context, template Template) error
+	}
+
+	templateRepository struct {
+		db *sqlx.DB
+	}
+
+	templateRow struct {
+		OwnerID     string            `db:"owner_id"`
+		WorkspaceID string            `db:"workspace_id"`
+		Name        string            `db:"name"`
+		Variables   TemplateVariables `db:"variables"`
+		Requests    string            `db:"requests"`
+		UpdatedAt   time.Time         `db:"updated_at"`
+	}
+)
+
+func NewTemplateRepository(db *sqlx.DB) TemplateRepository {
+	return &templateRepository{
+		db: db,
+	}
+}
+
+// GetByName returns the template of the owner, or of the workspace, of the
+// request.
+func (r *templateRepository) GetByName(ctx context.Context, name string) (Template, error) {
+	const query = "select owner_id, workspace_id, name, variables, requests, updated_at from templates " +
+		"where owner_id = $1 and workspace_id = $2 and name = $3"
+	ownerID, workspaceID := ownedScope(ctx)
+	var row templateRow
+	if err := r.db.GetContext(ctx, &row, query, ownerID, workspaceID, name); err != nil {
+		if goerrors.Is(err, sql.ErrNoRows) {
+			return Template{}, ErrTemplateNotFound
+		}
+
+		return Template{}, fmt.Errorf("database err: %w", err)
+	}
+
+	return Template{
+		OwnerID:     row.OwnerID,
+		WorkspaceID: row.WorkspaceID,
+		Name:        row.Name,
+		Variables:   row.Variables,
+		Requests:    json.RawMessage(row.Requests),
+		UpdatedAt:   row.UpdatedAt,
+	}, nil
+}
+
+func (r *templateRepository) Save(ctx context.Context, template Template) error {
+	const query = "insert into templates (owner_id, workspace_id, name, variables, requests, updated_at) values ($1, $2, $3, $4, $5, $6) " +
+		"on conflict (owner_id, workspace_id, name) do update " +
+		"set variables = excluded.variables, requests = excluded.requests, updated_at = excluded.updated_at"
+	_, err := r.db.ExecContext(ctx, query, template.OwnerID, template.WorkspaceID, template.Name, template.Variables,
+		string(template.Requests), template.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
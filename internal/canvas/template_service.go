@@ -0,0 +1,65 @@
+package canvas
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type (
+	templateService struct {
+		templates TemplateRepository
+		canvases  Service
+	}
+	TemplateService interface {
+		GetByName(ctx context.Context, name string) (*Template, error)
+		Save(ctx context.Context, template Template) (*Template, error)
+		Render(ctx context.Context, name string, values TemplateValues) (*DrawResponse, error)
+	}
+)
+
+func NewTemplateService(templates TemplateRepository, canvases Service) TemplateService {
+	return &templateService{
+		templates: templates,
+		canvases:  canvases,
+	}
+}
+
+func (s templateService) GetByName(ctx context.Context, name string) (*Template, error) {
+	template, err := s.templates.GetByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template '%s': %w", name, err)
+	}
+	return &template, nil
+}
+
+// Save creates or replaces the template of the owner, or of the workspace, of
+// the request.
+func (s templateService) Save(ctx context.Context, template Template) (*Template, error) {
+	template.OwnerID, template.WorkspaceID = ownedScope(ctx)
+	template.UpdatedAt = time.Now().UTC()
+	if err := s.templates.Save(ctx, template); err != nil {
+		return nil, fmt.Errorf("error saving template: %w", err)
+	}
+	return &template, nil
+}
+
+// Render fills the template with the values and saves the result as a new
+// canvas.
+func (s templateService) Render(ctx context.Context, name string, values TemplateValues) (*DrawResponse, error) {
+	template, err := s.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := template.Render(values)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := requests.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.canvases.Save(ctx, requests)
+}
@@ -0,0 +1,107 @@
+package canvas_test
+
+import (
+	"context"
+	"sketch/internal/auth"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"sketch/internal/workspace"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateService_Render(t *testing.T) {
+	type arrange struct {
+		templateErr error
+		values      canvas.TemplateValues
+		saved       int
+	}
+
+	fakeResponse := &canvas.DrawResponse{ID: "id", Drawing: "#######\n#[db]  #\n#######"}
+
+	tests := []struct {
+		name    string
+		arrange arrange
+		assert  func(t *testing.T, response *canvas.DrawResponse, err error)
+	}{
+		{
+			name:    "when the template does not exist, should return an error",
+			arrange: arrange{templateErr: canvas.ErrTemplateNotFound},
+			assert: func(t *testing.T, response *canvas.DrawResponse, err error) {
+				assert.ErrorIs(t, err, canvas.ErrTemplateNotFound)
+				assert.Nil(t, response)
+			},
+		},
+		{
+			name:    "when the rendered requests are invalid, should return an error",
+			arrange: arrange{values: canvas.TemplateValues{"label": "db", "width": float64(0)}},
+			assert: func(t *testing.T, response *canvas.DrawResponse, err error) {
+				assert.ErrorContains(t, err, "width and height")
+				assert.Nil(t, response)
+			},
+		},
+		{
+			name:    "when the values are valid, should save the rendered canvas",
+			arrange: arrange{values: canvas.TemplateValues{"label": "db"}, saved: 1},
+			assert: func(t *testing.T, response *canvas.DrawResponse, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, fakeResponse, response)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			templatesMock := mock_canvas.NewMockTemplateRepository(ctrl)
+			canvasesMock := mock_canvas.NewMockService(ctrl)
+			service := canvas.NewTemplateService(templatesMock, canvasesMock)
+			ctx := context.Background()
+
+			templatesMock.EXPECT().GetByName(ctx, "labeled-box").
+				Return(newFakeTemplate(), tc.arrange.templateErr)
+			canvasesMock.EXPECT().Save(ctx, gomock.Any()).
+				Times(tc.arrange.saved).
+				Return(fakeResponse, nil)
+
+			response, err := service.Render(ctx, "labeled-box", tc.arrange.values)
+
+			tc.assert(t, response, err)
+		})
+	}
+}
+
+func TestTemplateService_Save(t *testing.T) {
+	t.Run("when the request is in a workspace, should save the template of the workspace", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		templatesMock := mock_canvas.NewMockTemplateRepository(ctrl)
+		service := canvas.NewTemplateService(templatesMock, nil)
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+		ctx = workspace.WithScope(ctx, workspace.Scope{WorkspaceID: "ws", Role: workspace.RoleEditor})
+
+		templatesMock.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+
+		template, err := service.Save(ctx, newFakeTemplate())
+
+		assert.NoError(t, err)
+		assert.Empty(t, template.OwnerID)
+		assert.Equal(t, "ws", template.WorkspaceID)
+	})
+
+	t.Run("when the request is outside of workspaces, should save the template of its owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		templatesMock := mock_canvas.NewMockTemplateRepository(ctrl)
+		service := canvas.NewTemplateService(templatesMock, nil)
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+
+		templatesMock.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+
+		template, err := service.Save(ctx, newFakeTemplate())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "owner", template.OwnerID)
+		assert.Empty(t, template.WorkspaceID)
+	})
+}
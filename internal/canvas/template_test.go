@@ -0,0 +1,156 @@
+package canvas_test
+
+import (
+	"encoding/json"
+	"sketch/internal/canvas"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newFakeTemplate() canvas.Template {
+	return canvas.Template{
+		Name: "labeled-box",
+		Variables: canvas.TemplateVariables{
+			{Name: "label", Type: canvas.VariableString},
+			{Name: "width", Type: canvas.VariableInteger, Default: float64(7)},
+		},
+		Requests: json.RawMessage(`[
+			{"x": 0, "y": 0, "width": "{{width}}", "height": 3, "outline": "#", "fill": "none"},
+			{"type": "text", "x": 1, "y": 1, "text": "[{{ label }}]"}
+		]`),
+	}
+}
+
+func TestTemplate_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template func() canvas.Template
+		assert   func(t *testing.T, err error)
+	}{
+		{
+			name:     "when the template is valid, should return no error",
+			template: newFakeTemplate,
+			assert: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "when a placeholder is not declared, should return an error",
+			template: func() canvas.Template {
+				template := newFakeTemplate()
+				template.Variables = template.Variables[1:]
+				return template
+			},
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, canvas.ErrUndeclaredVariable)
+				assert.ErrorContains(t, err, "label")
+			},
+		},
+		{
+			name: "when a placeholder is only in a key, which is never substituted, should not require it",
+			template: func() canvas.Template {
+				template := newFakeTemplate()
+				template.Requests = json.RawMessage(`[{"type": "text", "x": 1, "y": 1, "text": "{{label}}", "{{ note }}": 1}]`)
+				return template
+			},
+			assert: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "when a variable is declared twice, should return an error",
+			template: func() canvas.Template {
+				template := newFakeTemplate()
+				template.Variables = append(template.Variables, canvas.TemplateVariable{Name: "label", Type: canvas.VariableInteger})
+				return template
+			},
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, canvas.ErrInvalidVariable)
+				assert.ErrorContains(t, err, "label")
+			},
+		},
+		{
+			name: "when a variable has an unknown type, should return an error",
+			template: func() canvas.Template {
+				template := newFakeTemplate()
+				template.Variables[0].Type = "date"
+				return template
+			},
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, canvas.ErrInvalidVariable)
+			},
+		},
+		{
+			name: "when requests are not an array, should return an error",
+			template: func() canvas.Template {
+				template := newFakeTemplate()
+				template.Requests = json.RawMessage(`{}`)
+				return template
+			},
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, canvas.ErrInvalidTemplateBody)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.assert(t, tc.template().Validate())
+		})
+	}
+}
+
+func TestTemplate_Render(t *testing.T) {
+	tests := []struct {
+		name   string
+		values canvas.TemplateValues
+		assert func(t *testing.T, requests canvas.DrawRequests, err error)
+	}{
+		{
+			name:   "when all values are informed, should substitute them",
+			values: canvas.TemplateValues{"label": "db", "width": float64(10)},
+			assert: func(t *testing.T, requests canvas.DrawRequests, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 10, requests[0].Width)
+				assert.Equal(t, "[db]", requests[1].Text)
+			},
+		},
+		{
+			name:   "when a value with default is missing, should use the default",
+			values: canvas.TemplateValues{"label": "db"},
+			assert: func(t *testing.T, requests canvas.DrawRequests, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 7, requests[0].Width)
+			},
+		},
+		{
+			name:   "when a required value is missing, should return an error",
+			values: canvas.TemplateValues{},
+			assert: func(t *testing.T, requests canvas.DrawRequests, err error) {
+				assert.ErrorIs(t, err, canvas.ErrMissingVariable)
+			},
+		},
+		{
+			name:   "when a value has the wrong type, should return an error",
+			values: canvas.TemplateValues{"label": "db", "width": 2.5},
+			assert: func(t *testing.T, requests canvas.DrawRequests, err error) {
+				assert.ErrorIs(t, err, canvas.ErrInvalidVariableValue)
+			},
+		},
+		{
+			name:   "when a value is not declared, should return an error",
+			values: canvas.TemplateValues{"label": "db", "color": "red"},
+			assert: func(t *testing.T, requests canvas.DrawRequests, err error) {
+				assert.ErrorIs(t, err, canvas.ErrUnknownVariable)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			requests, err := newFakeTemplate().Render(tc.values)
+			tc.assert(t, requests, err)
+		})
+	}
+}
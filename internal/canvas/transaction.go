@@ -0,0 +1,60 @@
+package canvas
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	txKey struct{}
+
+	// transaction is a database transaction that may belong to an outer
+	// operation, which is then the one committing or rolling it back.
+	transaction struct {
+		*sqlx.Tx
+		joined bool
+	}
+)
+
+// withTx makes the repositories run in the transaction when given the
+// returned context.
+func withTx(ctx context.Context, tx *sqlx.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// beginTx starts a transaction, or joins the one the context carries.
+func beginTx(ctx context.Context, db *sqlx.DB) (*transaction, error) {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return &transaction{Tx: tx, joined: true}, nil
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &transaction{Tx: tx}, nil
+}
+
+// executor returns the transaction the context carries, if any, or the
+// database.
+func executor(ctx context.Context, db *sqlx.DB) sqlx.ExtContext {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+func (t *transaction) Commit() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+func (t *transaction) Rollback() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
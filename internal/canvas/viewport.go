@@ -0,0 +1,84 @@
+package canvas
+
+import (
+	"fmt"
+	"net/url"
+	"sketch/internal/errors"
+	"strconv"
+)
+
+const (
+	// maxViewportArea caps the cells of a viewport, which are all allocated
+	// whether the canvas has them or not.
+	maxViewportArea = 1_000_000
+	// maxViewportEnd caps how far a viewport may reach, keeping the cells it
+	// reads far from overflowing.
+	maxViewportEnd = 1 << 24
+)
+
+var (
+	ErrInvalidViewport  = errors.Error("viewport x, y, w and h must be integers")
+	ErrViewportTooLarge = errors.Error(fmt.Sprintf("viewport width times height must be at most %d", maxViewportArea))
+	ErrViewportTooFar   = errors.Error(fmt.Sprintf("viewport x plus width and y plus height must be at most %d", maxViewportEnd))
+)
+
+type Viewport struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// NewViewportFromQuery reads the x, y, w and h query parameters. It returns nil
+// when none of them were informed, meaning the whole canvas was requested.
+func NewViewportFromQuery(query url.Values) (*Viewport, error) {
+	keys := []string{"x", "y", "w", "h"}
+	values := make([]int, len(keys))
+	informed := false
+
+	for i, key := range keys {
+		raw := query.Get(key)
+		if raw == "" {
+			continue
+		}
+
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, ErrInvalidViewport
+		}
+		values[i] = value
+		informed = true
+	}
+
+	if !informed {
+		return nil, nil
+	}
+
+	viewport := Viewport{X: values[0], Y: values[1], Width: values[2], Height: values[3]}
+	if err := viewport.Validate(); err != nil {
+		return nil, err
+	}
+	return &viewport, nil
+}
+
+func (v Viewport) Validate() error {
+	if v.X < 0 || v.Y < 0 {
+		return errors.Error("coordinates must be equal or greater than zero")
+	}
+
+	if v.Width <= 0 || v.Height <= 0 {
+		return errors.Error("width and height must be greater than zero")
+	}
+
+	if v.Width > maxViewportArea || v.Height > maxViewportArea || v.Width*v.Height > maxViewportArea {
+		return ErrViewportTooLarge
+	}
+
+	// The sides are at most maxViewportArea by now, so only the coordinates
+	// may overflow the sums.
+	if v.X > maxViewportEnd || v.Y > maxViewportEnd || v.X+v.Width > maxViewportEnd || v.Y+v.Height > maxViewportEnd {
+		return ErrViewportTooFar
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package canvas_test
+
+import (
+	"net/url"
+	"sketch/internal/canvas"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewViewportFromQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  url.Values
+		assert func(t *testing.T, viewport *canvas.Viewport, err error)
+	}{
+		{
+			name:  "when no parameter is informed, should return nil",
+			query: url.Values{},
+			assert: func(t *testing.T, viewport *canvas.Viewport, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, viewport)
+			},
+		},
+		{
+			name:  "when all parameters are informed, should return the viewport",
+			query: url.Values{"x": {"1"}, "y": {"2"}, "w": {"3"}, "h": {"4"}},
+			assert: func(t *testing.T, viewport *canvas.Viewport, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, &canvas.Viewport{X: 1, Y: 2, Width: 3, Height: 4}, viewport)
+			},
+		},
+		{
+			name:  "when a parameter is not a number, should return an error",
+			query: url.Values{"x": {"a"}, "w": {"3"}, "h": {"4"}},
+			assert: func(t *testing.T, viewport *canvas.Viewport, err error) {
+				assert.ErrorIs(t, err, canvas.ErrInvalidViewport)
+				assert.Nil(t, viewport)
+			},
+		},
+		{
+			name:  "when the area is too large, should return an error",
+			query: url.Values{"w": {"1000000"}, "h": {"1000000"}},
+			assert: func(t *testing.T, viewport *canvas.Viewport, err error) {
+				assert.ErrorIs(t, err, canvas.ErrViewportTooLarge)
+				assert.Nil(t, viewport)
+			},
+		},
+		{
+			name:  "when the viewport reaches too far, should return an error",
+			query: url.Values{"x": {"9223372036854775807"}, "w": {"1"}, "h": {"1"}},
+			assert: func(t *testing.T, viewport *canvas.Viewport, err error) {
+				assert.ErrorIs(t, err, canvas.ErrViewportTooFar)
+				assert.Nil(t, viewport)
+			},
+		},
+		{
+			name:  "when the viewport ends past the limit, should return an error",
+			query: url.Values{"y": {"16777200"}, "w": {"1"}, "h": {"100"}},
+			assert: func(t *testing.T, viewport *canvas.Viewport, err error) {
+				assert.ErrorIs(t, err, canvas.ErrViewportTooFar)
+				assert.Nil(t, viewport)
+			},
+		},
+		{
+			name:  "when the size is missing, should return an error",
+			query: url.Values{"x": {"1"}},
+			assert: func(t *testing.T, viewport *canvas.Viewport, err error) {
+				assert.ErrorContains(t, err, "width and height")
+				assert.Nil(t, viewport)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			viewport, err := canvas.NewViewportFromQuery(tc.query)
+			tc.assert(t, viewport, err)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package canvas
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"sketch/internal/errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+
+	// SignatureHeader holds "sha256=" and the hex HMAC-SHA256 of the timestamp,
+	// a dot and the body, keyed with the secret of the webhook.
+	SignatureHeader = "X-Sketch-Signature"
+	TimestampHeader = "X-Sketch-Timestamp"
+	EventHeader     = "X-Sketch-Event"
+	DeliveryHeader  = "X-Sketch-Delivery"
+
+	maxWebhookURLLength = 2048
+	minSecretLength     = 16
+)
+
+var (
+	ErrInvalidWebhookURL       = errors.Error("url must be an absolute http or https url")
+	ErrForbiddenWebhookAddress = errors.Error("url must not point to a loopback, private or link-local address")
+	ErrInvalidWebhookEvents    = errors.Error("events must list canvas.created, canvas.updated or canvas.deleted")
+	ErrInvalidWebhookSecret    = errors.Error("secret must have at least 16 characters")
+	ErrWebhookNotFound         = errors.Error("webhook not found")
+)
+
+type (
+	EventTypes []EventType
+
+	WebhookRequest struct {
+		URL    string     `json:"url"`
+		Events EventTypes `json:"events"`
+		// Secret signs the deliveries. One is generated when it is empty.
+		Secret string `json:"secret"`
+	}
+
+	// Webhook is notified of the events of its types, on every canvas. Its
+	// secret is only shown when it is created.
+	Webhook struct {
+		ID        string     `json:"id" db:"id"`
+		OwnerID   string     `json:"owner_id" db:"owner_id"`
+		URL       string     `json:"url" db:"url"`
+		Events    EventTypes `json:"events" db:"events"`
+		Secret    string     `json:"secret,omitempty" db:"secret"`
+		CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	}
+
+	DeliveryStatus string
+
+	// Delivery is an event sent, or still to be sent, to a webhook. Pending
+	// deliveries are tried again at NextAttemptAt.
+	Delivery struct {
+		ID             string          `json:"id" db:"id"`
+		WebhookID      string          `json:"webhook_id" db:"webhook_id"`
+		EventID        string          `json:"event_id" db:"event_id"`
+		EventType      EventType       `json:"event_type" db:"event_type"`
+		Payload        json.RawMessage `json:"-" db:"payload"`
+		Status         DeliveryStatus  `json:"status" db:"status"`
+		Attempts       int             `json:"attempts" db:"attempts"`
+		ResponseStatus int             `json:"response_status,omitempty" db:"response_status"`
+		Error          string          `json:"error,omitempty" db:"error"`
+		NextAttemptAt  time.Time       `json:"next_attempt_at" db:"next_attempt_at"`
+		CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+		UpdatedAt      time.Time       `json:"updated_at" db:"updated_at"`
+	}
+
+	// PendingDelivery is a delivery with where and how to send it.
+	PendingDelivery struct {
+		Delivery
+		URL    string `db:"url"`
+		Secret string `db:"secret"`
+	}
+)
+
+func (r WebhookRequest) Validate() error {
+	target, err := url.Parse(r.URL)
+	if err != nil || len(r.URL) > maxWebhookURLLength || target.Host == "" ||
+		(target.Scheme != "http" && target.Scheme != "https") {
+		return ErrInvalidWebhookURL
+	}
+
+	// The addresses the host resolves to are checked again when sending, as
+	// they may change.
+	host := target.Hostname()
+	if ip := net.ParseIP(host); strings.EqualFold(host, "localhost") || (ip != nil && !isPublicIP(ip)) {
+		return ErrForbiddenWebhookAddress
+	}
+
+	if len(r.Events) == 0 {
+		return ErrInvalidWebhookEvents
+	}
+	for _, eventType := range r.Events {
+		switch eventType {
+		case EventCreated, EventUpdated, EventDeleted:
+		default:
+			return ErrInvalidWebhookEvents
+		}
+	}
+
+	if r.Secret != "" && len(r.Secret) < minSecretLength {
+		return ErrInvalidWebhookSecret
+	}
+	return nil
+}
+
+// SignWebhook returns the signature of a delivery sent at the timestamp, in
+// unix seconds, as found in the SignatureHeader.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (e EventTypes) Value() (driver.Value, error) {
+	return json.Marshal(e)
+}
+
+func (e *EventTypes) Scan(src any) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, e)
+	case string:
+		return json.Unmarshal([]byte(value), e)
+	case nil:
+		*e = nil
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into event types", src)
+}
@@ -0,0 +1,83 @@
+package canvas
+
+import (
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"sketch/internal/routing"
+)
+
+type WebhookHandler struct {
+	service WebhookService
+}
+
+func NewWebhookHandler(service WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+	}
+}
+
+// Save subscribes a url to the events of every canvas. The response is the
+// only one showing the secret.
+func (c *WebhookHandler) Save(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+	request, err := routing.FromJSON[WebhookRequest](r)
+	if err != nil {
+		return fmt.Errorf("failed to get json body: %w", err)
+	}
+
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	webhook, err := c.service.Save(r.Context(), request)
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, webhook)
+}
+
+func (c *WebhookHandler) GetByID(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	webhook, err := c.service.GetByID(r.Context(), params.ByName("id"))
+
+	if errors.Is(err, ErrWebhookNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, webhook)
+}
+
+func (c *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	err := c.service.Delete(r.Context(), params.ByName("id"))
+
+	if errors.Is(err, ErrWebhookNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// Deliveries lists the latest deliveries of the webhook, newest first.
+func (c *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	deliveries, err := c.service.GetDeliveries(r.Context(), params.ByName("id"))
+
+	if errors.Is(err, ErrWebhookNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, deliveries)
+}
@@ -0,0 +1,83 @@
+package canvas_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookHandler_Save(t *testing.T) {
+	t.Run("when the request is valid, should return the webhook with its secret", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockWebhookService(ctrl)
+		handler := canvas.NewWebhookHandler(serviceMock)
+		body := `{"url":"https://ci.example.com","events":["canvas.created"]}`
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+
+		serviceMock.EXPECT().Save(gomock.Any(), canvas.WebhookRequest{
+			URL:    "https://ci.example.com",
+			Events: canvas.EventTypes{canvas.EventCreated},
+		}).Return(&canvas.Webhook{ID: "123", Secret: "0123456789abcdef"}, nil)
+
+		err := handler.Save(w, req, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"secret":"0123456789abcdef"`)
+	})
+
+	t.Run("when the request is invalid, should return a validation error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockWebhookService(ctrl)
+		handler := canvas.NewWebhookHandler(serviceMock)
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(`{"url":"ci"}`))
+
+		err := handler.Save(w, req, nil)
+
+		assert.ErrorIs(t, err, canvas.ErrInvalidWebhookURL)
+	})
+}
+
+func TestWebhookHandler_Deliveries(t *testing.T) {
+	t.Run("when the webhook exists, should list its deliveries", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockWebhookService(ctrl)
+		handler := canvas.NewWebhookHandler(serviceMock)
+		req := httptest.NewRequest(http.MethodGet, "/webhooks/123/deliveries", nil)
+
+		serviceMock.EXPECT().GetDeliveries(gomock.Any(), "123").Return([]canvas.Delivery{
+			{ID: "1", Status: canvas.DeliveryFailed, Attempts: 8, ResponseStatus: 500, Payload: []byte(`{}`)},
+		}, nil)
+
+		err := handler.Deliveries(w, req, httprouter.Params{{Key: "id", Value: "123"}})
+
+		assert.NoError(t, err)
+		assert.Contains(t, w.Body.String(), `"status":"failed"`)
+		assert.NotContains(t, w.Body.String(), "payload")
+	})
+
+	t.Run("when the webhook does not exist, should return a 404", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_canvas.NewMockWebhookService(ctrl)
+		handler := canvas.NewWebhookHandler(serviceMock)
+		req := httptest.NewRequest(http.MethodGet, "/webhooks/123/deliveries", nil)
+
+		serviceMock.EXPECT().GetDeliveries(gomock.Any(), "123").Return(nil, canvas.ErrWebhookNotFound)
+
+		err := handler.Deliveries(w, req, httprouter.Params{{Key: "id", Value: "123"}})
+
+		assert.ErrorIs(t, err, canvas.ErrWebhookNotFound)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
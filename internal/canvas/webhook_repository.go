@@ -0,0 +1,152 @@
+package canvas
+
+import (
+	"context"
+	"database/sql"
+	goerrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// maxDeliveries is how many deliveries of a webhook are listed, newest
+	// first.
+	maxDeliveries = 100
+)
+
+type (
+	WebhookRepository interface {
+		Save(ctx context.Context, webhook Webhook) error
+		GetByID(ctx context.Context, id string) (Webhook, error)
+		Delete(ctx context.Context, id string) error
+		// GetByEvent returns the webhooks of the owner listening to the event type.
+		GetByEvent(ctx context.Context, eventType EventType, ownerID string) ([]Webhook, error)
+		SaveDeliveries(ctx context.Context, deliveries []Delivery) error
+		ClaimDeliveries(ctx context.Context, now time.Time, until time.Time, limit int) ([]PendingDelivery, error)
+		UpdateDelivery(ctx context.Context, delivery Delivery) error
+		GetDeliveries(ctx context.Context, webhookID string) ([]Delivery, error)
+	}
+
+	webhookRepository struct {
+		db *sqlx.DB
+	}
+)
+
+func NewWebhookRepository(db *sqlx.DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (r *webhookRepository) Save(ctx context.Context, webhook Webhook) error {
+	const query = "insert into webhooks (id, owner_id, url, events, secret, created_at) " +
+		"values (:id, :owner_id, :url, :events, :secret, :created_at)"
+	if _, err := r.db.NamedExecContext(ctx, query, webhook); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, id string) (Webhook, error) {
+	const query = "select id, owner_id, url, events, secret, created_at from webhooks where id = $1"
+	var webhook Webhook
+	if err := r.db.GetContext(ctx, &webhook, query, id); err != nil {
+		if goerrors.Is(err, sql.ErrNoRows) {
+			return webhook, ErrWebhookNotFound
+		}
+
+		return webhook, fmt.Errorf("database err: %w", err)
+	}
+	return webhook, nil
+}
+
+// Delete removes the webhook, its deliveries go with it.
+func (r *webhookRepository) Delete(ctx context.Context, id string) error {
+	const query = "delete from webhooks where id = $1"
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepository) GetByEvent(ctx context.Context, eventType EventType, ownerID string) ([]Webhook, error) {
+	const query = "select id, owner_id, url, events, secret, created_at from webhooks " +
+		"where owner_id = $2 and events @> jsonb_build_array($1::text)"
+	var webhooks []Webhook
+	if err := r.db.SelectContext(ctx, &webhooks, query, eventType, ownerID); err != nil {
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+	return webhooks, nil
+}
+
+// SaveDeliveries queues the deliveries. An event is queued once per webhook,
+// saving it again does nothing.
+func (r *webhookRepository) SaveDeliveries(ctx context.Context, deliveries []Delivery) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	defer tx.Rollback()
+
+	const query = "insert into webhook_deliveries " +
+		"(id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at, updated_at) " +
+		"values (:id, :webhook_id, :event_id, :event_type, :payload, :status, :attempts, :next_attempt_at, :created_at, :updated_at) " +
+		"on conflict (webhook_id, event_id) do nothing"
+	for _, delivery := range deliveries {
+		if _, err := tx.NamedExecContext(ctx, query, delivery); err != nil {
+			return fmt.Errorf("database err: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+// ClaimDeliveries returns the pending deliveries due at now, oldest first, and
+// holds them until the given time, so that other workers skip them while they
+// are being sent. A worker that stops before updating them releases them then.
+func (r *webhookRepository) ClaimDeliveries(ctx context.Context, now time.Time, until time.Time, limit int) ([]PendingDelivery, error) {
+	const query = "with due as (" +
+		"select id from webhook_deliveries where status = 'pending' and next_attempt_at <= $1 " +
+		"order by next_attempt_at limit $3 for update skip locked" +
+		"), claimed as (" +
+		"update webhook_deliveries d set next_attempt_at = $2 from due where d.id = due.id returning d.*" +
+		") select c.id, c.webhook_id, c.event_id, c.event_type, c.payload, c.status, c.attempts, " +
+		"c.response_status, c.error, c.next_attempt_at, c.created_at, c.updated_at, w.url, w.secret " +
+		"from claimed c join webhooks w on w.id = c.webhook_id order by c.created_at"
+	var deliveries []PendingDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, now, until, limit); err != nil {
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery Delivery) error {
+	const query = "update webhook_deliveries set status = :status, attempts = :attempts, " +
+		"response_status = :response_status, error = :error, next_attempt_at = :next_attempt_at, " +
+		"updated_at = :updated_at where id = :id"
+	if _, err := r.db.NamedExecContext(ctx, query, delivery); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+func (r *webhookRepository) GetDeliveries(ctx context.Context, webhookID string) ([]Delivery, error) {
+	const query = "select id, webhook_id, event_id, event_type, payload, status, attempts, response_status, error, " +
+		"next_attempt_at, created_at, updated_at from webhook_deliveries where webhook_id = $1 " +
+		"order by created_at desc limit $2"
+	deliveries := make([]Delivery, 0)
+	if err := r.db.SelectContext(ctx, &deliveries, query, webhookID, maxDeliveries); err != nil {
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,82 @@
+package canvas_test
+
+import (
+	"context"
+	"database/sql"
+	"sketch/internal/canvas"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookRepository_GetByID(t *testing.T) {
+	const query = "select id, owner_id, url, events, secret, created_at from webhooks where id = $1"
+	setup := func() (canvas.WebhookRepository, sqlmock.Sqlmock) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		return canvas.NewWebhookRepository(sqlx.NewDb(mockDB, "sqlmock")), mock
+	}
+
+	t.Run("when there is a result, should return it", func(t *testing.T) {
+		repository, mock := setup()
+		createdAt := time.Now().UTC()
+		rows := sqlmock.NewRows([]string{"id", "url", "events", "secret", "created_at"}).
+			AddRow("123", "https://ci.example.com", []byte(`["canvas.created","canvas.deleted"]`), "0123456789abcdef", createdAt)
+		mock.ExpectQuery(query).WithArgs("123").WillReturnRows(rows)
+
+		webhook, err := repository.GetByID(context.Background(), "123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, canvas.Webhook{
+			ID:        "123",
+			URL:       "https://ci.example.com",
+			Events:    canvas.EventTypes{canvas.EventCreated, canvas.EventDeleted},
+			Secret:    "0123456789abcdef",
+			CreatedAt: createdAt,
+		}, webhook)
+	})
+
+	t.Run("when there are no results, should return not found error", func(t *testing.T) {
+		repository, mock := setup()
+		mock.ExpectQuery(query).WithArgs("123").WillReturnError(sql.ErrNoRows)
+
+		_, err := repository.GetByID(context.Background(), "123")
+
+		assert.ErrorIs(t, err, canvas.ErrWebhookNotFound)
+	})
+}
+
+func TestWebhookRepository_SaveDeliveries(t *testing.T) {
+	const query = "insert into webhook_deliveries " +
+		"(id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at, updated_at) " +
+		"values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) " +
+		"on conflict (webhook_id, event_id) do nothing"
+
+	t.Run("should save every delivery in a single transaction", func(t *testing.T) {
+		mockDB, mock, _ := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		repository := canvas.NewWebhookRepository(sqlx.NewDb(mockDB, "sqlmock"))
+		now := time.Now().UTC()
+		deliveries := []canvas.Delivery{
+			{ID: "1", WebhookID: "a", EventID: "e", EventType: canvas.EventCreated, Payload: []byte(`{}`),
+				Status: canvas.DeliveryPending, NextAttemptAt: now, CreatedAt: now, UpdatedAt: now},
+			{ID: "2", WebhookID: "b", EventID: "e", EventType: canvas.EventCreated, Payload: []byte(`{}`),
+				Status: canvas.DeliveryPending, NextAttemptAt: now, CreatedAt: now, UpdatedAt: now},
+		}
+
+		mock.ExpectBegin()
+		for _, delivery := range deliveries {
+			mock.ExpectExec(query).
+				WithArgs(delivery.ID, delivery.WebhookID, "e", canvas.EventCreated, []byte(`{}`),
+					canvas.DeliveryPending, 0, now, now, now).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		mock.ExpectCommit()
+
+		err := repository.SaveDeliveries(context.Background(), deliveries)
+
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,157 @@
+package canvas
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sketch/internal/auth"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	// secretSize is the number of random bytes of a generated secret.
+	secretSize = 24
+)
+
+type (
+	// WebhookService manages the webhooks and, as a Publisher or a Sink,
+	// queues a delivery of every event for each webhook of the owner of the
+	// canvas listening to its type. The WebhookWorker sends them. Webhooks are
+	// only seen by their owner.
+	WebhookService interface {
+		Publisher
+		Sink
+		Save(ctx context.Context, request WebhookRequest) (*Webhook, error)
+		GetByID(ctx context.Context, id string) (*Webhook, error)
+		Delete(ctx context.Context, id string) error
+		GetDeliveries(ctx context.Context, id string) ([]Delivery, error)
+	}
+
+	webhookService struct {
+		repository WebhookRepository
+	}
+)
+
+func NewWebhookService(repository WebhookRepository) WebhookService {
+	return &webhookService{
+		repository: repository,
+	}
+}
+
+func (s webhookService) Save(ctx context.Context, request WebhookRequest) (*Webhook, error) {
+	secret := request.Secret
+	if secret == "" {
+		generated := make([]byte, secretSize)
+		if _, err := rand.Read(generated); err != nil {
+			return nil, fmt.Errorf("failed to generate secret: %w", err)
+		}
+		secret = hex.EncodeToString(generated)
+	}
+
+	webhook := Webhook{
+		ID:        uuid.New().String(),
+		OwnerID:   auth.OwnerID(ctx),
+		URL:       request.URL,
+		Events:    request.Events,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.repository.Save(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("error saving webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+func (s webhookService) GetByID(ctx context.Context, id string) (*Webhook, error) {
+	webhook, err := s.getOwned(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+	return &webhook, nil
+}
+
+func (s webhookService) Delete(ctx context.Context, id string) error {
+	if _, err := s.getOwned(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.repository.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete webhook '%s': %w", id, err)
+	}
+	return nil
+}
+
+func (s webhookService) GetDeliveries(ctx context.Context, id string) ([]Delivery, error) {
+	if _, err := s.getOwned(ctx, id); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repository.GetDeliveries(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deliveries of '%s': %w", id, err)
+	}
+	return deliveries, nil
+}
+
+// getOwned returns the webhook when it belongs to the owner of the request,
+// as if it did not exist otherwise.
+func (s webhookService) getOwned(ctx context.Context, id string) (Webhook, error) {
+	webhook, err := s.repository.GetByID(ctx, id)
+	if err == nil && webhook.OwnerID != auth.OwnerID(ctx) {
+		err = ErrWebhookNotFound
+	}
+
+	if err != nil {
+		return webhook, fmt.Errorf("failed to get webhook '%s': %w", id, err)
+	}
+	return webhook, nil
+}
+
+// Publish queues the event for its webhooks. The change is already saved, so
+// failing to queue it is only logged.
+func (s webhookService) Publish(ctx context.Context, event Event) {
+	if err := s.Send(ctx, event); err != nil {
+		log.Errorf("failed to queue webhook deliveries of event '%s': %v", event.ID, err)
+	}
+}
+
+// Send queues the event for its webhooks. An event is queued once per webhook,
+// however many times it is sent.
+func (s webhookService) Send(ctx context.Context, event Event) error {
+	webhooks, err := s.repository.GetByEvent(ctx, event.Type, event.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	deliveries := make([]Delivery, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		deliveries = append(deliveries, Delivery{
+			ID:            uuid.New().String(),
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+	}
+	return s.repository.SaveDeliveries(ctx, deliveries)
+}
@@ -0,0 +1,137 @@
+package canvas_test
+
+import (
+	"context"
+	"encoding/json"
+	"sketch/internal/auth"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"sketch/tests/faker"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookService_Save(t *testing.T) {
+	t.Run("when there is no secret, should generate one", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockWebhookRepository(ctrl)
+		service := canvas.NewWebhookService(repositoryMock)
+		ctx := context.Background()
+		request := canvas.WebhookRequest{URL: "https://ci.example.com", Events: canvas.EventTypes{canvas.EventCreated}}
+
+		repositoryMock.EXPECT().Save(ctx, gomock.Any()).Return(nil)
+
+		webhook, err := service.Save(ctx, request)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, webhook.ID)
+		assert.Len(t, webhook.Secret, 48)
+		assert.Equal(t, request.URL, webhook.URL)
+	})
+
+	t.Run("when there is an error saving, should return it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockWebhookRepository(ctrl)
+		service := canvas.NewWebhookService(repositoryMock)
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().Save(ctx, gomock.Any()).Return(faker.NewError())
+
+		webhook, err := service.Save(ctx, canvas.WebhookRequest{Secret: "0123456789abcdef"})
+
+		assert.ErrorIs(t, err, faker.NewError())
+		assert.Nil(t, webhook)
+	})
+}
+
+func TestWebhookService_GetByID(t *testing.T) {
+	t.Run("should hide the secret", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockWebhookRepository(ctrl)
+		service := canvas.NewWebhookService(repositoryMock)
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().GetByID(ctx, "123").Return(canvas.Webhook{ID: "123", Secret: "0123456789abcdef"}, nil)
+
+		webhook, err := service.GetByID(ctx, "123")
+
+		assert.NoError(t, err)
+		assert.Empty(t, webhook.Secret)
+	})
+}
+
+func TestWebhookService_Delete(t *testing.T) {
+	t.Run("when the webhook belongs to another owner, should return not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockWebhookRepository(ctrl)
+		service := canvas.NewWebhookService(repositoryMock)
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+
+		repositoryMock.EXPECT().GetByID(ctx, "123").Return(canvas.Webhook{ID: "123", OwnerID: "other"}, nil)
+		repositoryMock.EXPECT().Delete(gomock.Any(), gomock.Any()).Times(0)
+
+		err := service.Delete(ctx, "123")
+
+		assert.ErrorIs(t, err, canvas.ErrWebhookNotFound)
+	})
+}
+
+func TestWebhookService_GetDeliveries(t *testing.T) {
+	t.Run("when the webhook does not exist, should return not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockWebhookRepository(ctrl)
+		service := canvas.NewWebhookService(repositoryMock)
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().GetByID(ctx, "123").Return(canvas.Webhook{}, canvas.ErrWebhookNotFound)
+
+		deliveries, err := service.GetDeliveries(ctx, "123")
+
+		assert.ErrorIs(t, err, canvas.ErrWebhookNotFound)
+		assert.Nil(t, deliveries)
+	})
+}
+
+func TestWebhookService_Publish(t *testing.T) {
+	t.Run("should queue a delivery of the event for every webhook of its type and owner", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockWebhookRepository(ctrl)
+		service := canvas.NewWebhookService(repositoryMock)
+		ctx := context.Background()
+		fakeCanvas := faker.NewCanvas(t)
+		fakeCanvas.OwnerID = "owner"
+		event := canvas.NewEvent(canvas.EventUpdated, fakeCanvas)
+
+		repositoryMock.EXPECT().GetByEvent(ctx, canvas.EventUpdated, "owner").
+			Return([]canvas.Webhook{{ID: "1"}, {ID: "2"}}, nil)
+		repositoryMock.EXPECT().SaveDeliveries(ctx, gomock.Any()).
+			Do(func(_ context.Context, deliveries []canvas.Delivery) {
+				assert.Len(t, deliveries, 2)
+				for i, delivery := range deliveries {
+					var payload canvas.Event
+					assert.NoError(t, json.Unmarshal(delivery.Payload, &payload))
+					assert.Equal(t, event.ID, payload.ID)
+					assert.Equal(t, event.ID, delivery.EventID)
+					assert.Equal(t, canvas.DeliveryPending, delivery.Status)
+					assert.Equal(t, []string{"1", "2"}[i], delivery.WebhookID)
+				}
+			}).
+			Return(nil)
+
+		service.Publish(ctx, event)
+	})
+
+	t.Run("when no webhook listens to the event, should queue nothing", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockWebhookRepository(ctrl)
+		service := canvas.NewWebhookService(repositoryMock)
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().GetByEvent(ctx, canvas.EventDeleted, "").Return(nil, nil)
+		repositoryMock.EXPECT().SaveDeliveries(gomock.Any(), gomock.Any()).Times(0)
+
+		service.Publish(ctx, canvas.NewEvent(canvas.EventDeleted, canvas.Canvas{ID: "123"}))
+	})
+}
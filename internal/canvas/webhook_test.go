@@ -0,0 +1,87 @@
+package canvas_test
+
+import (
+	"sketch/internal/canvas"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request canvas.WebhookRequest
+		wantErr error
+	}{
+		{
+			name:    "when the request is valid, should return no error",
+			request: canvas.WebhookRequest{URL: "https://ci.example.com/hooks", Events: canvas.EventTypes{canvas.EventCreated}},
+		},
+		{
+			name:    "when the url is relative, should return an error",
+			request: canvas.WebhookRequest{URL: "/hooks", Events: canvas.EventTypes{canvas.EventCreated}},
+			wantErr: canvas.ErrInvalidWebhookURL,
+		},
+		{
+			name:    "when the url is not http, should return an error",
+			request: canvas.WebhookRequest{URL: "ftp://ci.example.com", Events: canvas.EventTypes{canvas.EventCreated}},
+			wantErr: canvas.ErrInvalidWebhookURL,
+		},
+		{
+			name:    "when the url points to the metadata address, should return an error",
+			request: canvas.WebhookRequest{URL: "http://169.254.169.254/latest", Events: canvas.EventTypes{canvas.EventCreated}},
+			wantErr: canvas.ErrForbiddenWebhookAddress,
+		},
+		{
+			name:    "when the url points to a private address, should return an error",
+			request: canvas.WebhookRequest{URL: "http://10.0.0.1:8080", Events: canvas.EventTypes{canvas.EventCreated}},
+			wantErr: canvas.ErrForbiddenWebhookAddress,
+		},
+		{
+			name:    "when the url points to localhost, should return an error",
+			request: canvas.WebhookRequest{URL: "http://localhost:8080", Events: canvas.EventTypes{canvas.EventCreated}},
+			wantErr: canvas.ErrForbiddenWebhookAddress,
+		},
+		{
+			name:    "when there are no events, should return an error",
+			request: canvas.WebhookRequest{URL: "https://ci.example.com"},
+			wantErr: canvas.ErrInvalidWebhookEvents,
+		},
+		{
+			name:    "when an event is not a lifecycle event, should return an error",
+			request: canvas.WebhookRequest{URL: "https://ci.example.com", Events: canvas.EventTypes{canvas.EventSnapshot}},
+			wantErr: canvas.ErrInvalidWebhookEvents,
+		},
+		{
+			name: "when the secret is too short, should return an error",
+			request: canvas.WebhookRequest{
+				URL:    "https://ci.example.com",
+				Events: canvas.EventTypes{canvas.EventDeleted},
+				Secret: "short",
+			},
+			wantErr: canvas.ErrInvalidWebhookSecret,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.request.Validate(), tt.wantErr)
+		})
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	t.Run("should sign the timestamp and the body with the secret", func(t *testing.T) {
+		signature := canvas.SignWebhook("0123456789abcdef", 1700000000, []byte(`{"id":"1"}`))
+
+		assert.Equal(t, "sha256=d5f5834972cbc6cf5590800c46ccaa0cd6c16f19c0c73dbdf9b4c56390cbc2a3", signature)
+		assert.NotEqual(t, signature, canvas.SignWebhook("0123456789abcdef", 1700000001, []byte(`{"id":"1"}`)))
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 10*time.Second, canvas.RetryDelay(1))
+	assert.Equal(t, 20*time.Second, canvas.RetryDelay(2))
+	assert.Equal(t, 80*time.Second, canvas.RetryDelay(4))
+	assert.Equal(t, time.Hour, canvas.RetryDelay(20))
+}
@@ -0,0 +1,192 @@
+package canvas
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/labstack/gommon/log"
+)
+
+const (
+	// MaxDeliveryAttempts is how many times a delivery is sent before it is
+	// given up as failed.
+	MaxDeliveryAttempts = 8
+
+	// The n-th retry waits baseRetryDelay * 2^(n-1), at most maxRetryDelay.
+	baseRetryDelay = 10 * time.Second
+	maxRetryDelay  = time.Hour
+
+	pollInterval = time.Second
+	// claimSize deliveries are sent one after the other, each within
+	// deliveryTimeout, so they must all fit in claimDuration.
+	claimSize       = 10
+	claimDuration   = 5 * time.Minute
+	deliveryTimeout = 10 * time.Second
+	// maxErrorLength trims the error kept in the delivery log.
+	maxErrorLength = 512
+)
+
+// WebhookWorker sends the queued deliveries, retrying those that fail with an
+// exponential backoff. Many workers may share the same database.
+type WebhookWorker struct {
+	repository WebhookRepository
+	client     *http.Client
+}
+
+// NewWebhookWorker sends the deliveries with the client, NewWebhookClient
+// outside of tests.
+func NewWebhookWorker(repository WebhookRepository, client *http.Client) *WebhookWorker {
+	return &WebhookWorker{
+		repository: repository,
+		client:     client,
+	}
+}
+
+// NewWebhookClient returns a client that only connects to public addresses,
+// checked once the host is resolved, and does not follow redirects, so that
+// webhooks cannot reach the network of the api.
+func NewWebhookClient() *http.Client {
+	dialer := &net.Dialer{Timeout: deliveryTimeout, Control: dialPublic}
+	return &http.Client{
+		Timeout: deliveryTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: deliveryTimeout,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// dialPublic refuses the connections to addresses that are not public.
+func dialPublic(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+		return ErrForbiddenWebhookAddress
+	}
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsUnspecified() && !ip.IsMulticast()
+}
+
+// Run sends the deliveries as they become due, until the context is done.
+func (w *WebhookWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A full batch means more may be waiting.
+			for {
+				sent, err := w.Deliver(ctx)
+				if err != nil {
+					log.Errorf("failed to send webhook deliveries: %v", err)
+				}
+				if err != nil || sent < claimSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Deliver sends a batch of the due deliveries and returns how many it sent.
+func (w *WebhookWorker) Deliver(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	deliveries, err := w.repository.ClaimDeliveries(ctx, now, now.Add(claimDuration), claimSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, pending := range deliveries {
+		delivery := w.send(ctx, pending)
+		if err := w.repository.UpdateDelivery(ctx, delivery); err != nil {
+			return 0, err
+		}
+	}
+	return len(deliveries), nil
+}
+
+func (w *WebhookWorker) send(ctx context.Context, pending PendingDelivery) Delivery {
+	delivery := pending.Delivery
+	delivery.Attempts++
+	status, err := w.post(ctx, pending)
+
+	now := time.Now().UTC()
+	delivery.UpdatedAt = now
+	delivery.ResponseStatus = status
+	delivery.Error = ""
+	switch {
+	case err == nil:
+		delivery.Status = DeliverySucceeded
+		return delivery
+	case delivery.Attempts >= MaxDeliveryAttempts:
+		delivery.Status = DeliveryFailed
+	default:
+		delivery.NextAttemptAt = now.Add(RetryDelay(delivery.Attempts))
+	}
+
+	delivery.Error = err.Error()
+	if len(delivery.Error) > maxErrorLength {
+		delivery.Error = delivery.Error[:maxErrorLength]
+	}
+	return delivery
+}
+
+// post sends the payload and returns the status of the response, if any.
+func (w *WebhookWorker) post(ctx context.Context, pending PendingDelivery) (int, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, pending.URL, bytes.NewReader(pending.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "sketch-webhooks")
+	request.Header.Set(EventHeader, string(pending.EventType))
+	request.Header.Set(DeliveryHeader, pending.ID)
+	request.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(SignatureHeader, SignWebhook(pending.Secret, timestamp, pending.Payload))
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("unexpected status %d", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+// RetryDelay is how long a delivery waits after failing the given number of
+// attempts.
+func RetryDelay(attempts int) time.Duration {
+	delay := baseRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
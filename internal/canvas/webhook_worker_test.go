@@ -0,0 +1,138 @@
+package canvas_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sketch/internal/canvas"
+	mock_canvas "sketch/internal/canvas/mocks"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookWorker_Deliver(t *testing.T) {
+	const secret = "0123456789abcdef"
+	payload := []byte(`{"id":"event"}`)
+	pending := func(url string, attempts int) canvas.PendingDelivery {
+		return canvas.PendingDelivery{
+			Delivery: canvas.Delivery{
+				ID:        "delivery",
+				EventType: canvas.EventCreated,
+				Payload:   payload,
+				Status:    canvas.DeliveryPending,
+				Attempts:  attempts,
+			},
+			URL:    url,
+			Secret: secret,
+		}
+	}
+
+	t.Run("when the webhook answers 2xx, should send a signed payload and mark it as succeeded", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			timestamp, _ := strconv.ParseInt(r.Header.Get(canvas.TimestampHeader), 10, 64)
+			assert.Equal(t, payload, body)
+			assert.Equal(t, string(canvas.EventCreated), r.Header.Get(canvas.EventHeader))
+			assert.Equal(t, "delivery", r.Header.Get(canvas.DeliveryHeader))
+			assert.Equal(t, canvas.SignWebhook(secret, timestamp, body), r.Header.Get(canvas.SignatureHeader))
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockWebhookRepository(ctrl)
+		worker := canvas.NewWebhookWorker(repositoryMock, server.Client())
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().ClaimDeliveries(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]canvas.PendingDelivery{pending(server.URL, 0)}, nil)
+		repositoryMock.EXPECT().UpdateDelivery(ctx, gomock.Any()).
+			Do(func(_ context.Context, delivery canvas.Delivery) {
+				assert.Equal(t, canvas.DeliverySucceeded, delivery.Status)
+				assert.Equal(t, 1, delivery.Attempts)
+				assert.Equal(t, http.StatusAccepted, delivery.ResponseStatus)
+			}).
+			Return(nil)
+
+		sent, err := worker.Deliver(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+	})
+
+	t.Run("when the webhook fails, should retry later with backoff", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockWebhookRepository(ctrl)
+		worker := canvas.NewWebhookWorker(repositoryMock, server.Client())
+		ctx := context.Background()
+		before := time.Now().UTC()
+
+		repositoryMock.EXPECT().ClaimDeliveries(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]canvas.PendingDelivery{pending(server.URL, 2)}, nil)
+		repositoryMock.EXPECT().UpdateDelivery(ctx, gomock.Any()).
+			Do(func(_ context.Context, delivery canvas.Delivery) {
+				assert.Equal(t, canvas.DeliveryPending, delivery.Status)
+				assert.Equal(t, 3, delivery.Attempts)
+				assert.Equal(t, http.StatusInternalServerError, delivery.ResponseStatus)
+				assert.Equal(t, "unexpected status 500", delivery.Error)
+				assert.False(t, delivery.NextAttemptAt.Before(before.Add(canvas.RetryDelay(3))))
+			}).
+			Return(nil)
+
+		_, err := worker.Deliver(ctx)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("when the last attempt fails, should mark it as failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_canvas.NewMockWebhookRepository(ctrl)
+		worker := canvas.NewWebhookWorker(repositoryMock, http.DefaultClient)
+		ctx := context.Background()
+
+		repositoryMock.EXPECT().ClaimDeliveries(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]canvas.PendingDelivery{pending("http://127.0.0.1:1", canvas.MaxDeliveryAttempts-1)}, nil)
+		repositoryMock.EXPECT().UpdateDelivery(ctx, gomock.Any()).
+			Do(func(_ context.Context, delivery canvas.Delivery) {
+				assert.Equal(t, canvas.DeliveryFailed, delivery.Status)
+				assert.Equal(t, canvas.MaxDeliveryAttempts, delivery.Attempts)
+				assert.NotEmpty(t, delivery.Error)
+			}).
+			Return(nil)
+
+		_, err := worker.Deliver(ctx)
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestNewWebhookClient(t *testing.T) {
+	t.Run("when the host resolves to a loopback address, should not connect to it", func(t *testing.T) {
+		called := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer server.Close()
+
+		_, err := canvas.NewWebhookClient().Post(server.URL, "application/json", nil)
+
+		assert.ErrorIs(t, err, canvas.ErrForbiddenWebhookAddress)
+		assert.False(t, called)
+	})
+
+	t.Run("when the webhook redirects, should not follow it", func(t *testing.T) {
+		client := canvas.NewWebhookClient()
+
+		assert.ErrorIs(t, client.CheckRedirect(nil, nil), http.ErrUseLastResponse)
+	})
+}
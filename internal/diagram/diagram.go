@@ -0,0 +1,183 @@
+package diagram
+
+import (
+	"fmt"
+	"regexp"
+	"sketch/internal/errors"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// margin is the room left around the nodes for the connectors.
+	margin = 2
+	// maxArea caps the cells of the grid a diagram is drawn on, which is
+	// allocated whole and searched for every edge.
+	maxArea = 250_000
+)
+
+var (
+	ErrEmptyDiagram     = errors.Error("diagram must have at least one node")
+	ErrInvalidNodeID    = errors.Error("node ids may only have letters, digits, '_' and '-'")
+	ErrDuplicateNode    = errors.Error("node ids must be unique")
+	ErrUnknownNode      = errors.Error("edge references an unknown node")
+	ErrInvalidNodeBox   = errors.Error("node coordinates must be equal or greater than zero and its box must fit its label")
+	ErrOverlappingNodes = errors.Error("nodes must not overlap")
+	ErrNoRoute          = errors.Error("there is no room to route the edge")
+	ErrDiagramTooLarge  = errors.Error(fmt.Sprintf("the diagram must fit in %d cells", maxArea))
+
+	idPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+type (
+	// Node is a labeled box. Width and height are computed from the label
+	// when they are not informed.
+	Node struct {
+		ID     string `json:"id"`
+		Label  string `json:"label,omitempty"`
+		X      int    `json:"x"`
+		Y      int    `json:"y"`
+		Width  int    `json:"width,omitempty"`
+		Height int    `json:"height,omitempty"`
+	}
+
+	// Edge is a connector from a node to another, ending in an arrowhead.
+	Edge struct {
+		From  string `json:"from"`
+		To    string `json:"to"`
+		Label string `json:"label,omitempty"`
+	}
+
+	Diagram struct {
+		Nodes []Node `json:"nodes"`
+		Edges []Edge `json:"edges,omitempty"`
+	}
+)
+
+func (n Node) text() string {
+	if n.Label == "" {
+		return n.ID
+	}
+	return n.Label
+}
+
+// Size returns the informed size of the box, or the smallest one holding the
+// label with a space on each side.
+func (n Node) Size() (int, int) {
+	lines := strings.Split(n.text(), "\n")
+	width, height := n.Width, n.Height
+	if width == 0 {
+		width = longest(lines) + 4
+	}
+	if height == 0 {
+		height = len(lines) + 2
+	}
+	return width, height
+}
+
+func (n Node) box() box {
+	width, height := n.Size()
+	return box{x: n.X, y: n.Y, width: width, height: height}
+}
+
+func (d Diagram) Validate() error {
+	if len(d.Nodes) == 0 {
+		return ErrEmptyDiagram
+	}
+
+	ids := make(map[string]box, len(d.Nodes))
+	right, bottom := 0, 0
+	for _, node := range d.Nodes {
+		if !idPattern.MatchString(node.ID) {
+			return fmt.Errorf("%w: '%s'", ErrInvalidNodeID, node.ID)
+		}
+		if _, ok := ids[node.ID]; ok {
+			return fmt.Errorf("%w: '%s'", ErrDuplicateNode, node.ID)
+		}
+
+		lines := strings.Split(node.text(), "\n")
+		width, height := node.Size()
+		if node.X < 0 || node.Y < 0 || width < longest(lines)+2 || height < len(lines)+2 {
+			return fmt.Errorf("%w: '%s'", ErrInvalidNodeBox, node.ID)
+		}
+
+		// Each side is checked on its own first, so the sums cannot overflow.
+		if node.X > maxArea || node.Y > maxArea || width > maxArea || height > maxArea {
+			return ErrDiagramTooLarge
+		}
+		if node.X+width > right {
+			right = node.X + width
+		}
+		if node.Y+height > bottom {
+			bottom = node.Y + height
+		}
+		if (right+margin)*(bottom+margin) > maxArea {
+			return ErrDiagramTooLarge
+		}
+
+		for id, other := range ids {
+			if other.overlaps(node.box()) {
+				return fmt.Errorf("%w: '%s' and '%s'", ErrOverlappingNodes, id, node.ID)
+			}
+		}
+		ids[node.ID] = node.box()
+	}
+
+	for _, edge := range d.Edges {
+		for _, id := range []string{edge.From, edge.To} {
+			if _, ok := ids[id]; !ok {
+				return fmt.Errorf("%w: '%s'", ErrUnknownNode, id)
+			}
+		}
+	}
+	return nil
+}
+
+// Render draws the boxes, then routes every edge in order around them. Edges
+// cross each other at right angles, drawn as '+', but never share a segment.
+func (d Diagram) Render() (string, error) {
+	boxes := make(map[string]box, len(d.Nodes))
+	width, height := 0, 0
+	for _, node := range d.Nodes {
+		b := node.box()
+		boxes[node.ID] = b
+		if b.x+b.width > width {
+			width = b.x + b.width
+		}
+		if b.y+b.height > height {
+			height = b.y + b.height
+		}
+	}
+
+	g := newGrid(width+margin, height+margin)
+	for _, node := range d.Nodes {
+		g.drawBox(boxes[node.ID], node.text())
+	}
+
+	paths := make([][]step, len(d.Edges))
+	for i, edge := range d.Edges {
+		path, ok := g.route(boxes[edge.From], boxes[edge.To])
+		if !ok {
+			return "", fmt.Errorf("%w from '%s' to '%s'", ErrNoRoute, edge.From, edge.To)
+		}
+		g.drawPath(path)
+		paths[i] = path
+	}
+
+	for i, edge := range d.Edges {
+		if edge.Label != "" {
+			g.drawLabel(paths[i], edge.Label)
+		}
+	}
+	return g.String(), nil
+}
+
+func longest(lines []string) int {
+	length := 0
+	for _, line := range lines {
+		if current := utf8.RuneCountInString(line); current > length {
+			length = current
+		}
+	}
+	return length
+}
@@ -0,0 +1,160 @@
+package diagram_test
+
+import (
+	"sketch/internal/diagram"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagram_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		diagram     diagram.Diagram
+		expectedErr error
+	}{
+		{
+			name:        "when there are no nodes, should return an error",
+			diagram:     diagram.Diagram{},
+			expectedErr: diagram.ErrEmptyDiagram,
+		},
+		{
+			name:        "when a node id has spaces, should return an error",
+			diagram:     diagram.Diagram{Nodes: []diagram.Node{{ID: "my node"}}},
+			expectedErr: diagram.ErrInvalidNodeID,
+		},
+		{
+			name:        "when two nodes have the same id, should return an error",
+			diagram:     diagram.Diagram{Nodes: []diagram.Node{{ID: "a"}, {ID: "a", X: 10}}},
+			expectedErr: diagram.ErrDuplicateNode,
+		},
+		{
+			name:        "when the box is smaller than the label, should return an error",
+			diagram:     diagram.Diagram{Nodes: []diagram.Node{{ID: "a", Label: "long label", Width: 5}}},
+			expectedErr: diagram.ErrInvalidNodeBox,
+		},
+		{
+			name:        "when a node is too far, should return an error",
+			diagram:     diagram.Diagram{Nodes: []diagram.Node{{ID: "a", X: 1_000_000, Y: 1_000_000}}},
+			expectedErr: diagram.ErrDiagramTooLarge,
+		},
+		{
+			name:        "when the nodes spread over too many cells, should return an error",
+			diagram:     diagram.Diagram{Nodes: []diagram.Node{{ID: "a"}, {ID: "b", X: 1000, Y: 1000}}},
+			expectedErr: diagram.ErrDiagramTooLarge,
+		},
+		{
+			name:        "when the coordinates would overflow, should return an error",
+			diagram:     diagram.Diagram{Nodes: []diagram.Node{{ID: "a", X: 9223372036854775807}}},
+			expectedErr: diagram.ErrDiagramTooLarge,
+		},
+		{
+			name:        "when two nodes overlap, should return an error",
+			diagram:     diagram.Diagram{Nodes: []diagram.Node{{ID: "a"}, {ID: "b", X: 2, Y: 1}}},
+			expectedErr: diagram.ErrOverlappingNodes,
+		},
+		{
+			name: "when an edge references an unknown node, should return an error",
+			diagram: diagram.Diagram{
+				Nodes: []diagram.Node{{ID: "a"}},
+				Edges: []diagram.Edge{{From: "a", To: "b"}},
+			},
+			expectedErr: diagram.ErrUnknownNode,
+		},
+		{
+			name: "when the diagram is valid, should return no error",
+			diagram: diagram.Diagram{
+				Nodes: []diagram.Node{{ID: "a"}, {ID: "b", X: 10}},
+				Edges: []diagram.Edge{{From: "a", To: "b"}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorIs(t, tc.diagram.Validate(), tc.expectedErr)
+		})
+	}
+}
+
+func TestDiagram_Render(t *testing.T) {
+	tests := []struct {
+		name     string
+		diagram  diagram.Diagram
+		expected string
+	}{
+		{
+			name: "when the nodes face each other, should draw a straight arrow",
+			diagram: diagram.Diagram{
+				Nodes: []diagram.Node{{ID: "a"}, {ID: "b", X: 10}},
+				Edges: []diagram.Edge{{From: "a", To: "b"}},
+			},
+			expected: `+---+     +---+
+| a |---->| b |
++---+     +---+`,
+		},
+		{
+			name: "when a node is in the way, should route around it and label the edge",
+			diagram: diagram.Diagram{
+				Nodes: []diagram.Node{
+					{ID: "a", Y: 3},
+					{ID: "wall", Label: "#", X: 8, Width: 3, Height: 11},
+					{ID: "b", X: 14, Y: 3},
+				},
+				Edges: []diagram.Edge{{From: "a", To: "b", Label: "go"}},
+			},
+			expected: `        +-+
+        | |
+        | |
++---+   | |   +---+
+| a |   | |   | b |
++---+   |#|   +---+
+   |    | |    ^
+   |    | |    |
+   |    | |    |
+   |    | |    |
+   |    +-+    |
+   +----go-----+`,
+		},
+		{
+			name: "when edges cross, should join them with a plus",
+			diagram: diagram.Diagram{
+				Nodes: []diagram.Node{{ID: "a", Y: 4}, {ID: "b", X: 14, Y: 4}, {ID: "c", X: 7}, {ID: "d", X: 7, Y: 8}},
+				Edges: []diagram.Edge{{From: "a", To: "b"}, {From: "c", To: "d"}},
+			},
+			expected: `       +---+
+       | c |
+       +---+
+         |
++---+    |    +---+
+| a |----+--->| b |
++---+    |    +---+
+         v
+       +---+
+       | d |
+       +---+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.NoError(t, tc.diagram.Validate())
+
+			got, err := tc.diagram.Render()
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+
+	t.Run("when a node is walled in, should return an error", func(t *testing.T) {
+		d := diagram.Diagram{
+			Nodes: []diagram.Node{{ID: "a"}, {ID: "b", X: 5}, {ID: "c", Y: 3, Width: 10}},
+			Edges: []diagram.Edge{{From: "a", To: "b"}},
+		}
+
+		_, err := d.Render()
+
+		assert.ErrorIs(t, err, diagram.ErrNoRoute)
+	})
+}
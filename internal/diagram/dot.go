@@ -0,0 +1,344 @@
+package diagram
+
+import (
+	"fmt"
+	"sketch/internal/errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	DOTContentType = "text/vnd.graphviz"
+)
+
+var (
+	ErrInvalidDOT = errors.Error("invalid dot")
+)
+
+type (
+	dotToken struct {
+		value  string
+		line   int
+		quoted bool
+	}
+
+	dotParser struct {
+		tokens   []dotToken
+		position int
+		graph    *Graph
+		ids      map[string]string
+	}
+
+	// Graph is a diagram without coordinates, read from DOT and placed by
+	// Layout.
+	Graph struct {
+		Diagram
+		// Horizontal places the layers from left to right instead of from top
+		// to bottom, as rankdir=LR does.
+		Horizontal bool
+	}
+)
+
+// ParseDOT reads a subset of the Graphviz language: a single digraph with
+// node statements, edge chains and attribute lists. Only the label attribute
+// and the graph rankdir are used, every other attribute is ignored.
+//
+//	digraph deploy {
+//		rankdir=LR
+//		build [label="Build"]
+//		build -> test -> deploy [label="ok"]
+//	}
+func ParseDOT(source string) (*Graph, error) {
+	tokens, err := tokenizeDOT(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &dotParser{tokens: tokens, graph: &Graph{}, ids: make(map[string]string)}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.graph, nil
+}
+
+func tokenizeDOT(source string) ([]dotToken, error) {
+	runes := []rune(source)
+	tokens := make([]dotToken, 0)
+	line := 1
+
+	for i := 0; i < len(runes); i++ {
+		char := runes[i]
+		switch {
+		case char == '\n':
+			line++
+		case unicode.IsSpace(char):
+		case char == '#', char == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			line++
+		case char == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			end := strings.Index(string(runes[i+2:]), "*/")
+			if end < 0 {
+				return nil, dotError(line, "unterminated comment")
+			}
+			comment := []rune(string(runes[i+2:])[:end])
+			line += strings.Count(string(comment), "\n")
+			i += 2 + len(comment) + 1
+		case char == '-' && i+1 < len(runes) && (runes[i+1] == '>' || runes[i+1] == '-'):
+			tokens = append(tokens, dotToken{value: string(runes[i : i+2]), line: line})
+			i++
+		case strings.ContainsRune("{}[]=;,", char):
+			tokens = append(tokens, dotToken{value: string(char), line: line})
+		case char == '"':
+			value := strings.Builder{}
+			start := line
+			for i++; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					switch runes[i] {
+					case 'n', 'l', 'r':
+						value.WriteRune('\n')
+						continue
+					case '"':
+					default:
+						value.WriteRune('\\')
+					}
+				}
+				if runes[i] == '\n' {
+					line++
+				}
+				value.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, dotError(start, "unterminated string")
+			}
+			tokens = append(tokens, dotToken{value: value.String(), line: start, quoted: true})
+		case isDOTIdentifier(char):
+			start := i
+			for i+1 < len(runes) && isDOTIdentifier(runes[i+1]) {
+				i++
+			}
+			tokens = append(tokens, dotToken{value: string(runes[start : i+1]), line: line})
+		default:
+			return nil, dotError(line, "unexpected character '%c'", char)
+		}
+	}
+	return tokens, nil
+}
+
+func isDOTIdentifier(char rune) bool {
+	return char == '_' || char == '.' || unicode.IsLetter(char) || unicode.IsDigit(char)
+}
+
+func dotError(line int, format string, args ...any) error {
+	return fmt.Errorf("%w: line %d: %s", ErrInvalidDOT, line, fmt.Sprintf(format, args...))
+}
+
+func (p *dotParser) peek() (dotToken, bool) {
+	if p.position >= len(p.tokens) {
+		return dotToken{}, false
+	}
+	return p.tokens[p.position], true
+}
+
+func (p *dotParser) next() (dotToken, error) {
+	token, ok := p.peek()
+	if !ok {
+		last := 1
+		if len(p.tokens) > 0 {
+			last = p.tokens[len(p.tokens)-1].line
+		}
+		return token, dotError(last, "unexpected end of input")
+	}
+	p.position++
+	return token, nil
+}
+
+func (p *dotParser) is(value string) bool {
+	token, ok := p.peek()
+	return ok && !token.quoted && token.value == value
+}
+
+func (p *dotParser) expect(value string) error {
+	token, err := p.next()
+	if err != nil {
+		return err
+	}
+	if token.quoted || token.value != value {
+		return dotError(token.line, "expected '%s', got '%s'", value, token.value)
+	}
+	return nil
+}
+
+func (p *dotParser) parse() error {
+	if p.is("strict") {
+		p.position++
+	}
+
+	token, err := p.next()
+	if err != nil {
+		return err
+	}
+	if strings.ToLower(token.value) != "digraph" {
+		return dotError(token.line, "only digraphs are supported")
+	}
+
+	if !p.is("{") {
+		if _, err := p.next(); err != nil {
+			return err
+		}
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+
+	for !p.is("}") {
+		if err := p.statement(); err != nil {
+			return err
+		}
+	}
+	p.position++
+
+	if token, ok := p.peek(); ok {
+		return dotError(token.line, "unexpected '%s' after the graph", token.value)
+	}
+	return nil
+}
+
+func (p *dotParser) statement() error {
+	if p.is(";") {
+		p.position++
+		return nil
+	}
+
+	first, err := p.next()
+	if err != nil {
+		return err
+	}
+
+	if !first.quoted {
+		switch strings.ToLower(first.value) {
+		case "graph":
+			attributes, err := p.attributes()
+			if err != nil {
+				return err
+			}
+			if rankdir, ok := attributes["rankdir"]; ok {
+				p.rankdir(rankdir)
+			}
+			return nil
+		case "node", "edge":
+			_, err := p.attributes()
+			return err
+		case "subgraph", "{", "}":
+			return dotError(first.line, "subgraphs are not supported")
+		}
+	}
+
+	if p.is("=") {
+		p.position++
+		value, err := p.next()
+		if err != nil {
+			return err
+		}
+		if strings.ToLower(first.value) == "rankdir" {
+			p.rankdir(value.value)
+		}
+		return nil
+	}
+
+	chain := []dotToken{first}
+	for p.is("->") || p.is("--") {
+		p.position++
+		target, err := p.next()
+		if err != nil {
+			return err
+		}
+		chain = append(chain, target)
+	}
+
+	attributes, err := p.attributes()
+	if err != nil {
+		return err
+	}
+
+	if len(chain) == 1 {
+		node := p.node(first)
+		if label, ok := attributes["label"]; ok {
+			node.Label = label
+		}
+		return nil
+	}
+
+	for i := 1; i < len(chain); i++ {
+		from, to := p.node(chain[i-1]), p.node(chain[i])
+		p.graph.Edges = append(p.graph.Edges, Edge{From: from.ID, To: to.ID, Label: attributes["label"]})
+	}
+	return nil
+}
+
+// rankdir places the layers from left to right when the direction is LR or RL,
+// whether set alone or in a graph attribute statement.
+func (p *dotParser) rankdir(direction string) {
+	p.graph.Horizontal = strings.ToUpper(direction) == "LR" || strings.ToUpper(direction) == "RL"
+}
+
+// attributes reads the optional lists of key=value pairs between brackets.
+func (p *dotParser) attributes() (map[string]string, error) {
+	attributes := make(map[string]string)
+	for p.is("[") {
+		p.position++
+		for !p.is("]") {
+			key, err := p.next()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			value, err := p.next()
+			if err != nil {
+				return nil, err
+			}
+			attributes[strings.ToLower(key.value)] = value.value
+
+			if p.is(",") || p.is(";") {
+				p.position++
+			}
+		}
+		p.position++
+	}
+	return attributes, nil
+}
+
+// node returns the node with the DOT id, adding it when it is new. Ids that
+// are not valid node ids are replaced, keeping the original as the label.
+func (p *dotParser) node(token dotToken) *Node {
+	id, ok := p.ids[token.value]
+	if !ok {
+		id = token.value
+		for i := len(p.graph.Nodes) + 1; !idPattern.MatchString(id) || p.used(id); i++ {
+			id = "node" + strconv.Itoa(i)
+		}
+		p.ids[token.value] = id
+		p.graph.Nodes = append(p.graph.Nodes, Node{ID: id, Label: token.value})
+	}
+
+	for i := range p.graph.Nodes {
+		if p.graph.Nodes[i].ID == id {
+			return &p.graph.Nodes[i]
+		}
+	}
+	return nil
+}
+
+func (p *dotParser) used(id string) bool {
+	for _, node := range p.graph.Nodes {
+		if node.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package diagram_test
+
+import (
+	"sketch/internal/diagram"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDOT(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		assert func(t *testing.T, graph *diagram.Graph, err error)
+	}{
+		{
+			name: "when the graph is valid, should read its nodes, edges and labels",
+			source: `strict digraph deploy {
+	/* the pipeline */
+	rankdir=LR; node [shape=box]
+	build [label="Build\nstep", color=red]
+	build -> test -> "deploy it" [label="ok"] // chained
+	# ignored
+}`,
+			assert: func(t *testing.T, graph *diagram.Graph, err error) {
+				assert.NoError(t, err)
+				assert.True(t, graph.Horizontal)
+				assert.Equal(t, []diagram.Node{
+					{ID: "build", Label: "Build\nstep"},
+					{ID: "test", Label: "test"},
+					{ID: "node3", Label: "deploy it"},
+				}, graph.Nodes)
+				assert.Equal(t, []diagram.Edge{
+					{From: "build", To: "test", Label: "ok"},
+					{From: "test", To: "node3", Label: "ok"},
+				}, graph.Edges)
+			},
+		},
+		{
+			name:   "when rankdir is set in a graph attribute statement, should place the layers horizontally",
+			source: "digraph {\n graph [rankdir=LR, splines=ortho]\n a -> b\n}",
+			assert: func(t *testing.T, graph *diagram.Graph, err error) {
+				assert.NoError(t, err)
+				assert.True(t, graph.Horizontal)
+			},
+		},
+		{
+			name:   "when the graph is undirected, should return an error",
+			source: "graph { a -- b }",
+			assert: func(t *testing.T, graph *diagram.Graph, err error) {
+				assert.EqualError(t, err, "invalid dot: line 1: only digraphs are supported")
+			},
+		},
+		{
+			name:   "when there is a subgraph, should return an error",
+			source: "digraph {\n subgraph cluster { a }\n}",
+			assert: func(t *testing.T, graph *diagram.Graph, err error) {
+				assert.EqualError(t, err, "invalid dot: line 2: subgraphs are not supported")
+			},
+		},
+		{
+			name:   "when a string is not terminated, should return an error",
+			source: "digraph {\n a [label=\"open]\n}",
+			assert: func(t *testing.T, graph *diagram.Graph, err error) {
+				assert.EqualError(t, err, "invalid dot: line 2: unterminated string")
+			},
+		},
+		{
+			name:   "when the graph is not closed, should return an error",
+			source: "digraph { a -> b",
+			assert: func(t *testing.T, graph *diagram.Graph, err error) {
+				assert.EqualError(t, err, "invalid dot: line 1: unexpected end of input")
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			graph, err := diagram.ParseDOT(tc.source)
+			tc.assert(t, graph, err)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package diagram
+
+import (
+	"strings"
+)
+
+const (
+	free cell = iota
+	wall
+	horizontal
+	vertical
+	// joint marks corners, crossings, arrowheads and labels: cells no other
+	// connector may go through.
+	joint
+)
+
+type (
+	cell int
+
+	point struct {
+		x, y int
+	}
+
+	box struct {
+		x, y, width, height int
+	}
+
+	grid struct {
+		width, height int
+		chars         [][]rune
+		cells         [][]cell
+	}
+)
+
+func (b box) overlaps(other box) bool {
+	return b.x < other.x+other.width && other.x < b.x+b.width &&
+		b.y < other.y+other.height && other.y < b.y+b.height
+}
+
+func newGrid(width, height int) *grid {
+	g := &grid{width: width, height: height, chars: make([][]rune, height), cells: make([][]cell, height)}
+	for y := 0; y < height; y++ {
+		g.chars[y] = []rune(strings.Repeat(" ", width))
+		g.cells[y] = make([]cell, width)
+	}
+	return g
+}
+
+func (g *grid) inside(p point) bool {
+	return p.x >= 0 && p.y >= 0 && p.x < g.width && p.y < g.height
+}
+
+func (g *grid) set(p point, char rune, kind cell) {
+	g.chars[p.y][p.x] = char
+	g.cells[p.y][p.x] = kind
+}
+
+// drawBox draws the outline and centers the label inside it. The whole box
+// becomes a wall for the connectors.
+func (g *grid) drawBox(b box, label string) {
+	for y := b.y; y < b.y+b.height; y++ {
+		for x := b.x; x < b.x+b.width; x++ {
+			char := ' '
+			isTop, isBottom := y == b.y, y == b.y+b.height-1
+			isSide := x == b.x || x == b.x+b.width-1
+			switch {
+			case (isTop || isBottom) && isSide:
+				char = '+'
+			case isTop || isBottom:
+				char = '-'
+			case isSide:
+				char = '|'
+			}
+			g.set(point{x, y}, char, wall)
+		}
+	}
+
+	lines := strings.Split(label, "\n")
+	top := b.y + (b.height-len(lines))/2
+	for i, line := range lines {
+		runes := []rune(line)
+		left := b.x + (b.width-len(runes))/2
+		for j, char := range runes {
+			g.chars[top+i][left+j] = char
+		}
+	}
+}
+
+// drawPath draws the connector with straight lines, '+' where it turns or
+// crosses another one and an arrowhead at its end.
+func (g *grid) drawPath(path []step) {
+	arrows := [4]rune{'>', 'v', '<', '^'}
+	for i, current := range path {
+		p := current.point
+		if i == len(path)-1 {
+			g.set(p, arrows[current.direction], joint)
+			continue
+		}
+
+		next := path[i+1].direction
+		switch {
+		case next != current.direction, g.cells[p.y][p.x] != free:
+			g.set(p, '+', joint)
+		case next.isHorizontal():
+			g.set(p, '-', horizontal)
+		default:
+			g.set(p, '|', vertical)
+		}
+	}
+}
+
+// drawLabel writes the label over the longest horizontal stretch of the path
+// when it fits there, or next to the path, as close to its middle as possible.
+// Labels without room are left out.
+func (g *grid) drawLabel(path []step, label string) {
+	runes := []rune(label)
+	length := len(runes)
+
+	start, size := 0, 0
+	for i := 0; i < len(path); {
+		j := i
+		for j < len(path) && g.chars[path[j].y][path[j].x] == '-' {
+			j++
+		}
+		if j-i > size {
+			start, size = i, j-i
+		}
+		i = j + 1
+	}
+
+	if size >= length+2 {
+		// Paths going left list their cells from right to left.
+		first := path[start+(size-length)/2].point
+		if path[start].direction == left {
+			first = path[start+(size-length)/2+length-1].point
+		}
+		for i, char := range runes {
+			g.set(point{first.x + i, first.y}, char, joint)
+		}
+		return
+	}
+
+	middle := len(path) / 2
+	for distance := 0; distance <= len(path); distance++ {
+		for _, i := range []int{middle - distance, middle + distance} {
+			if i < 0 || i >= len(path) {
+				continue
+			}
+			p := path[i].point
+			candidates := []point{{p.x + 2, p.y}, {p.x - length - 1, p.y}, {p.x - length/2, p.y - 1}, {p.x - length/2, p.y + 1}}
+			for _, candidate := range candidates {
+				if g.isFree(candidate, length) {
+					for j, char := range runes {
+						g.set(point{candidate.x + j, candidate.y}, char, joint)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// isFree tells whether the row has room for a text of the given length
+// starting at the point, with a blank on each side.
+func (g *grid) isFree(p point, length int) bool {
+	for x := p.x - 1; x <= p.x+length; x++ {
+		current := point{x, p.y}
+		if !g.inside(current) {
+			if x == p.x-1 || x == p.x+length {
+				continue
+			}
+			return false
+		}
+		if g.cells[p.y][x] != free {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *grid) String() string {
+	lines := make([]string, g.height)
+	for y, row := range g.chars {
+		lines[y] = strings.TrimRight(string(row), " ")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
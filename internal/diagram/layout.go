@@ -0,0 +1,242 @@
+package diagram
+
+import (
+	"sort"
+)
+
+const (
+	// layerGap is the room between layers and nodeGap between the nodes of a
+	// layer, enough for connectors and their labels to go through.
+	layerGap = 4
+	nodeGap  = 4
+	// sweeps is how many times the layers are reordered to reduce crossings.
+	sweeps = 4
+)
+
+type (
+	// vertex is a node of the layered graph. Edges spanning more than one
+	// layer go through dummy vertices, which keep a channel open for them.
+	vertex struct {
+		node     int
+		dummy    bool
+		layer    int
+		position float64
+		up       []int
+		down     []int
+	}
+
+	layering struct {
+		vertices []*vertex
+		layers   [][]int
+	}
+)
+
+// Layout places the nodes of the graph in layers, Sugiyama style: cycles are
+// broken, nodes go to the layer after their farthest predecessor, layers are
+// reordered by the barycenter of their neighbours and then spread so that
+// every layer is centered on the widest one.
+func Layout(graph *Graph) Diagram {
+	result := Diagram{Nodes: make([]Node, len(graph.Nodes)), Edges: graph.Edges}
+	copy(result.Nodes, graph.Nodes)
+	if len(result.Nodes) == 0 {
+		return result
+	}
+
+	l := newLayering(result)
+	l.order()
+	l.place(result.Nodes, graph.Horizontal)
+	return result
+}
+
+func newLayering(d Diagram) *layering {
+	index := make(map[string]int, len(d.Nodes))
+	for i, node := range d.Nodes {
+		index[node.ID] = i
+	}
+
+	edges := make([][2]int, 0, len(d.Edges))
+	for _, edge := range d.Edges {
+		from, to := index[edge.From], index[edge.To]
+		if from != to {
+			edges = append(edges, [2]int{from, to})
+		}
+	}
+	edges = acyclic(len(d.Nodes), edges)
+
+	layers := make([]int, len(d.Nodes))
+	for changed := true; changed; {
+		changed = false
+		for _, edge := range edges {
+			if layers[edge[1]] < layers[edge[0]]+1 {
+				layers[edge[1]] = layers[edge[0]] + 1
+				changed = true
+			}
+		}
+	}
+
+	l := &layering{}
+	for i := range d.Nodes {
+		l.add(&vertex{node: i, layer: layers[i]})
+	}
+
+	for _, edge := range edges {
+		from := edge[0]
+		for layer := layers[edge[0]] + 1; layer < layers[edge[1]]; layer++ {
+			dummy := l.add(&vertex{node: -1, dummy: true, layer: layer})
+			l.connect(from, dummy)
+			from = dummy
+		}
+		l.connect(from, edge[1])
+	}
+	return l
+}
+
+// acyclic reverses the edges that close a cycle, found with a depth first
+// search in the order the nodes were declared.
+func acyclic(count int, edges [][2]int) [][2]int {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, count)
+	result := make([][2]int, len(edges))
+	copy(result, edges)
+
+	var visit func(node int)
+	visit = func(node int) {
+		state[node] = visiting
+		for i, edge := range result {
+			if edge[0] != node {
+				continue
+			}
+			switch state[edge[1]] {
+			case visiting:
+				result[i] = [2]int{edge[1], edge[0]}
+			case unvisited:
+				visit(edge[1])
+			}
+		}
+		state[node] = visited
+	}
+
+	for node := 0; node < count; node++ {
+		if state[node] == unvisited {
+			visit(node)
+		}
+	}
+	return result
+}
+
+func (l *layering) add(v *vertex) int {
+	l.vertices = append(l.vertices, v)
+	for len(l.layers) <= v.layer {
+		l.layers = append(l.layers, nil)
+	}
+	index := len(l.vertices) - 1
+	v.position = float64(len(l.layers[v.layer]))
+	l.layers[v.layer] = append(l.layers[v.layer], index)
+	return index
+}
+
+func (l *layering) connect(from, to int) {
+	l.vertices[from].down = append(l.vertices[from].down, to)
+	l.vertices[to].up = append(l.vertices[to].up, from)
+}
+
+// order sweeps the layers down and up, sorting each one by the average
+// position of its neighbours in the layer before it.
+func (l *layering) order() {
+	for sweep := 0; sweep < sweeps; sweep++ {
+		for layer := 1; layer < len(l.layers); layer++ {
+			l.sort(layer, func(v *vertex) []int { return v.up })
+		}
+		for layer := len(l.layers) - 2; layer >= 0; layer-- {
+			l.sort(layer, func(v *vertex) []int { return v.down })
+		}
+	}
+}
+
+func (l *layering) sort(layer int, neighbours func(v *vertex) []int) {
+	barycenters := make(map[int]float64, len(l.layers[layer]))
+	for _, index := range l.layers[layer] {
+		v := l.vertices[index]
+		barycenters[index] = v.position
+		if related := neighbours(v); len(related) > 0 {
+			sum := 0.0
+			for _, other := range related {
+				sum += l.vertices[other].position
+			}
+			barycenters[index] = sum / float64(len(related))
+		}
+	}
+
+	vertices := l.layers[layer]
+	sort.SliceStable(vertices, func(i, j int) bool {
+		return barycenters[vertices[i]] < barycenters[vertices[j]]
+	})
+	for position, index := range vertices {
+		l.vertices[index].position = float64(position)
+	}
+}
+
+// place turns the layers into coordinates. Layers go down, or right when
+// horizontal, and their nodes are spread across, dummies taking one cell.
+// Everything is moved one cell away from the origin so connectors can go
+// around the first nodes.
+func (l *layering) place(nodes []Node, horizontal bool) {
+	size := func(v *vertex) (int, int) {
+		if v.dummy {
+			return 1, 1
+		}
+		width, height := nodes[v.node].Size()
+		if horizontal {
+			return height, width
+		}
+		return width, height
+	}
+
+	// across is the size of a layer along it, depth its size between layers.
+	across := make([]int, len(l.layers))
+	depth := make([]int, len(l.layers))
+	widest := 0
+	for layer, vertices := range l.layers {
+		for i, index := range vertices {
+			along, between := size(l.vertices[index])
+			if i > 0 {
+				across[layer] += nodeGap
+			}
+			across[layer] += along
+			if between > depth[layer] {
+				depth[layer] = between
+			}
+		}
+		if across[layer] > widest {
+			widest = across[layer]
+		}
+	}
+
+	offset := 1
+	for layer, vertices := range l.layers {
+		cursor := 1 + (widest-across[layer])/2
+		for _, index := range vertices {
+			v := l.vertices[index]
+			along, between := size(v)
+			if !v.dummy {
+				node := &nodes[v.node]
+				// Nodes thinner than their layer are centered in it.
+				inner := offset + (depth[layer]-between)/2
+				if horizontal {
+					node.X, node.Y = inner, cursor
+				} else {
+					node.X, node.Y = cursor, inner
+				}
+			}
+			cursor += along + nodeGap
+		}
+		offset += depth[layer] + layerGap
+		if horizontal {
+			offset += layerGap
+		}
+	}
+}
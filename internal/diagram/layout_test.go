@@ -0,0 +1,45 @@
+package diagram_test
+
+import (
+	"sketch/internal/diagram"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayout(t *testing.T) {
+	t.Run("when the graph goes down, should put each node in the layer after its farthest predecessor", func(t *testing.T) {
+		graph, err := diagram.ParseDOT("digraph { a -> b -> c; a -> c; c -> a }")
+		assert.NoError(t, err)
+
+		d := diagram.Layout(graph)
+
+		// a -> c and the reversed c -> a keep two channels open next to b.
+		assert.NoError(t, d.Validate())
+		assert.Equal(t, []diagram.Node{
+			{ID: "a", Label: "a", X: 6, Y: 1},
+			{ID: "b", Label: "b", X: 1, Y: 8},
+			{ID: "c", Label: "c", X: 6, Y: 15},
+		}, d.Nodes)
+	})
+
+	t.Run("when the graph goes right, should render the layers side by side", func(t *testing.T) {
+		graph, err := diagram.ParseDOT(`digraph { rankdir=LR; a -> b; a -> c }`)
+		assert.NoError(t, err)
+
+		got, err := diagram.Layout(graph).Render()
+
+		assert.NoError(t, err)
+		assert.Equal(t, `
+              +---+
+              | b |
+              +---+
+ +---+         ^
+ | a |---------+
+ +---+
+    |
+    |         +---+
+    +-------->| c |
+              +---+`, got)
+	})
+}
@@ -0,0 +1,221 @@
+package diagram
+
+import (
+	"container/heap"
+)
+
+const (
+	right direction = iota
+	down
+	left
+	up
+
+	// turnCost and crossingCost make the router prefer straight connectors
+	// that cross few others over slightly shorter ones.
+	turnCost     = 3
+	crossingCost = 6
+)
+
+var (
+	deltas = [4]point{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
+)
+
+type (
+	direction int
+
+	// step is a cell of a path and the direction it was entered from.
+	step struct {
+		point
+		direction direction
+	}
+
+	queued struct {
+		step
+		cost     int
+		estimate int
+		order    int
+		index    int
+	}
+
+	queue []*queued
+)
+
+func (d direction) isHorizontal() bool {
+	return d == right || d == left
+}
+
+func (d direction) opposite() direction {
+	return (d + 2) % 4
+}
+
+func (p point) move(d direction) point {
+	return point{p.x + deltas[d].x, p.y + deltas[d].y}
+}
+
+func (q queue) Len() int {
+	return len(q)
+}
+
+func (q queue) Less(i, j int) bool {
+	if q[i].estimate != q[j].estimate {
+		return q[i].estimate < q[j].estimate
+	}
+	return q[i].order < q[j].order
+}
+
+func (q queue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *queue) Push(value any) {
+	item := value.(*queued)
+	item.index = len(*q)
+	*q = append(*q, item)
+}
+
+func (q *queue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// ports returns the cells touching the sides of the box, corners excluded,
+// each with the direction pointing away from the box. They are listed from the
+// middle of the sides outwards, so that ties favour centered connectors.
+func (b box) ports() []step {
+	ports := make([]step, 0, 2*(b.width+b.height))
+	for _, x := range outwards(b.x+1, b.x+b.width-1) {
+		ports = append(ports, step{point{x, b.y - 1}, up}, step{point{x, b.y + b.height}, down})
+	}
+	for _, y := range outwards(b.y+1, b.y+b.height-1) {
+		ports = append(ports, step{point{b.x - 1, y}, left}, step{point{b.x + b.width, y}, right})
+	}
+	return ports
+}
+
+// outwards lists the values from start up to end, excluded, starting from the
+// middle one and alternating sides.
+func outwards(start, end int) []int {
+	values := make([]int, 0, end-start)
+	middle := start + (end-start-1)/2
+	for distance := 0; len(values) < end-start; distance++ {
+		if value := middle - distance; value >= start && distance > 0 {
+			values = append(values, value)
+		}
+		if value := middle + distance; value < end {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// distance is the Manhattan distance from the point to the ring of cells
+// around the box.
+func (b box) distance(p point) int {
+	distance := 0
+	if p.x < b.x-1 {
+		distance += b.x - 1 - p.x
+	} else if p.x > b.x+b.width {
+		distance += p.x - b.x - b.width
+	}
+	if p.y < b.y-1 {
+		distance += b.y - 1 - p.y
+	} else if p.y > b.y+b.height {
+		distance += p.y - b.y - b.height
+	}
+	return distance
+}
+
+// route finds the cheapest orthogonal path with A*, leaving a port of the
+// source box and entering a port of the target one straight towards it. The
+// path may cross straight connectors at right angles but never runs along
+// them, turns on them or goes through joints.
+func (g *grid) route(source, target box) ([]step, bool) {
+	goals := make(map[step]bool)
+	for _, port := range target.ports() {
+		goals[step{port.point, port.direction.opposite()}] = true
+	}
+
+	costs := make(map[step]int)
+	parents := make(map[step]step)
+	open := &queue{}
+	order := 0
+	push := func(current step, cost int) {
+		if known, ok := costs[current]; ok && known <= cost {
+			return
+		}
+		costs[current] = cost
+		order++
+		heap.Push(open, &queued{step: current, cost: cost, estimate: cost + target.distance(current.point), order: order})
+	}
+
+	for _, port := range source.ports() {
+		if g.inside(port.point) && g.cells[port.y][port.x] == free {
+			push(port, 0)
+		}
+	}
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*queued)
+		if current.cost > costs[current.step] {
+			continue
+		}
+
+		if goals[current.step] && g.cells[current.y][current.x] == free {
+			path := []step{current.step}
+			for at := current.step; ; {
+				parent, ok := parents[at]
+				if !ok {
+					break
+				}
+				path = append([]step{parent}, path...)
+				at = parent
+			}
+			return path, true
+		}
+
+		crossing := g.cells[current.y][current.x] != free
+		for d := right; d <= up; d++ {
+			if d == current.direction.opposite() || (crossing && d != current.direction) {
+				continue
+			}
+
+			next := current.point.move(d)
+			if !g.inside(next) || !g.canEnter(next, d) {
+				continue
+			}
+
+			cost := current.cost + 1
+			if d != current.direction {
+				cost += turnCost
+			}
+			if g.cells[next.y][next.x] != free {
+				cost += crossingCost
+			}
+
+			following := step{next, d}
+			if known, ok := costs[following]; !ok || cost < known {
+				parents[following] = current.step
+			}
+			push(following, cost)
+		}
+	}
+	return nil, false
+}
+
+// canEnter tells whether a connector moving in the direction may go through
+// the cell: free cells, or straight connectors perpendicular to it.
+func (g *grid) canEnter(p point, d direction) bool {
+	switch g.cells[p.y][p.x] {
+	case free:
+		return true
+	case horizontal:
+		return !d.isHorizontal()
+	case vertical:
+		return d.isHorizontal()
+	}
+	return false
+}
@@ -0,0 +1,361 @@
+package diagram
+
+import (
+	"fmt"
+	"regexp"
+	"sketch/internal/errors"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	noteOver  notePlacement = "over"
+	noteLeft  notePlacement = "left of"
+	noteRight notePlacement = "right of"
+
+	// selfCallWidth is how far a self call goes right of its lifeline.
+	selfCallWidth = 5
+	// participantGap is the least room between two participant boxes.
+	participantGap = 2
+)
+
+var (
+	ErrEmptySequence = errors.Error("sequence must have at least one participant")
+
+	participantPattern = regexp.MustCompile(`^participant\s+([A-Za-z0-9_]+)(?:\s+as\s+(.+))?$`)
+	messagePattern     = regexp.MustCompile(`^([A-Za-z0-9_]+)\s*(-->|->)\s*([A-Za-z0-9_]+)\s*(?::\s*(.*))?$`)
+	notePattern        = regexp.MustCompile(`^note\s+(over|left of|right of)\s+([A-Za-z0-9_]+)(?:\s*,\s*([A-Za-z0-9_]+))?\s*:\s*(.+)$`)
+)
+
+type (
+	notePlacement string
+
+	participant struct {
+		name  string
+		label string
+	}
+
+	// sequenceEvent is a message, a self call when both ends are the same
+	// participant, or a note when placement is set.
+	sequenceEvent struct {
+		from      int
+		to        int
+		text      string
+		dashed    bool
+		placement notePlacement
+	}
+
+	// Sequence is a sequence diagram: participants side by side, with their
+	// lifelines going down, and the events between them from top to bottom.
+	Sequence struct {
+		participants []participant
+		events       []sequenceEvent
+	}
+)
+
+// ParseSequence reads the sequence diagram language, one statement per line.
+// Participants are declared on their first use unless declared before.
+//
+//	# comments start with a hash
+//	participant web as "Web app"
+//	web -> api: GET /users
+//	api -> api: check cache
+//	api --> web: 200 OK
+//	note right of api: cached\nfor 5 minutes
+func ParseSequence(source string) (*Sequence, error) {
+	sequence := &Sequence{}
+	for i, raw := range strings.Split(source, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if match := participantPattern.FindStringSubmatch(line); match != nil {
+			index := sequence.participant(match[1])
+			if match[2] != "" {
+				sequence.participants[index].label = strings.Trim(match[2], `"`)
+			}
+			continue
+		}
+
+		if match := messagePattern.FindStringSubmatch(line); match != nil {
+			sequence.events = append(sequence.events, sequenceEvent{
+				from:   sequence.participant(match[1]),
+				to:     sequence.participant(match[3]),
+				text:   match[4],
+				dashed: match[2] == "-->",
+			})
+			continue
+		}
+
+		if match := notePattern.FindStringSubmatch(line); match != nil {
+			from := sequence.participant(match[2])
+			to := from
+			if match[3] != "" {
+				if notePlacement(match[1]) != noteOver {
+					return nil, sequenceError(i+1, "only notes over participants may span two of them")
+				}
+				to = sequence.participant(match[3])
+			}
+			if to < from {
+				from, to = to, from
+			}
+			sequence.events = append(sequence.events, sequenceEvent{
+				from:      from,
+				to:        to,
+				text:      strings.ReplaceAll(match[4], `\n`, "\n"),
+				placement: notePlacement(match[1]),
+			})
+			continue
+		}
+
+		return nil, sequenceError(i+1, "unknown statement '%s'", line)
+	}
+
+	if len(sequence.participants) == 0 {
+		return nil, ErrEmptySequence
+	}
+	return sequence, nil
+}
+
+func sequenceError(line int, format string, args ...any) error {
+	return errors.Error(fmt.Sprintf("line %d: %s", line, fmt.Sprintf(format, args...)))
+}
+
+func (s *Sequence) participant(name string) int {
+	for i, current := range s.participants {
+		if current.name == name {
+			return i
+		}
+	}
+	s.participants = append(s.participants, participant{name: name, label: name})
+	return len(s.participants) - 1
+}
+
+// Render draws the boxes of the participants on top, their lifelines going
+// down and the events between them. Notes are boxes too, which the lifelines
+// behind them skip.
+func (s *Sequence) Render() string {
+	centers := s.centers()
+	var result sheet
+	for i, current := range s.participants {
+		width := utf8.RuneCountInString(current.label) + 4
+		result.write(centers[i]-width/2, 0, frame(current.label))
+	}
+
+	// covered keeps, per participant, the rows its lifeline must skip because
+	// a note is drawn over it.
+	covered := make([]map[int]bool, len(s.participants))
+	for i := range covered {
+		covered[i] = make(map[int]bool)
+	}
+
+	// The events are drawn over the lifelines, so they wait until these are.
+	var events sheet
+	row := 4
+	for _, event := range s.events {
+		from, to := centers[event.from], centers[event.to]
+		switch {
+		case event.placement != "":
+			content := frame(event.text)
+			width, height := longestLine(content), strings.Count(content, "\n")+1
+			x := s.noteX(event, centers, width)
+			events.write(x, row, content)
+			for i, center := range centers {
+				if center >= x && center < x+width {
+					for y := row; y < row+height; y++ {
+						covered[i][y] = true
+					}
+				}
+			}
+			row += height + 1
+		case event.from == event.to:
+			events.write(from+1, row, "----+\n    |\n<---+")
+			if event.text != "" {
+				events.write(from+selfCallWidth+2, row+1, event.text)
+			}
+			row += 4
+		default:
+			char, head := "-", ">"
+			if event.dashed {
+				char = "."
+			}
+			left, right := from, to
+			if to < from {
+				head, left, right = "<", to, from
+			}
+
+			if event.text != "" {
+				length := utf8.RuneCountInString(event.text)
+				events.write(left+(right-left-length)/2+1, row, event.text)
+				row++
+			}
+			arrow := strings.Repeat(char, right-left-2)
+			if head == ">" {
+				arrow += head
+			} else {
+				arrow = head + arrow
+			}
+			events.write(left+1, row, arrow)
+			row += 2
+		}
+	}
+
+	for i, center := range centers {
+		for y := 3; y < row; y++ {
+			if !covered[i][y] {
+				result.write(center, y, "|")
+			}
+		}
+	}
+	result.overlay(events)
+	return result.String()
+}
+
+// centers places the lifelines, leaving room between them for the boxes and
+// for every message, self call and note drawn in between.
+func (s *Sequence) centers() []int {
+	widths := make([]int, len(s.participants))
+	for i, current := range s.participants {
+		widths[i] = utf8.RuneCountInString(current.label) + 4
+	}
+
+	gaps := make([]int, len(s.participants))
+	for i := 0; i < len(gaps)-1; i++ {
+		gaps[i] = widths[i] - widths[i]/2 + widths[i+1]/2 + participantGap
+	}
+	require := func(from, to, room int) {
+		current := 0
+		for i := from; i < to; i++ {
+			current += gaps[i]
+		}
+		if current < room {
+			gaps[to-1] += room - current
+		}
+	}
+
+	for _, event := range s.events {
+		length := longestLine(event.text)
+		switch {
+		case event.placement == noteRight && event.from < len(gaps)-1:
+			require(event.from, event.from+1, length+4+participantGap)
+		case event.placement == noteLeft && event.from > 0:
+			require(event.from-1, event.from, length+4+participantGap)
+		case event.placement != "":
+		case event.from == event.to && event.from < len(gaps)-1:
+			require(event.from, event.from+1, selfCallWidth+2+length+participantGap)
+		case event.from != event.to:
+			from, to := event.from, event.to
+			if to < from {
+				from, to = to, from
+			}
+			require(from, to, length+4)
+		}
+	}
+
+	// The first lifeline leaves room for its box and for the notes on its
+	// left, which may be wider.
+	first := widths[0] / 2
+	for _, event := range s.events {
+		length := longestLine(event.text) + 4
+		switch {
+		case event.placement == noteLeft && event.from == 0 && length+1 > first:
+			first = length + 1
+		case event.placement == noteOver && event.from == 0 && length/2 > first:
+			first = length / 2
+		}
+	}
+
+	centers := make([]int, len(s.participants))
+	centers[0] = first
+	for i := 1; i < len(centers); i++ {
+		centers[i] = centers[i-1] + gaps[i-1]
+	}
+	return centers
+}
+
+func (s *Sequence) noteX(event sequenceEvent, centers []int, width int) int {
+	switch event.placement {
+	case noteLeft:
+		return centers[event.from] - 1 - width
+	case noteRight:
+		return centers[event.from] + 2
+	}
+	middle := (centers[event.from] + centers[event.to]) / 2
+	x := middle - width/2
+	if x < 0 {
+		return 0
+	}
+	return x
+}
+
+// frame surrounds the text, which may have many lines, with a border.
+func frame(text string) string {
+	lines := strings.Split(text, "\n")
+	width := longestLine(text)
+	border := "+" + strings.Repeat("-", width+2) + "+"
+
+	result := []string{border}
+	for _, line := range lines {
+		result = append(result, "| "+line+strings.Repeat(" ", width-utf8.RuneCountInString(line))+" |")
+	}
+	return strings.Join(append(result, border), "\n")
+}
+
+func longestLine(text string) int {
+	length := 0
+	for _, line := range strings.Split(text, "\n") {
+		if current := utf8.RuneCountInString(line); current > length {
+			length = current
+		}
+	}
+	return length
+}
+
+// sheet is where the sequence is drawn, growing to fit whatever is written on
+// it. Cells never written are nil and show as blanks.
+type sheet [][]rune
+
+// write puts the text, which may have many lines, starting at the point.
+func (s *sheet) write(x, y int, text string) {
+	for i, line := range strings.Split(text, "\n") {
+		for j, char := range []rune(line) {
+			s.set(x+j, y+i, char)
+		}
+	}
+}
+
+func (s *sheet) set(x, y int, char rune) {
+	for len(*s) <= y {
+		*s = append(*s, nil)
+	}
+	for len((*s)[y]) <= x {
+		(*s)[y] = append((*s)[y], 0)
+	}
+	(*s)[y][x] = char
+}
+
+// overlay writes every cell of the other sheet over this one.
+func (s *sheet) overlay(other sheet) {
+	for y, row := range other {
+		for x, char := range row {
+			if char != 0 {
+				s.set(x, y, char)
+			}
+		}
+	}
+}
+
+func (s sheet) String() string {
+	lines := make([]string, len(s))
+	for y, row := range s {
+		line := []rune(strings.Repeat(" ", len(row)))
+		for x, char := range row {
+			if char != 0 {
+				line[x] = char
+			}
+		}
+		lines[y] = strings.TrimRight(string(line), " ")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,81 @@
+package diagram_test
+
+import (
+	"sketch/internal/diagram"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSequence(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		assert func(t *testing.T, rendered string, err error)
+	}{
+		{
+			name:   "when the sequence is valid, should draw lifelines, messages, self calls and notes",
+			source: "# greeting\na -> b: hi\nb -> b\nb --> a\nnote over a: ok",
+			assert: func(t *testing.T, rendered string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, ` +---+  +---+
+ | a |  | b |
+ +---+  +---+
+   |      |
+   |  hi  |
+   |----->|
+   |      |
+   |      |----+
+   |      |    |
+   |      |<---+
+   |      |
+   |<.....|
+   |      |
++----+    |
+| ok |    |
++----+    |
+   |      |`, rendered)
+			},
+		},
+		{
+			name:   "when a participant has a label, should draw it in its box",
+			source: "participant web as \"Web app\"\nweb -> api",
+			assert: func(t *testing.T, rendered string, err error) {
+				assert.NoError(t, err)
+				assert.Contains(t, rendered, "+---------+  +-----+\n| Web app |  | api |\n+---------+  +-----+")
+			},
+		},
+		{
+			name:   "when a statement is unknown, should return the line",
+			source: "a -> b\nloop forever",
+			assert: func(t *testing.T, rendered string, err error) {
+				assert.EqualError(t, err, "line 2: unknown statement 'loop forever'")
+			},
+		},
+		{
+			name:   "when a side note spans two participants, should return an error",
+			source: "note left of a, b: no",
+			assert: func(t *testing.T, rendered string, err error) {
+				assert.EqualError(t, err, "line 1: only notes over participants may span two of them")
+			},
+		},
+		{
+			name:   "when there are only comments, should return an error",
+			source: "# nothing\n",
+			assert: func(t *testing.T, rendered string, err error) {
+				assert.ErrorIs(t, err, diagram.ErrEmptySequence)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sequence, err := diagram.ParseSequence(tc.source)
+			var rendered string
+			if err == nil {
+				rendered = sequence.Render()
+			}
+			tc.assert(t, rendered, err)
+		})
+	}
+}
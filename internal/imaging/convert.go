@@ -0,0 +1,205 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"sketch/internal/errors"
+	"strings"
+	"unicode"
+
+	// Register the decoders used by image.Decode.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+const (
+	DefaultRamp   = " .:-=+*#%@"
+	DefaultWidth  = 80
+	DefaultAspect = 0.5
+	MaxWidth      = 400
+
+	// maxImagePixels limits the images decoded, which take four bytes or more
+	// a pixel in memory, and maxRows the rows of the result, which very tall
+	// images would otherwise have by the thousands.
+	maxImagePixels = 16 << 20
+	maxRows        = 1000
+)
+
+var (
+	ErrInvalidWidth  = errors.Error("width must be between 1 and 400")
+	ErrInvalidRamp   = errors.Error("ramp must have at least two printable ascii characters")
+	ErrInvalidAspect = errors.Error("aspect must be greater than 0 and up to 4")
+	ErrInvalidImage  = errors.Error("invalid image, it must be a png, jpeg or gif")
+	ErrImageTooLarge = errors.Error(fmt.Sprintf("the image must have at most %d pixels", maxImagePixels))
+)
+
+type Options struct {
+	// Width is the number of columns of the result.
+	Width int
+	// Ramp goes from the lightest to the darkest character.
+	Ramp string
+	// Aspect is the width of a character divided by its height, used to keep
+	// the proportions of the image.
+	Aspect float64
+	// Dither spreads the quantization error with Floyd–Steinberg.
+	Dither bool
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Width:  DefaultWidth,
+		Ramp:   DefaultRamp,
+		Aspect: DefaultAspect,
+	}
+}
+
+func (o Options) Validate() error {
+	if o.Width < 1 || o.Width > MaxWidth {
+		return ErrInvalidWidth
+	}
+
+	if len(o.Ramp) < 2 {
+		return ErrInvalidRamp
+	}
+	for _, char := range o.Ramp {
+		if char > unicode.MaxASCII || !unicode.IsPrint(char) {
+			return ErrInvalidRamp
+		}
+	}
+
+	if o.Aspect <= 0 || o.Aspect > 4 {
+		return ErrInvalidAspect
+	}
+	return nil
+}
+
+// Decode reads the header of the image before decoding it, refusing those with
+// more pixels than maxImagePixels.
+func Decode(r io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	return img, nil
+}
+
+// ToASCII turns the image into text, mapping the luminance of each cell to a
+// character of the ramp.
+func ToASCII(img image.Image, options Options) string {
+	levels := luminance(img, options)
+	ramp := []rune(options.Ramp)
+	steps := float64(len(ramp) - 1)
+
+	lines := make([]string, len(levels))
+	for y, row := range levels {
+		line := strings.Builder{}
+		for x, value := range row {
+			// Darker cells get denser characters.
+			index := int(math.Round((1 - value) * steps))
+			index = clamp(index, 0, len(ramp)-1)
+			line.WriteRune(ramp[index])
+
+			if options.Dither {
+				quantized := 1 - float64(index)/steps
+				spread(levels, x, y, value-quantized)
+			}
+		}
+		lines[y] = line.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// luminance averages the pixels covered by every character cell, returning
+// values between 0 (black) and 1 (white). Images too tall for maxRows are
+// squeezed into them.
+func luminance(img image.Image, options Options) [][]float64 {
+	bounds := img.Bounds()
+	width := options.Width
+	cellWidth := float64(bounds.Dx()) / float64(width)
+	cellHeight := cellWidth / options.Aspect
+	height := int(math.Max(1, math.Round(float64(bounds.Dy())/cellHeight)))
+	if height > maxRows {
+		height = maxRows
+		cellHeight = float64(bounds.Dy()) / float64(height)
+	}
+
+	levels := make([][]float64, height)
+	for y := range levels {
+		levels[y] = make([]float64, width)
+		top := bounds.Min.Y + int(float64(y)*cellHeight)
+		bottom := clamp(bounds.Min.Y+int(float64(y+1)*cellHeight), top+1, bounds.Max.Y)
+
+		for x := range levels[y] {
+			left := bounds.Min.X + int(float64(x)*cellWidth)
+			right := clamp(bounds.Min.X+int(float64(x+1)*cellWidth), left+1, bounds.Max.X)
+
+			total, count := 0.0, 0
+			for py := top; py < bottom; py++ {
+				for px := left; px < right; px++ {
+					total += pixelLuminance(img.At(px, py))
+					count++
+				}
+			}
+			if count > 0 {
+				levels[y][x] = total / float64(count)
+			} else {
+				levels[y][x] = 1
+			}
+		}
+	}
+	return levels
+}
+
+// pixelLuminance uses the Rec. 709 weights, compositing transparent pixels
+// over white.
+func pixelLuminance(c color.Color) float64 {
+	r, g, b, a := c.RGBA()
+	const full = 0xffff
+	white := float64(full - a)
+	red := (float64(r) + white) / full
+	green := (float64(g) + white) / full
+	blue := (float64(b) + white) / full
+	return 0.2126*red + 0.7152*green + 0.0722*blue
+}
+
+// spread distributes the error of a cell to its neighbors that were not
+// converted yet, with the Floyd–Steinberg weights.
+func spread(levels [][]float64, x, y int, err float64) {
+	add := func(x, y int, weight float64) {
+		if y < len(levels) && x >= 0 && x < len(levels[y]) {
+			levels[y][x] += err * weight
+		}
+	}
+	add(x+1, y, 7.0/16)
+	add(x-1, y+1, 3.0/16)
+	add(x, y+1, 5.0/16)
+	add(x+1, y+1, 1.0/16)
+}
+
+func clamp(value, lower, upper int) int {
+	if value < lower {
+		return lower
+	}
+	if value > upper {
+		return upper
+	}
+	return value
+}
@@ -0,0 +1,149 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newGradient(width, height int) image.Image {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(255 * x / (width - 1))})
+		}
+	}
+	return img
+}
+
+func TestToASCII(t *testing.T) {
+	tests := []struct {
+		name     string
+		img      image.Image
+		options  Options
+		expected string
+	}{
+		{
+			name:     "when the image is a gradient, should go from dark to light characters",
+			img:      newGradient(5, 2),
+			options:  Options{Width: 5, Ramp: " .#", Aspect: 0.5},
+			expected: "##.. ",
+		},
+		{
+			name:     "when the aspect is 1, should keep the number of rows",
+			img:      newGradient(5, 2),
+			options:  Options{Width: 5, Ramp: " .#", Aspect: 1},
+			expected: "##.. \n##.. ",
+		},
+		{
+			name:     "when the image is transparent, should be blank",
+			img:      image.NewNRGBA(image.Rect(0, 0, 4, 2)),
+			options:  Options{Width: 4, Ramp: " #", Aspect: 0.5},
+			expected: "    ",
+		},
+		{
+			name:     "when the image is wider than the target, should average the pixels",
+			img:      newGradient(10, 4),
+			options:  Options{Width: 2, Ramp: " .:#", Aspect: 1},
+			expected: ":.",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ToASCII(tc.img, tc.options))
+		})
+	}
+}
+
+func TestToASCII_Dither(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 8, 8))
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			img.SetGray(x, y, color.Gray{Y: 128})
+		}
+	}
+
+	plain := ToASCII(img, Options{Width: 8, Ramp: " #", Aspect: 1})
+	dithered := ToASCII(img, Options{Width: 8, Ramp: " #", Aspect: 1, Dither: true})
+
+	assert.Equal(t, 0, strings.Count(plain, "#"))
+	assert.InDelta(t, 32, strings.Count(dithered, "#"), 2)
+}
+
+func TestToASCII_MaxRows(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 1, 5000))
+
+	result := ToASCII(img, Options{Width: 1, Ramp: " #", Aspect: 1})
+
+	assert.Equal(t, maxRows, strings.Count(result, "\n")+1)
+}
+
+func TestDecode(t *testing.T) {
+	encoded := &bytes.Buffer{}
+	_ = gif.Encode(encoded, image.NewPaletted(image.Rect(0, 0, 1, 1), []color.Color{color.White}), nil)
+
+	t.Run("when the image is small, should decode it", func(t *testing.T) {
+		img, err := Decode(bytes.NewReader(encoded.Bytes()))
+
+		assert.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 1, 1), img.Bounds())
+	})
+
+	t.Run("when the header tells too many pixels, should refuse it before decoding", func(t *testing.T) {
+		huge := bytes.Clone(encoded.Bytes())
+		// The logical screen of a GIF is two little-endian words after "GIF89a".
+		copy(huge[6:10], []byte{0x00, 0x20, 0x00, 0x20})
+
+		_, err := Decode(bytes.NewReader(huge))
+
+		assert.ErrorIs(t, err, ErrImageTooLarge)
+	})
+
+	t.Run("when the data is not an image, should return an error", func(t *testing.T) {
+		_, err := Decode(strings.NewReader("hello"))
+
+		assert.ErrorIs(t, err, ErrInvalidImage)
+	})
+}
+
+func TestOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  func() Options
+		expected error
+	}{
+		{name: "default options, should be valid", options: DefaultOptions},
+		{
+			name:     "width out of range, should return an error",
+			options:  func() Options { o := DefaultOptions(); o.Width = MaxWidth + 1; return o },
+			expected: ErrInvalidWidth,
+		},
+		{
+			name:     "ramp with a single character, should return an error",
+			options:  func() Options { o := DefaultOptions(); o.Ramp = "#"; return o },
+			expected: ErrInvalidRamp,
+		},
+		{
+			name:     "ramp with non ascii characters, should return an error",
+			options:  func() Options { o := DefaultOptions(); o.Ramp = " ░▒▓█"; return o },
+			expected: ErrInvalidRamp,
+		},
+		{
+			name:     "zero aspect, should return an error",
+			options:  func() Options { o := DefaultOptions(); o.Aspect = 0; return o },
+			expected: ErrInvalidAspect,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.options().Validate())
+		})
+	}
+}
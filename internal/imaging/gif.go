@@ -0,0 +1,141 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"sketch/internal/errors"
+	"strings"
+)
+
+const (
+	DefaultScale = 2
+	MaxScale     = 8
+	MaxDelay     = 60000
+	// minDelay is the shortest delay, in hundredths of a second, browsers
+	// honour. Shorter ones are played much slower than asked.
+	minDelay = 2
+	// maxPixels limits the pixels of all the frames of a GIF together, and
+	// maxSide is the widest and tallest frame the format holds.
+	maxPixels = 64 << 20
+	maxSide   = 65535
+)
+
+var (
+	ErrInvalidScale = errors.Error("scale must be between 1 and 8")
+	ErrInvalidDelay = errors.Error("delay must be between 0 and 60000 milliseconds")
+	ErrEmptyGIF     = errors.Error("a gif must have at least one frame")
+	ErrGIFTooLarge  = errors.Error("the drawing is too large to be exported as a gif")
+
+	gifPalette = color.Palette{color.White, color.Black}
+)
+
+type (
+	// GIFFrame is a drawing and how long it is shown, in milliseconds.
+	GIFFrame struct {
+		Drawing  string
+		Duration int
+	}
+
+	GIFOptions struct {
+		// Scale is the size, in pixels, of every pixel of the font.
+		Scale int
+		// Delay replaces the duration of every frame when greater than zero,
+		// in milliseconds.
+		Delay int
+	}
+)
+
+func DefaultGIFOptions() GIFOptions {
+	return GIFOptions{
+		Scale: DefaultScale,
+	}
+}
+
+func (o GIFOptions) Validate() error {
+	if o.Scale < 1 || o.Scale > MaxScale {
+		return ErrInvalidScale
+	}
+
+	if o.Delay < 0 || o.Delay > MaxDelay {
+		return ErrInvalidDelay
+	}
+	return nil
+}
+
+// EncodeGIF writes the frames as an animated GIF that loops forever, black
+// text over white. Every frame has the size of the largest drawing, and
+// characters outside of printable ASCII are drawn as '?'.
+func EncodeGIF(w io.Writer, frames []GIFFrame, options GIFOptions) error {
+	if len(frames) == 0 {
+		return ErrEmptyGIF
+	}
+
+	columns, rows := 1, 1
+	for _, frame := range frames {
+		lines := strings.Split(frame.Drawing, "\n")
+		if len(lines) > rows {
+			rows = len(lines)
+		}
+		for _, line := range lines {
+			if length := len([]rune(line)); length > columns {
+				columns = length
+			}
+		}
+	}
+
+	bounds := image.Rect(0, 0, columns*cellWidth*options.Scale, rows*cellHeight*options.Scale)
+	if bounds.Dx() > maxSide || bounds.Dy() > maxSide || bounds.Dx()*bounds.Dy()*len(frames) > maxPixels {
+		return ErrGIFTooLarge
+	}
+
+	animation := &gif.GIF{
+		Image: make([]*image.Paletted, 0, len(frames)),
+		Delay: make([]int, 0, len(frames)),
+	}
+	for _, frame := range frames {
+		duration := frame.Duration
+		if options.Delay > 0 {
+			duration = options.Delay
+		}
+
+		delay := duration / 10
+		if delay < minDelay {
+			delay = minDelay
+		}
+
+		animation.Image = append(animation.Image, rasterize(frame.Drawing, bounds, options.Scale))
+		animation.Delay = append(animation.Delay, delay)
+	}
+	return gif.EncodeAll(w, animation)
+}
+
+func rasterize(drawing string, bounds image.Rectangle, scale int) *image.Paletted {
+	img := image.NewPaletted(bounds, gifPalette)
+	for row, line := range strings.Split(drawing, "\n") {
+		for column, char := range []rune(line) {
+			if char < ' ' || char > '~' {
+				char = '?'
+			}
+
+			glyph := glyphs[char-' ']
+			for y := 0; y < glyphHeight; y++ {
+				for x := 0; x < glyphWidth; x++ {
+					if glyph[y]&(1<<(glyphWidth-1-x)) == 0 {
+						continue
+					}
+
+					left := (column*cellWidth + x) * scale
+					top := (row*cellHeight + y) * scale
+					for dy := 0; dy < scale; dy++ {
+						for dx := 0; dx < scale; dx++ {
+							img.SetColorIndex(left+dx, top+dy, 1)
+						}
+					}
+				}
+			}
+		}
+	}
+	return img
+}
@@ -0,0 +1,118 @@
+package imaging
+
+import (
+	"bytes"
+	"image/gif"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ascii turns the pixels of a frame back into text, '#' for black.
+func ascii(t *testing.T, encoded []byte, frame int) string {
+	t.Helper()
+	decoded, err := gif.DecodeAll(bytes.NewReader(encoded))
+	assert.NoError(t, err)
+
+	img := decoded.Image[frame]
+	lines := make([]string, 0, img.Bounds().Dy())
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		line := strings.Builder{}
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			if img.ColorIndexAt(x, y) == 1 {
+				line.WriteByte('#')
+			} else {
+				line.WriteByte('.')
+			}
+		}
+		lines = append(lines, line.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestEncodeGIF(t *testing.T) {
+	t.Run("when there are many frames, should size them all as the largest one", func(t *testing.T) {
+		encoded := bytes.Buffer{}
+		frames := []GIFFrame{{Drawing: "-", Duration: 200}, {Drawing: "ab\ncd", Duration: 5}}
+
+		err := EncodeGIF(&encoded, frames, GIFOptions{Scale: 1})
+
+		assert.NoError(t, err)
+		decoded, _ := gif.DecodeAll(bytes.NewReader(encoded.Bytes()))
+		assert.Equal(t, []int{20, minDelay}, decoded.Delay)
+		assert.Equal(t, 0, decoded.LoopCount)
+		for _, img := range decoded.Image {
+			assert.Equal(t, 2*cellWidth, img.Bounds().Dx())
+			assert.Equal(t, 2*cellHeight, img.Bounds().Dy())
+		}
+	})
+
+	t.Run("when scaled, should draw every pixel of the glyph as a square", func(t *testing.T) {
+		encoded := bytes.Buffer{}
+
+		err := EncodeGIF(&encoded, []GIFFrame{{Drawing: "-"}}, GIFOptions{Scale: 2})
+
+		assert.NoError(t, err)
+		expected := strings.Join([]string{
+			"............",
+			"............",
+			"............",
+			"............",
+			"............",
+			"............",
+			"##########..",
+			"##########..",
+			"............",
+			"............",
+			"............",
+			"............",
+			"............",
+			"............",
+			"............",
+			"............",
+		}, "\n")
+		assert.Equal(t, expected, ascii(t, encoded.Bytes(), 0))
+	})
+
+	t.Run("when the character is not printable ascii, should draw a question mark", func(t *testing.T) {
+		expected, actual := bytes.Buffer{}, bytes.Buffer{}
+
+		_ = EncodeGIF(&expected, []GIFFrame{{Drawing: "?"}}, GIFOptions{Scale: 1})
+		err := EncodeGIF(&actual, []GIFFrame{{Drawing: "é"}}, GIFOptions{Scale: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, ascii(t, expected.Bytes(), 0), ascii(t, actual.Bytes(), 0))
+	})
+
+	t.Run("when a delay is informed, should replace the durations", func(t *testing.T) {
+		encoded := bytes.Buffer{}
+
+		err := EncodeGIF(&encoded, []GIFFrame{{Drawing: "a", Duration: 100}}, GIFOptions{Scale: 1, Delay: 1500})
+
+		assert.NoError(t, err)
+		decoded, _ := gif.DecodeAll(bytes.NewReader(encoded.Bytes()))
+		assert.Equal(t, []int{150}, decoded.Delay)
+	})
+
+	t.Run("when there are no frames, should return an error", func(t *testing.T) {
+		err := EncodeGIF(&bytes.Buffer{}, nil, DefaultGIFOptions())
+
+		assert.ErrorIs(t, err, ErrEmptyGIF)
+	})
+
+	t.Run("when the drawing is too large, should return an error", func(t *testing.T) {
+		drawing := strings.Repeat(strings.Repeat("a", 400)+"\n", 100)
+
+		err := EncodeGIF(&bytes.Buffer{}, []GIFFrame{{Drawing: drawing}}, GIFOptions{Scale: MaxScale})
+
+		assert.ErrorIs(t, err, ErrGIFTooLarge)
+	})
+}
+
+func TestGIFOptions_Validate(t *testing.T) {
+	assert.NoError(t, DefaultGIFOptions().Validate())
+	assert.ErrorIs(t, GIFOptions{Scale: 0}.Validate(), ErrInvalidScale)
+	assert.ErrorIs(t, GIFOptions{Scale: MaxScale + 1}.Validate(), ErrInvalidScale)
+	assert.ErrorIs(t, GIFOptions{Scale: 1, Delay: -1}.Validate(), ErrInvalidDelay)
+}
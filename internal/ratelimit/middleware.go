@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"sketch/internal/auth"
+	"sketch/internal/errors"
+	"sketch/internal/routing"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	HeaderLimit      = "X-RateLimit-Limit"
+	HeaderRemaining  = "X-RateLimit-Remaining"
+	HeaderReset      = "X-RateLimit-Reset"
+	HeaderRetryAfter = "Retry-After"
+)
+
+var (
+	ErrRateLimited  = errors.Error("too many requests, try again later")
+	ErrCostTooLarge = errors.Error("request asks for more than the rate limit ever allows")
+)
+
+// Cost tells how many tokens the request to the route takes.
+type Cost func(r *http.Request, params httprouter.Params) int
+
+// One is the cost of the requests all weighing the same.
+func One(*http.Request, httprouter.Params) int {
+	return 1
+}
+
+// Middleware refuses the requests of the clients out of tokens with a 429.
+// Every response tells the limit of the client, what is left of it and in how
+// many seconds it is full again. The requests costing more than the limit get
+// a 429 without Retry-After, as waiting does not help them. It must run after
+// the auth middleware.
+func Middleware(limiter *Limiter, cost Cost) routing.Middleware {
+	return func(next routing.Handle) routing.Handle {
+		if limiter == nil {
+			return next
+		}
+
+		return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+			result := limiter.Take(clientKey(r), cost(r, params))
+
+			w.Header().Set(HeaderLimit, strconv.Itoa(result.Limit))
+			w.Header().Set(HeaderRemaining, strconv.Itoa(result.Remaining))
+			w.Header().Set(HeaderReset, seconds(result.Reset))
+			if result.Exceeded {
+				return routing.TooManyRequests(w, ErrCostTooLarge)
+			}
+			if !result.Allowed {
+				w.Header().Set(HeaderRetryAfter, seconds(result.RetryAfter))
+				return routing.TooManyRequests(w, ErrRateLimited)
+			}
+
+			return next(w, r, params)
+		}
+	}
+}
+
+// Allow takes the cost from the bucket of the client of the request, for the
+// work it asks for once let in, like the messages of its websocket. Without a
+// limiter everything is allowed.
+func Allow(limiter *Limiter, r *http.Request, cost int) bool {
+	if limiter == nil {
+		return true
+	}
+	return limiter.Take(clientKey(r), cost).Allowed
+}
+
+// clientKey returns the API key of the request, or its IP address when it is
+// anonymous.
+func clientKey(r *http.Request) string {
+	if principal, ok := auth.FromContext(r.Context()); ok {
+		return "key:" + principal.KeyID
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+func seconds(duration time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(duration.Seconds())))
+}
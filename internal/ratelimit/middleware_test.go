@@ -0,0 +1,117 @@
+package ratelimit_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sketch/internal/auth"
+	"sketch/internal/ratelimit"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	next := func(w http.ResponseWriter, _ *http.Request, _ httprouter.Params) error {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	request := func(remoteAddr string, keyID string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/123", nil)
+		r.RemoteAddr = remoteAddr
+		if keyID != "" {
+			r = r.WithContext(auth.WithPrincipal(context.Background(), auth.Principal{KeyID: keyID, OwnerID: "owner"}))
+		}
+		return r
+	}
+
+	t.Run("when the client has tokens left, should handle the request and tell the limit", func(t *testing.T) {
+		handler := ratelimit.Middleware(ratelimit.NewLimiter(ratelimit.PerMinute(2)), ratelimit.One)(next)
+		w := httptest.NewRecorder()
+
+		err := handler(w, request("10.0.0.1:1234", ""), nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "2", w.Header().Get(ratelimit.HeaderLimit))
+		assert.Equal(t, "1", w.Header().Get(ratelimit.HeaderRemaining))
+		assert.Equal(t, "30", w.Header().Get(ratelimit.HeaderReset))
+	})
+
+	t.Run("when the client is out of tokens, should return too many requests", func(t *testing.T) {
+		handler := ratelimit.Middleware(ratelimit.NewLimiter(ratelimit.PerMinute(1)), ratelimit.One)(next)
+		_ = handler(httptest.NewRecorder(), request("10.0.0.1:1234", ""), nil)
+		w := httptest.NewRecorder()
+
+		err := handler(w, request("10.0.0.1:5678", ""), nil)
+
+		assert.ErrorIs(t, err, ratelimit.ErrRateLimited)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "60", w.Header().Get(ratelimit.HeaderRetryAfter))
+		assert.Equal(t, "0", w.Header().Get(ratelimit.HeaderRemaining))
+	})
+
+	t.Run("when the requests have an api key, should limit the key instead of the address", func(t *testing.T) {
+		handler := ratelimit.Middleware(ratelimit.NewLimiter(ratelimit.PerMinute(1)), ratelimit.One)(next)
+		_ = handler(httptest.NewRecorder(), request("10.0.0.1:1234", "1"), nil)
+		w := httptest.NewRecorder()
+
+		err := handler(w, request("10.0.0.1:1234", "2"), nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("when the request weighs more, should take as many tokens", func(t *testing.T) {
+		weight := func(*http.Request, httprouter.Params) int { return 5 }
+		handler := ratelimit.Middleware(ratelimit.NewLimiter(ratelimit.PerMinute(6)), weight)(next)
+		w := httptest.NewRecorder()
+
+		_ = handler(w, request("10.0.0.1:1234", ""), nil)
+
+		assert.Equal(t, "1", w.Header().Get(ratelimit.HeaderRemaining))
+	})
+
+	t.Run("when the request weighs more than the limit, should refuse it without telling to retry", func(t *testing.T) {
+		weight := func(*http.Request, httprouter.Params) int { return 7 }
+		handler := ratelimit.Middleware(ratelimit.NewLimiter(ratelimit.PerMinute(6)), weight)(next)
+		w := httptest.NewRecorder()
+
+		err := handler(w, request("10.0.0.1:1234", ""), nil)
+
+		assert.ErrorIs(t, err, ratelimit.ErrCostTooLarge)
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Empty(t, w.Header().Get(ratelimit.HeaderRetryAfter))
+		assert.Equal(t, "6", w.Header().Get(ratelimit.HeaderRemaining))
+	})
+
+	t.Run("when there is no limiter, should not limit", func(t *testing.T) {
+		handler := ratelimit.Middleware(nil, ratelimit.One)(next)
+		w := httptest.NewRecorder()
+
+		err := handler(w, request("10.0.0.1:1234", ""), nil)
+
+		assert.NoError(t, err)
+		assert.Empty(t, w.Header().Get(ratelimit.HeaderLimit))
+	})
+}
+
+func TestAllow(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/123", nil)
+
+	t.Run("when the client has the tokens, should take them", func(t *testing.T) {
+		limiter := ratelimit.NewLimiter(ratelimit.PerMinute(6))
+
+		assert.True(t, ratelimit.Allow(limiter, request, 5))
+		assert.False(t, ratelimit.Allow(limiter, request, 5))
+	})
+
+	t.Run("when the cost is above the limit, should refuse it", func(t *testing.T) {
+		assert.False(t, ratelimit.Allow(ratelimit.NewLimiter(ratelimit.PerMinute(6)), request, 7))
+	})
+
+	t.Run("when there is no limiter, should allow it", func(t *testing.T) {
+		assert.True(t, ratelimit.Allow(nil, request, 100))
+	})
+}
@@ -0,0 +1,127 @@
+// Package ratelimit limits how often the clients of the API call it, with a
+// token bucket for each API key, or for each IP address when the request is
+// anonymous.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the buckets refilled up to their burst, which
+// are no different from new ones, are dropped.
+const sweepInterval = time.Minute
+
+type (
+	// Limit lets Rate tokens a second into a bucket holding up to Burst.
+	Limit struct {
+		Rate  float64
+		Burst int
+	}
+
+	// Result tells whether a request could take its tokens, and how the bucket
+	// was left.
+	Result struct {
+		Allowed   bool
+		Limit     int
+		Remaining int
+		// RetryAfter is how long to wait before the request is allowed, when it
+		// is not.
+		RetryAfter time.Duration
+		// Exceeded tells the request costs more than the bucket ever holds, so
+		// no wait lets it in.
+		Exceeded bool
+		// Reset is how long until the bucket is full again.
+		Reset time.Duration
+	}
+
+	// Limiter keeps the buckets in memory, so each instance of the api limits
+	// the requests it receives on its own.
+	Limiter struct {
+		limit     Limit
+		now       func() time.Time
+		mutex     sync.Mutex
+		buckets   map[string]*bucket
+		lastSweep time.Time
+	}
+
+	bucket struct {
+		tokens    float64
+		updatedAt time.Time
+	}
+)
+
+// PerMinute lets up to n tokens a minute, all of them at once if needed.
+func PerMinute(n int) Limit {
+	return Limit{Rate: float64(n) / 60, Burst: n}
+}
+
+// NewLimiter returns nil, which allows everything, when the limit lets no
+// tokens in.
+func NewLimiter(limit Limit) *Limiter {
+	return newLimiter(limit, time.Now)
+}
+
+func newLimiter(limit Limit, now func() time.Time) *Limiter {
+	if limit.Rate <= 0 || limit.Burst <= 0 {
+		return nil
+	}
+
+	return &Limiter{
+		limit:     limit,
+		now:       now,
+		buckets:   make(map[string]*bucket),
+		lastSweep: now(),
+	}
+}
+
+// Take takes cost tokens from the bucket of the key, when it has them. A cost
+// above the burst is always refused, and takes nothing.
+func (l *Limiter) Take(key string, cost int) Result {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit.Burst), updatedAt: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(l.limit.Burst), b.tokens+now.Sub(b.updatedAt).Seconds()*l.limit.Rate)
+	b.updatedAt = now
+
+	result := Result{Limit: l.limit.Burst, Exceeded: cost > l.limit.Burst}
+	result.Allowed = !result.Exceeded && b.tokens >= float64(cost)
+	switch {
+	case result.Allowed:
+		b.tokens -= float64(cost)
+	case !result.Exceeded:
+		result.RetryAfter = l.wait(float64(cost) - b.tokens)
+	}
+
+	result.Remaining = int(b.tokens)
+	result.Reset = l.wait(float64(l.limit.Burst) - b.tokens)
+	return result
+}
+
+// wait returns how long the bucket takes to get the tokens.
+func (l *Limiter) wait(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / l.limit.Rate * float64(time.Second)))
+}
+
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.updatedAt).Seconds()*l.limit.Rate >= float64(l.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestLimiter_Take(t *testing.T) {
+	setup := func() (*Limiter, *fakeClock) {
+		clock := &fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+		return newLimiter(Limit{Rate: 1, Burst: 3}, clock.Now), clock
+	}
+
+	t.Run("when the bucket has the tokens, should take them", func(t *testing.T) {
+		limiter, _ := setup()
+
+		result := limiter.Take("a", 2)
+
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, 1, result.Remaining)
+		assert.Equal(t, 2*time.Second, result.Reset)
+	})
+
+	t.Run("when the bucket is out of tokens, should refuse and tell when to retry", func(t *testing.T) {
+		limiter, _ := setup()
+		limiter.Take("a", 3)
+
+		result := limiter.Take("a", 2)
+
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+		assert.Equal(t, 2*time.Second, result.RetryAfter)
+	})
+
+	t.Run("when time passes, should refill the bucket up to the burst", func(t *testing.T) {
+		limiter, clock := setup()
+		limiter.Take("a", 3)
+
+		clock.now = clock.now.Add(time.Hour)
+		result := limiter.Take("a", 1)
+
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2, result.Remaining)
+	})
+
+	t.Run("when the keys differ, should keep a bucket for each", func(t *testing.T) {
+		limiter, _ := setup()
+		limiter.Take("a", 3)
+
+		assert.True(t, limiter.Take("b", 3).Allowed)
+	})
+
+	t.Run("when the cost is above the burst, should refuse it for good and take nothing", func(t *testing.T) {
+		limiter, _ := setup()
+
+		result := limiter.Take("a", 4)
+
+		assert.False(t, result.Allowed)
+		assert.True(t, result.Exceeded)
+		assert.Zero(t, result.RetryAfter)
+		assert.Equal(t, 3, result.Remaining)
+		assert.True(t, limiter.Take("a", 3).Allowed)
+	})
+
+	t.Run("when the buckets are full again, should drop them", func(t *testing.T) {
+		limiter, clock := setup()
+		limiter.Take("a", 1)
+
+		clock.now = clock.now.Add(2 * sweepInterval)
+		limiter.Take("b", 1)
+
+		assert.NotContains(t, limiter.buckets, "a")
+		assert.Contains(t, limiter.buckets, "b")
+	})
+}
+
+func TestNewLimiter(t *testing.T) {
+	t.Run("when the limit lets no tokens in, should not limit", func(t *testing.T) {
+		assert.Nil(t, NewLimiter(PerMinute(0)))
+	})
+}
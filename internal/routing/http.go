@@ -21,3 +21,23 @@ func NotFound(w http.ResponseWriter, body error) error {
 	_ = ToJSON(w, http.StatusNotFound, body)
 	return body
 }
+
+func Unauthorized(w http.ResponseWriter, body error) error {
+	_ = ToJSON(w, http.StatusUnauthorized, body)
+	return body
+}
+
+func Forbidden(w http.ResponseWriter, body error) error {
+	_ = ToJSON(w, http.StatusForbidden, body)
+	return body
+}
+
+func Conflict(w http.ResponseWriter, body error) error {
+	_ = ToJSON(w, http.StatusConflict, body)
+	return body
+}
+
+func TooManyRequests(w http.ResponseWriter, body error) error {
+	_ = ToJSON(w, http.StatusTooManyRequests, body)
+	return body
+}
@@ -8,12 +8,25 @@ import (
 	"net/http"
 	"os"
 	"sketch/internal/errors"
+	"strings"
 )
 
+// Router wraps httprouter, which refuses a static segment and a parameter at
+// the same position (e.g. /symbols and /:id). Paths starting with a static
+// segment are registered in their own router, picked before the root one.
 type Router struct {
-	router *httprouter.Router
+	router      *httprouter.Router
+	prefixes    map[string]*httprouter.Router
+	middlewares []Middleware
 }
 
+type (
+	Handle func(http.ResponseWriter, *http.Request, httprouter.Params) error
+
+	// Middleware wraps a handler, to run before or after it or instead of it.
+	Middleware func(Handle) Handle
+)
+
 type ErrorResult struct {
 	Message string `json:"message"`
 }
@@ -41,25 +54,71 @@ func errorHandler(w http.ResponseWriter, err error) {
 
 func NewRouter() *Router {
 	return &Router{
-		router: httprouter.New(),
+		router:   httprouter.New(),
+		prefixes: make(map[string]*httprouter.Router),
 	}
 }
 
-func (r *Router) Get(path string, handler func(http.ResponseWriter, *http.Request, httprouter.Params) error) {
-	r.router.GET(path, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-		err := handler(writer, request, params)
-		errorHandler(writer, err)
-	})
+func (r *Router) routerFor(path string) *httprouter.Router {
+	segment := firstSegment(path)
+	if segment == "" || strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+		return r.router
+	}
+
+	router, ok := r.prefixes[segment]
+	if !ok {
+		router = httprouter.New()
+		r.prefixes[segment] = router
+	}
+	return router
+}
+
+func firstSegment(path string) string {
+	segment := strings.TrimPrefix(path, "/")
+	if i := strings.Index(segment, "/"); i >= 0 {
+		segment = segment[:i]
+	}
+	return segment
+}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if router, ok := r.prefixes[firstSegment(req.URL.Path)]; ok {
+		router.ServeHTTP(w, req)
+		return
+	}
+	r.router.ServeHTTP(w, req)
+}
+
+func (r *Router) Get(path string, handler Handle) {
+	r.handle(http.MethodGet, path, handler)
+}
+
+func (r *Router) Post(path string, handler Handle) {
+	r.handle(http.MethodPost, path, handler)
+}
+
+func (r *Router) Delete(path string, handler Handle) {
+	r.handle(http.MethodDelete, path, handler)
+}
+
+// Use wraps every handler of the router with the middleware, routes already
+// registered included. The first middleware used runs first.
+func (r *Router) Use(middleware Middleware) {
+	r.middlewares = append(r.middlewares, middleware)
 }
 
-func (r *Router) Post(path string, handler func(http.ResponseWriter, *http.Request, httprouter.Params) error) {
-	r.router.POST(path, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-		err := handler(writer, request, params)
+func (r *Router) handle(method, path string, handler Handle) {
+	r.routerFor(path).Handle(method, path, func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		wrapped := handler
+		for i := len(r.middlewares) - 1; i >= 0; i-- {
+			wrapped = r.middlewares[i](wrapped)
+		}
+		err := wrapped(writer, request, params)
 		errorHandler(writer, err)
 	})
 }
 
 func (r *Router) Run() {
 	port := os.Getenv("APP_PORT")
-	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%s", port), r.router))
+	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%s", port), r))
 }
@@ -0,0 +1,72 @@
+package routing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sketch/internal/routing"
+	"testing"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter_StaticAndParameterSegments(t *testing.T) {
+	router := routing.NewRouter()
+	handler := func(name string) func(http.ResponseWriter, *http.Request, httprouter.Params) error {
+		return func(w http.ResponseWriter, _ *http.Request, params httprouter.Params) error {
+			return routing.ToJSON(w, http.StatusOK, name+params.ByName("id")+params.ByName("name"))
+		}
+	}
+
+	router.Get("/:id", handler("canvas "))
+	router.Post("/:id/crop", handler("crop "))
+	router.Post("/symbols", handler("symbols"))
+	router.Get("/symbols/:name", handler("symbol "))
+
+	tests := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{method: http.MethodGet, path: "/123", expected: "\"canvas 123\"\n"},
+		{method: http.MethodPost, path: "/123/crop", expected: "\"crop 123\"\n"},
+		{method: http.MethodPost, path: "/symbols", expected: "\"symbols\"\n"},
+		{method: http.MethodGet, path: "/symbols/server", expected: "\"symbol server\"\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tc.expected, w.Body.String())
+		})
+	}
+}
+
+func TestRouter_Use(t *testing.T) {
+	var calls []string
+	middleware := func(name string) routing.Middleware {
+		return func(next routing.Handle) routing.Handle {
+			return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+				calls = append(calls, name)
+				return next(w, r, params)
+			}
+		}
+	}
+
+	router := routing.NewRouter()
+	router.Use(middleware("first"))
+	router.Get("/:id", func(w http.ResponseWriter, _ *http.Request, params httprouter.Params) error {
+		calls = append(calls, "handler")
+		return routing.ToJSON(w, http.StatusOK, params.ByName("id"))
+	})
+	router.Use(middleware("second"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/123", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"first", "second", "handler"}, calls)
+}
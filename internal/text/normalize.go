@@ -0,0 +1,65 @@
+package text
+
+import (
+	"sketch/internal/errors"
+	"strings"
+	"unicode"
+)
+
+const (
+	DefaultTabSize = 8
+)
+
+var (
+	ErrEmptyText        = errors.Error("text must have at least one visible character")
+	ErrControlCharacter = errors.Error("text must not contain control characters other than tabs and new lines")
+	ErrInvalidTabSize   = errors.Error("tab size must be between 1 and 16")
+)
+
+// ExpandTabs replaces every tab with the spaces needed to reach the next tab
+// stop.
+func ExpandTabs(line string, tabSize int) string {
+	result := strings.Builder{}
+	column := 0
+	for _, char := range line {
+		if char != '\t' {
+			result.WriteRune(char)
+			column++
+			continue
+		}
+
+		spaces := tabSize - column%tabSize
+		result.WriteString(strings.Repeat(" ", spaces))
+		column += spaces
+	}
+	return result.String()
+}
+
+// Normalize prepares hand-made text art to be stored: line endings become
+// "\n", tabs are expanded, trailing whitespace and trailing blank lines are
+// removed.
+func Normalize(raw string, tabSize int) (string, error) {
+	if tabSize < 1 || tabSize > 16 {
+		return "", ErrInvalidTabSize
+	}
+
+	raw = strings.ReplaceAll(raw, "\r\n", "\n")
+	lines := strings.Split(raw, "\n")
+	for i, line := range lines {
+		for _, char := range line {
+			if unicode.IsControl(char) && char != '\t' {
+				return "", ErrControlCharacter
+			}
+		}
+		lines[i] = strings.TrimRightFunc(ExpandTabs(line, tabSize), unicode.IsSpace)
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		return "", ErrEmptyText
+	}
+	return strings.Join(lines, "\n"), nil
+}
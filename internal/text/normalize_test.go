@@ -0,0 +1,73 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandTabs(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		tabSize  int
+		expected string
+	}{
+		{name: "when there are no tabs, should keep the line", line: "abc", tabSize: 4, expected: "abc"},
+		{name: "when the tab starts the line, should fill a whole stop", line: "\tx", tabSize: 4, expected: "    x"},
+		{name: "when the tab is in the middle, should reach the next stop", line: "ab\tx", tabSize: 4, expected: "ab  x"},
+		{name: "when the tab is right at a stop, should fill a whole stop", line: "abcd\tx", tabSize: 4, expected: "abcd    x"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ExpandTabs(tc.line, tc.tabSize))
+		})
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		raw    string
+		assert func(t *testing.T, got string, err error)
+	}{
+		{
+			name: "when the text has windows line endings and trailing spaces, should normalize them",
+			raw:  "+--+  \r\n|\t|\r\n+--+\r\n\r\n",
+			assert: func(t *testing.T, got string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "+--+\n|       |\n+--+", got)
+			},
+		},
+		{
+			name: "when the text has leading blank lines, should keep them",
+			raw:  "\n  x",
+			assert: func(t *testing.T, got string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "\n  x", got)
+			},
+		},
+		{
+			name: "when the text is blank, should return an error",
+			raw:  " \n\t\n",
+			assert: func(t *testing.T, got string, err error) {
+				assert.ErrorIs(t, err, ErrEmptyText)
+			},
+		},
+		{
+			name: "when the text has control characters, should return an error",
+			raw:  "a\x1b[31mb",
+			assert: func(t *testing.T, got string, err error) {
+				assert.ErrorIs(t, err, ErrControlCharacter)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Normalize(tc.raw, DefaultTabSize)
+			tc.assert(t, got, err)
+		})
+	}
+}
@@ -0,0 +1,439 @@
+// Package websocket implements the parts of RFC 6455 the API needs: the
+// opening handshake, text and binary messages, fragmentation, ping and pong
+// and the closing handshake. Extensions and subprotocols are not supported.
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sketch/internal/errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	TextMessage   MessageType = 1
+	BinaryMessage MessageType = 2
+
+	opContinuation = 0x0
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+
+	CloseNormal          = 1000
+	CloseProtocolError   = 1002
+	CloseMessageTooLarge = 1009
+
+	// MaxMessageSize is the largest message, after joining its fragments,
+	// that is read.
+	MaxMessageSize = 1 << 20
+
+	acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+	// writeTimeout is how long a frame may take to be written, so that a peer
+	// no longer reading does not hold the writer forever.
+	writeTimeout = 10 * time.Second
+)
+
+var (
+	ErrNotWebSocket       = errors.Error("the request is not a websocket handshake")
+	ErrUnsupportedVersion = errors.Error("only websocket version 13 is supported")
+	ErrHandshakeFailed    = errors.Error("websocket handshake failed")
+	ErrForbiddenOrigin    = errors.Error("the origin of the websocket handshake is not allowed")
+	ErrProtocol           = errors.Error("websocket protocol error")
+	ErrMessageTooLarge    = errors.Error(fmt.Sprintf("websocket messages must have at most %d bytes", MaxMessageSize))
+	ErrClosed             = errors.Error("websocket closed")
+)
+
+type (
+	MessageType int
+
+	frame struct {
+		fin     bool
+		opcode  byte
+		payload []byte
+	}
+
+	// Conn is a websocket connection. Reads must happen from a single
+	// goroutine, writes may happen from many.
+	Conn struct {
+		conn   net.Conn
+		reader *bufio.Reader
+		// client connections mask what they write and expect unmasked frames.
+		client bool
+		// readTimeout is how long to wait for the next frame, forever when it
+		// is zero.
+		readTimeout time.Duration
+
+		mu     sync.Mutex
+		closed bool
+	}
+)
+
+// Upgrade answers the opening handshake and takes over the connection. The
+// response must not be used afterwards, unless an error is returned. Browsers
+// open websockets from any page, so handshakes from another origin than the
+// host are refused unless the origin is one of the allowed ones, or these
+// have "*".
+func Upgrade(w http.ResponseWriter, r *http.Request, allowedOrigins []string) (*Conn, error) {
+	if r.Method != http.MethodGet ||
+		!hasToken(r.Header.Get("Connection"), "upgrade") ||
+		!hasToken(r.Header.Get("Upgrade"), "websocket") {
+		return nil, ErrNotWebSocket
+	}
+
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		return nil, ErrUnsupportedVersion
+	}
+
+	if !isAllowedOrigin(r, allowedOrigins) {
+		return nil, ErrForbiddenOrigin
+	}
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		return nil, ErrNotWebSocket
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, fmt.Errorf("%w: the connection can not be hijacked", ErrHandshakeFailed)
+	}
+
+	conn, buffered, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrHandshakeFailed, err)
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %s", ErrHandshakeFailed, err)
+	}
+
+	return &Conn{conn: conn, reader: buffered.Reader}, nil
+}
+
+// Dial opens a client connection to a ws:// url.
+func Dial(rawURL string) (*Conn, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || target.Scheme != "ws" {
+		return nil, fmt.Errorf("%w: only ws:// urls are supported", ErrHandshakeFailed)
+	}
+
+	host := target.Host
+	if target.Port() == "" {
+		host += ":80"
+	}
+	conn, err := net.Dial("tcp", host)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrHandshakeFailed, err)
+	}
+
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	request := fmt.Sprintf("GET %s HTTP/1.1\r\n"+
+		"Host: %s\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: %s\r\n"+
+		"Sec-WebSocket-Version: 13\r\n\r\n", target.RequestURI(), target.Host, key)
+	if _, err := conn.Write([]byte(request)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %s", ErrHandshakeFailed, err)
+	}
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %s", ErrHandshakeFailed, err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusSwitchingProtocols || response.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		conn.Close()
+		return nil, fmt.Errorf("%w: status %d", ErrHandshakeFailed, response.StatusCode)
+	}
+
+	return &Conn{conn: conn, reader: reader, client: true}, nil
+}
+
+// isAllowedOrigin allows the handshakes without an Origin, which only
+// browsers send, those from the host itself and those from the allowed
+// origins.
+func isAllowedOrigin(r *http.Request, allowedOrigins []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(parsed.Host, r.Host) {
+		return true
+	}
+
+	for _, allowed := range allowedOrigins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func acceptKey(key string) string {
+	hash := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+func hasToken(header, token string) bool {
+	for _, value := range strings.Split(header, ",") {
+		if strings.EqualFold(strings.TrimSpace(value), token) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadMessage returns the next data message, joining its fragments. Pings are
+// answered while waiting for it. When the peer closes the connection, the
+// close is answered and ErrClosed is returned.
+func (c *Conn) ReadMessage() (MessageType, []byte, error) {
+	var (
+		messageType MessageType
+		fragmented  bool
+	)
+	message := make([]byte, 0)
+
+	for {
+		if c.readTimeout > 0 {
+			_ = c.conn.SetReadDeadline(time.Now().Add(c.readTimeout))
+		}
+
+		f, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch f.opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, f.payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			code := CloseNormal
+			if len(f.payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(f.payload))
+			}
+			_ = c.CloseWith(code)
+			return 0, nil, ErrClosed
+		case opContinuation:
+			if !fragmented {
+				return 0, nil, c.fail(CloseProtocolError, ErrProtocol)
+			}
+		case byte(TextMessage), byte(BinaryMessage):
+			if fragmented {
+				return 0, nil, c.fail(CloseProtocolError, ErrProtocol)
+			}
+			messageType, fragmented = MessageType(f.opcode), true
+		default:
+			return 0, nil, c.fail(CloseProtocolError, ErrProtocol)
+		}
+
+		if len(message)+len(f.payload) > MaxMessageSize {
+			return 0, nil, c.fail(CloseMessageTooLarge, ErrMessageTooLarge)
+		}
+		message = append(message, f.payload...)
+		if f.fin {
+			return messageType, message, nil
+		}
+	}
+}
+
+func (c *Conn) readFrame() (frame, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		return frame{}, c.lost(err)
+	}
+
+	f := frame{fin: header[0]&0x80 != 0, opcode: header[0] & 0x0f}
+	if header[0]&0x70 != 0 {
+		return f, c.fail(CloseProtocolError, ErrProtocol)
+	}
+
+	masked := header[1]&0x80 != 0
+	if masked == c.client {
+		return f, c.fail(CloseProtocolError, ErrProtocol)
+	}
+
+	length := uint64(header[1] & 0x7f)
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		if _, err := io.ReadFull(c.reader, extended); err != nil {
+			return f, c.lost(err)
+		}
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		if _, err := io.ReadFull(c.reader, extended); err != nil {
+			return f, c.lost(err)
+		}
+		length = binary.BigEndian.Uint64(extended)
+	}
+
+	if f.opcode >= opClose && (length > 125 || !f.fin) {
+		return f, c.fail(CloseProtocolError, ErrProtocol)
+	}
+	if length > MaxMessageSize {
+		return f, c.fail(CloseMessageTooLarge, ErrMessageTooLarge)
+	}
+
+	mask := make([]byte, 4)
+	if masked {
+		if _, err := io.ReadFull(c.reader, mask); err != nil {
+			return f, c.lost(err)
+		}
+	}
+
+	f.payload = make([]byte, length)
+	if _, err := io.ReadFull(c.reader, f.payload); err != nil {
+		return f, c.lost(err)
+	}
+	if masked {
+		for i := range f.payload {
+			f.payload[i] ^= mask[i%4]
+		}
+	}
+	return f, nil
+}
+
+// WriteMessage sends the data as a single frame.
+func (c *Conn) WriteMessage(messageType MessageType, data []byte) error {
+	return c.writeFrame(byte(messageType), data)
+}
+
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	return c.writeRaw(true, opcode, payload)
+}
+
+// writeRaw sends a frame, which is a fragment when fin is false.
+func (c *Conn) writeRaw(fin bool, opcode byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+
+	header := []byte{opcode, 0}
+	if fin {
+		header[0] |= 0x80
+	}
+	switch length := len(payload); {
+	case length <= 125:
+		header[1] = byte(length)
+	case length <= 0xffff:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	if c.client {
+		header[1] |= 0x80
+		mask := make([]byte, 4)
+		_, _ = rand.Read(mask)
+		header = append(header, mask...)
+
+		masked := make([]byte, len(payload))
+		for i := range payload {
+			masked[i] = payload[i] ^ mask[i%4]
+		}
+		payload = masked
+	}
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		return fmt.Errorf("failed to write websocket frame: %w", err)
+	}
+	return nil
+}
+
+// KeepAlive pings the peer every interval until the connection closes, and
+// closes it once nothing, pongs included, was read for two intervals, so that
+// dead peers are noticed. It must be called before reading.
+func (c *Conn) KeepAlive(interval time.Duration) {
+	c.readTimeout = 2 * interval
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := c.writeFrame(opPing, nil); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+// Close sends a normal close and closes the connection.
+func (c *Conn) Close() error {
+	return c.CloseWith(CloseNormal)
+}
+
+// CloseWith sends a close with the status code and closes the connection,
+// without waiting for the peer to answer.
+func (c *Conn) CloseWith(code int) error {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	_ = c.writeFrame(opClose, payload)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.conn.Close()
+}
+
+// fail closes the connection with the code and returns the error.
+func (c *Conn) fail(code int, err error) error {
+	_ = c.CloseWith(code)
+	return err
+}
+
+// lost closes a connection that can no longer be read from.
+func (c *Conn) lost(err error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		c.conn.Close()
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrClosed
+	}
+	return fmt.Errorf("%w: %s", ErrClosed, err)
+}
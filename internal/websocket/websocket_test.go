@@ -0,0 +1,222 @@
+package websocket
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newEchoServer sends back every message it reads until the connection ends.
+func newEchoServer(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r, nil)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for {
+			messageType, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			_ = conn.WriteMessage(messageType, message)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestConn_Echo(t *testing.T) {
+	url := newEchoServer(t)
+
+	t.Run("when messages have different lengths, should read them back", func(t *testing.T) {
+		conn, err := Dial(url)
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		for _, length := range []int{0, 125, 126, 70000} {
+			message := []byte(strings.Repeat("a", length))
+			assert.NoError(t, conn.WriteMessage(TextMessage, message))
+
+			messageType, read, err := conn.ReadMessage()
+			assert.NoError(t, err)
+			assert.Equal(t, TextMessage, messageType)
+			assert.Equal(t, message, read)
+		}
+	})
+
+	t.Run("when a message is fragmented, should join its fragments", func(t *testing.T) {
+		conn, err := Dial(url)
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		assert.NoError(t, conn.writeRaw(false, byte(BinaryMessage), []byte("he")))
+		assert.NoError(t, conn.writeFrame(opPing, []byte("ping")))
+		assert.NoError(t, conn.writeRaw(true, opContinuation, []byte("llo")))
+
+		messageType, read, err := conn.ReadMessage()
+		assert.NoError(t, err)
+		assert.Equal(t, BinaryMessage, messageType)
+		assert.Equal(t, []byte("hello"), read)
+	})
+
+	t.Run("when the server closes, should return closed", func(t *testing.T) {
+		conn, err := Dial(url)
+		assert.NoError(t, err)
+
+		assert.NoError(t, conn.writeFrame(opClose, []byte{0x03, 0xe8}))
+
+		_, _, err = conn.ReadMessage()
+		assert.ErrorIs(t, err, ErrClosed)
+	})
+
+	t.Run("when a continuation starts a message, should fail the connection", func(t *testing.T) {
+		conn, err := Dial(url)
+		assert.NoError(t, err)
+
+		assert.NoError(t, conn.writeRaw(true, opContinuation, []byte("a")))
+
+		_, _, err = conn.ReadMessage()
+		assert.ErrorIs(t, err, ErrClosed)
+	})
+}
+
+func TestConn_KeepAlive(t *testing.T) {
+	// newServer keeps the connection alive with the interval and reports what
+	// ends its read.
+	newServer := func(t *testing.T, interval time.Duration) (string, <-chan error) {
+		t.Helper()
+		ended := make(chan error, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := Upgrade(w, r, nil)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			conn.KeepAlive(interval)
+			_, _, err = conn.ReadMessage()
+			ended <- err
+		}))
+		t.Cleanup(server.Close)
+		return "ws" + strings.TrimPrefix(server.URL, "http"), ended
+	}
+
+	t.Run("when the peer answers the pings, should keep the connection open", func(t *testing.T) {
+		url, ended := newServer(t, 10*time.Millisecond)
+		conn, err := Dial(url)
+		assert.NoError(t, err)
+		defer conn.Close()
+		go func() {
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		time.Sleep(100 * time.Millisecond)
+		assert.NoError(t, conn.WriteMessage(TextMessage, []byte("still here")))
+
+		assert.NoError(t, <-ended)
+	})
+
+	t.Run("when the peer stops answering, should close the connection", func(t *testing.T) {
+		url, ended := newServer(t, 10*time.Millisecond)
+		conn, err := Dial(url)
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		select {
+		case err := <-ended:
+			assert.ErrorIs(t, err, ErrClosed)
+		case <-time.After(time.Second):
+			t.Fatal("the connection was kept open")
+		}
+	})
+}
+
+func TestUpgrade(t *testing.T) {
+	newRequest := func() *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Connection", "keep-alive, Upgrade")
+		r.Header.Set("Upgrade", "websocket")
+		r.Header.Set("Sec-WebSocket-Version", "13")
+		r.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		return r
+	}
+
+	t.Run("when it is not an upgrade, should return an error", func(t *testing.T) {
+		r := newRequest()
+		r.Header.Del("Upgrade")
+
+		_, err := Upgrade(httptest.NewRecorder(), r, nil)
+
+		assert.ErrorIs(t, err, ErrNotWebSocket)
+	})
+
+	t.Run("when the version is not 13, should return an error", func(t *testing.T) {
+		r := newRequest()
+		r.Header.Set("Sec-WebSocket-Version", "8")
+		w := httptest.NewRecorder()
+
+		_, err := Upgrade(w, r, nil)
+
+		assert.ErrorIs(t, err, ErrUnsupportedVersion)
+		assert.Equal(t, "13", w.Header().Get("Sec-WebSocket-Version"))
+	})
+
+	t.Run("when the key is not 16 bytes, should return an error", func(t *testing.T) {
+		r := newRequest()
+		r.Header.Set("Sec-WebSocket-Key", "c2hvcnQ=")
+
+		_, err := Upgrade(httptest.NewRecorder(), r, nil)
+
+		assert.ErrorIs(t, err, ErrNotWebSocket)
+	})
+
+	t.Run("when the origin is another site, should refuse it", func(t *testing.T) {
+		r := newRequest()
+		r.Header.Set("Origin", "https://evil.example")
+
+		_, err := Upgrade(httptest.NewRecorder(), r, []string{"https://sketch.example"})
+
+		assert.ErrorIs(t, err, ErrForbiddenOrigin)
+	})
+}
+
+func TestIsAllowedOrigin(t *testing.T) {
+	tests := []struct {
+		name     string
+		origin   string
+		allowed  []string
+		expected bool
+	}{
+		{name: "when there is no origin, should allow it", expected: true},
+		{name: "when the origin is the host, should allow it", origin: "http://example.com", expected: true},
+		{name: "when the origin is allowed, should allow it", origin: "https://sketch.example", allowed: []string{"https://sketch.example/"}, expected: true},
+		{name: "when every origin is allowed, should allow it", origin: "https://evil.example", allowed: []string{"*"}, expected: true},
+		{name: "when the origin is another site, should refuse it", origin: "https://evil.example", allowed: []string{"https://sketch.example"}},
+		{name: "when the origin only differs in the scheme, should refuse it", origin: "http://sketch.example", allowed: []string{"https://sketch.example"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.origin != "" {
+				r.Header.Set("Origin", tc.origin)
+			}
+
+			assert.Equal(t, tc.expected, isAllowedOrigin(r, tc.allowed))
+		})
+	}
+}
+
+func TestAcceptKey(t *testing.T) {
+	// The example of RFC 6455, section 1.3.
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", acceptKey("dGhlIHNhbXBsZSBub25jZQ=="))
+}
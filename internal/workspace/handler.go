@@ -0,0 +1,91 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sketch/internal/routing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (c *Handler) Save(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+	request, err := routing.FromJSON[WorkspaceRequest](r)
+	if err != nil {
+		return fmt.Errorf("failed to get json body: %w", err)
+	}
+
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	workspace, err := c.service.Create(r.Context(), request)
+	if err := scopeError(w, err); err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, workspace)
+}
+
+// List returns the workspaces the owner is a member of, with its role.
+func (c *Handler) List(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+	workspaces, err := c.service.List(r.Context())
+	if err := scopeError(w, err); err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, workspaces)
+}
+
+func (c *Handler) Members(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	members, err := c.service.GetMembers(r.Context(), params.ByName("id"))
+	if err := scopeError(w, err); err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, members)
+}
+
+// SaveMember adds a member to the workspace, or changes its role.
+func (c *Handler) SaveMember(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	request, err := routing.FromJSON[MemberRequest](r)
+	if err != nil {
+		return fmt.Errorf("failed to get json body: %w", err)
+	}
+
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	member, err := c.service.SaveMember(r.Context(), params.ByName("id"), request)
+	if err := scopeError(w, err); err != nil {
+		return err
+	}
+
+	return routing.ToJSON(w, http.StatusOK, member)
+}
+
+func (c *Handler) RemoveMember(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+	err := c.service.RemoveMember(r.Context(), params.ByName("id"), params.ByName("owner"))
+
+	if errors.Is(err, ErrMemberNotFound) {
+		return routing.NotFound(w, err)
+	}
+
+	if err := scopeError(w, err); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
@@ -0,0 +1,54 @@
+package workspace
+
+import (
+	"errors"
+	"net/http"
+	"sketch/internal/auth"
+	"sketch/internal/routing"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	HeaderWorkspace = "X-Workspace-ID"
+	// queryWorkspace is for the pages, which cannot send headers.
+	queryWorkspace = "workspace"
+)
+
+// Middleware puts the requests naming a workspace in its scope, once it knows
+// their owner is a member. It must run after the auth middleware.
+func Middleware(service Service) routing.Middleware {
+	return func(next routing.Handle) routing.Handle {
+		return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) error {
+			id := r.Header.Get(HeaderWorkspace)
+			if id == "" {
+				id = r.URL.Query().Get(queryWorkspace)
+			}
+
+			if id == "" {
+				return next(w, r, params)
+			}
+
+			scope, err := service.Resolve(r.Context(), id)
+			if err := scopeError(w, err); err != nil {
+				return err
+			}
+
+			return next(w, r.WithContext(WithScope(r.Context(), scope)), params)
+		}
+	}
+}
+
+// scopeError writes the response of the errors telling the request is not
+// allowed in the workspace, and returns the error.
+func scopeError(w http.ResponseWriter, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, auth.ErrKeyRequired):
+		return routing.Unauthorized(w, err)
+	case errors.Is(err, ErrNotMember), errors.Is(err, ErrForbidden):
+		return routing.Forbidden(w, err)
+	}
+	return err
+}
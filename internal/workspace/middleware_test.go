@@ -0,0 +1,76 @@
+package workspace_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sketch/internal/workspace"
+	mock_workspace "sketch/internal/workspace/mocks"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	next := func(w http.ResponseWriter, r *http.Request, _ httprouter.Params) error {
+		_, _ = w.Write([]byte(workspace.FromContext(r.Context()).Role))
+		return nil
+	}
+
+	t.Run("when the request names a workspace of the owner, should scope it", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_workspace.NewMockService(ctrl)
+		req := httptest.NewRequest(http.MethodGet, "/123", nil)
+		req.Header.Set("X-Workspace-ID", "ws")
+
+		serviceMock.EXPECT().Resolve(gomock.Any(), "ws").Return(workspace.Scope{WorkspaceID: "ws", Role: workspace.RoleEditor}, nil)
+
+		err := workspace.Middleware(serviceMock)(next)(w, req, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "editor", w.Body.String())
+	})
+
+	t.Run("when the page names a workspace in the query, should scope it", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_workspace.NewMockService(ctrl)
+		req := httptest.NewRequest(http.MethodGet, "/?id=123&workspace=ws", nil)
+
+		serviceMock.EXPECT().Resolve(gomock.Any(), "ws").Return(workspace.Scope{WorkspaceID: "ws", Role: workspace.RoleViewer}, nil)
+
+		err := workspace.Middleware(serviceMock)(next)(w, req, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "viewer", w.Body.String())
+	})
+
+	t.Run("when the owner is not a member, should return forbidden", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_workspace.NewMockService(ctrl)
+		req := httptest.NewRequest(http.MethodGet, "/123", nil)
+		req.Header.Set("X-Workspace-ID", "ws")
+
+		serviceMock.EXPECT().Resolve(gomock.Any(), "ws").Return(workspace.Scope{}, workspace.ErrNotMember)
+
+		err := workspace.Middleware(serviceMock)(next)(w, req, nil)
+
+		assert.ErrorIs(t, err, workspace.ErrNotMember)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("when the request names no workspace, should not scope it", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		ctrl := gomock.NewController(t)
+		serviceMock := mock_workspace.NewMockService(ctrl)
+		req := httptest.NewRequest(http.MethodGet, "/123", nil)
+
+		err := workspace.Middleware(serviceMock)(next)(w, req, nil)
+
+		assert.NoError(t, err)
+		assert.Empty(t, w.Body.String())
+	})
+}
@@ -0,0 +1,116 @@
+package workspace
+
+import (
+	"context"
+	"database/sql"
+	goerrors "errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type (
+	Repository interface {
+		// Save creates the workspace with its first member.
+		Save(ctx context.Context, workspace Workspace, admin Member) error
+		GetByMember(ctx context.Context, ownerID string) ([]Workspace, error)
+		GetMember(ctx context.Context, workspaceID string, ownerID string) (Member, error)
+		GetMembers(ctx context.Context, workspaceID string) ([]Member, error)
+		// SaveMember adds the member, or changes its role when it already is one.
+		SaveMember(ctx context.Context, member Member) error
+		DeleteMember(ctx context.Context, workspaceID string, ownerID string) error
+	}
+
+	repository struct {
+		db *sqlx.DB
+	}
+)
+
+func NewRepository(db *sqlx.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Save(ctx context.Context, workspace Workspace, admin Member) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	defer tx.Rollback()
+
+	const query = "insert into workspaces (id, name, created_at) values (:id, :name, :created_at)"
+	if _, err := tx.NamedExecContext(ctx, query, workspace); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	if err := r.saveMember(ctx, tx, admin); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) GetByMember(ctx context.Context, ownerID string) ([]Workspace, error) {
+	const query = "select w.id, w.name, m.role, w.created_at from workspaces w " +
+		"join workspace_members m on m.workspace_id = w.id where m.owner_id = $1 order by w.created_at"
+	workspaces := make([]Workspace, 0)
+	if err := r.db.SelectContext(ctx, &workspaces, query, ownerID); err != nil {
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+	return workspaces, nil
+}
+
+func (r *repository) GetMember(ctx context.Context, workspaceID string, ownerID string) (Member, error) {
+	const query = "select workspace_id, owner_id, role, created_at from workspace_members " +
+		"where workspace_id = $1 and owner_id = $2"
+	var member Member
+	if err := r.db.GetContext(ctx, &member, query, workspaceID, ownerID); err != nil {
+		if goerrors.Is(err, sql.ErrNoRows) {
+			return member, ErrMemberNotFound
+		}
+
+		return member, fmt.Errorf("database err: %w", err)
+	}
+	return member, nil
+}
+
+func (r *repository) GetMembers(ctx context.Context, workspaceID string) ([]Member, error) {
+	const query = "select workspace_id, owner_id, role, created_at from workspace_members " +
+		"where workspace_id = $1 order by created_at"
+	members := make([]Member, 0)
+	if err := r.db.SelectContext(ctx, &members, query, workspaceID); err != nil {
+		return nil, fmt.Errorf("database err: %w", err)
+	}
+	return members, nil
+}
+
+func (r *repository) SaveMember(ctx context.Context, member Member) error {
+	return r.saveMember(ctx, r.db, member)
+}
+
+func (r *repository) saveMember(ctx context.Context, e sqlx.ExtContext, member Member) error {
+	const query = "insert into workspace_members (workspace_id, owner_id, role, created_at) " +
+		"values (:workspace_id, :owner_id, :role, :created_at) " +
+		"on conflict (workspace_id, owner_id) do update set role = excluded.role"
+	if _, err := sqlx.NamedExecContext(ctx, e, query, member); err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+	return nil
+}
+
+func (r *repository) DeleteMember(ctx context.Context, workspaceID string, ownerID string) error {
+	const query = "delete from workspace_members where workspace_id = $1 and owner_id = $2"
+	result, err := r.db.ExecContext(ctx, query, workspaceID, ownerID)
+	if err != nil {
+		return fmt.Errorf("database err: %w", err)
+	}
+
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrMemberNotFound
+	}
+	return nil
+}
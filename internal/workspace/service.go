@@ -0,0 +1,146 @@
+package workspace
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"sketch/internal/auth"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	Service interface {
+		// Create makes a workspace with the owner of the request as its admin.
+		Create(ctx context.Context, request WorkspaceRequest) (*Workspace, error)
+		List(ctx context.Context) ([]Workspace, error)
+		GetMembers(ctx context.Context, id string) ([]Member, error)
+		SaveMember(ctx context.Context, id string, request MemberRequest) (*Member, error)
+		RemoveMember(ctx context.Context, id string, ownerID string) error
+		// Resolve returns the scope of the request in the workspace.
+		Resolve(ctx context.Context, id string) (Scope, error)
+	}
+
+	service struct {
+		repository Repository
+	}
+)
+
+func NewService(repository Repository) Service {
+	return &service{
+		repository: repository,
+	}
+}
+
+func (s service) Create(ctx context.Context, request WorkspaceRequest) (*Workspace, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, auth.ErrKeyRequired
+	}
+
+	workspace := Workspace{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(request.Name),
+		Role:      RoleAdmin,
+		CreatedAt: time.Now().UTC(),
+	}
+	admin := Member{WorkspaceID: workspace.ID, OwnerID: principal.OwnerID, Role: RoleAdmin, CreatedAt: workspace.CreatedAt}
+	if err := s.repository.Save(ctx, workspace, admin); err != nil {
+		return nil, fmt.Errorf("error saving workspace: %w", err)
+	}
+	return &workspace, nil
+}
+
+func (s service) List(ctx context.Context) ([]Workspace, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, auth.ErrKeyRequired
+	}
+
+	workspaces, err := s.repository.GetByMember(ctx, principal.OwnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	return workspaces, nil
+}
+
+func (s service) GetMembers(ctx context.Context, id string) ([]Member, error) {
+	if _, err := s.authorize(ctx, id, RoleViewer); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repository.GetMembers(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get members of '%s': %w", id, err)
+	}
+	return members, nil
+}
+
+func (s service) SaveMember(ctx context.Context, id string, request MemberRequest) (*Member, error) {
+	principal, err := s.authorize(ctx, id, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	member := Member{WorkspaceID: id, OwnerID: strings.TrimSpace(request.OwnerID), Role: request.Role, CreatedAt: time.Now().UTC()}
+	if member.OwnerID == principal.OwnerID {
+		return nil, ErrOwnMembership
+	}
+
+	if err := s.repository.SaveMember(ctx, member); err != nil {
+		return nil, fmt.Errorf("error saving member of '%s': %w", id, err)
+	}
+	return &member, nil
+}
+
+// RemoveMember takes the workspace away from the member. Admins cannot remove
+// themselves, so every workspace keeps one.
+func (s service) RemoveMember(ctx context.Context, id string, ownerID string) error {
+	principal, err := s.authorize(ctx, id, RoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	if ownerID == principal.OwnerID {
+		return ErrOwnMembership
+	}
+
+	if err := s.repository.DeleteMember(ctx, id, ownerID); err != nil {
+		return fmt.Errorf("failed to remove member of '%s': %w", id, err)
+	}
+	return nil
+}
+
+func (s service) Resolve(ctx context.Context, id string) (Scope, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return Scope{}, auth.ErrKeyRequired
+	}
+
+	member, err := s.repository.GetMember(ctx, id, principal.OwnerID)
+	if goerrors.Is(err, ErrMemberNotFound) {
+		return Scope{}, ErrNotMember
+	}
+
+	if err != nil {
+		return Scope{}, fmt.Errorf("failed to get membership of '%s': %w", id, err)
+	}
+	return Scope{WorkspaceID: id, Role: member.Role}, nil
+}
+
+// authorize fails unless the owner of the request has at least the role in
+// the workspace.
+func (s service) authorize(ctx context.Context, id string, role Role) (auth.Principal, error) {
+	principal, _ := auth.FromContext(ctx)
+	scope, err := s.Resolve(ctx, id)
+	if err != nil {
+		return principal, err
+	}
+
+	if !scope.Role.Allows(role) {
+		return principal, ErrForbidden
+	}
+	return principal, nil
+}
@@ -0,0 +1,118 @@
+package workspace_test
+
+import (
+	"context"
+	"sketch/internal/auth"
+	"sketch/internal/workspace"
+	mock_workspace "sketch/internal/workspace/mocks"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Create(t *testing.T) {
+	t.Run("should make the owner of the request its admin", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_workspace.NewMockRepository(ctrl)
+		service := workspace.NewService(repositoryMock)
+		ctx := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+
+		repositoryMock.EXPECT().Save(ctx, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, created workspace.Workspace, admin workspace.Member) error {
+				assert.Equal(t, created.ID, admin.WorkspaceID)
+				assert.Equal(t, "owner", admin.OwnerID)
+				assert.Equal(t, workspace.RoleAdmin, admin.Role)
+				return nil
+			})
+
+		created, err := service.Create(ctx, workspace.WorkspaceRequest{Name: " design "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "design", created.Name)
+	})
+
+	t.Run("when the request is anonymous, should require a key", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		service := workspace.NewService(mock_workspace.NewMockRepository(ctrl))
+
+		created, err := service.Create(context.Background(), workspace.WorkspaceRequest{Name: "design"})
+
+		assert.ErrorIs(t, err, auth.ErrKeyRequired)
+		assert.Nil(t, created)
+	})
+}
+
+func TestService_SaveMember(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+	request := workspace.MemberRequest{OwnerID: "other", Role: workspace.RoleEditor}
+
+	t.Run("when the request is from an admin, should save the member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_workspace.NewMockRepository(ctrl)
+		service := workspace.NewService(repositoryMock)
+
+		repositoryMock.EXPECT().GetMember(ctx, "123", "owner").Return(workspace.Member{Role: workspace.RoleAdmin}, nil)
+		repositoryMock.EXPECT().SaveMember(ctx, gomock.Any()).Return(nil)
+
+		member, err := service.SaveMember(ctx, "123", request)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "other", member.OwnerID)
+		assert.Equal(t, workspace.RoleEditor, member.Role)
+	})
+
+	t.Run("when the request is from an editor, should forbid it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_workspace.NewMockRepository(ctrl)
+		service := workspace.NewService(repositoryMock)
+
+		repositoryMock.EXPECT().GetMember(ctx, "123", "owner").Return(workspace.Member{Role: workspace.RoleEditor}, nil)
+
+		member, err := service.SaveMember(ctx, "123", request)
+
+		assert.ErrorIs(t, err, workspace.ErrForbidden)
+		assert.Nil(t, member)
+	})
+
+	t.Run("when the admin changes its own role, should refuse it", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_workspace.NewMockRepository(ctrl)
+		service := workspace.NewService(repositoryMock)
+
+		repositoryMock.EXPECT().GetMember(ctx, "123", "owner").Return(workspace.Member{Role: workspace.RoleAdmin}, nil)
+
+		_, err := service.SaveMember(ctx, "123", workspace.MemberRequest{OwnerID: "owner", Role: workspace.RoleViewer})
+
+		assert.ErrorIs(t, err, workspace.ErrOwnMembership)
+	})
+}
+
+func TestService_Resolve(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{KeyID: "1", OwnerID: "owner"})
+
+	t.Run("when the owner is a member, should return its role", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_workspace.NewMockRepository(ctrl)
+		service := workspace.NewService(repositoryMock)
+
+		repositoryMock.EXPECT().GetMember(ctx, "123", "owner").Return(workspace.Member{Role: workspace.RoleViewer}, nil)
+
+		scope, err := service.Resolve(ctx, "123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, workspace.Scope{WorkspaceID: "123", Role: workspace.RoleViewer}, scope)
+	})
+
+	t.Run("when the owner is not a member, should return not member", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		repositoryMock := mock_workspace.NewMockRepository(ctrl)
+		service := workspace.NewService(repositoryMock)
+
+		repositoryMock.EXPECT().GetMember(ctx, "123", "owner").Return(workspace.Member{}, workspace.ErrMemberNotFound)
+
+		_, err := service.Resolve(ctx, "123")
+
+		assert.ErrorIs(t, err, workspace.ErrNotMember)
+	})
+}
@@ -0,0 +1,125 @@
+// Package workspace groups canvases in workspaces shared by their members.
+// Each member has a role deciding what it may do with the canvases of the
+// workspace, and requests pick the workspace they act in.
+package workspace
+
+import (
+	"context"
+	"sketch/internal/errors"
+	"strings"
+	"time"
+)
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleAdmin  Role = "admin"
+
+	maxNameLength = 64
+)
+
+var (
+	ErrInvalidName    = errors.Error("name must have between 1 and 64 characters")
+	ErrInvalidRole    = errors.Error("role must be viewer, editor or admin")
+	ErrInvalidMember  = errors.Error("owner_id is required")
+	ErrNotMember      = errors.Error("not a member of the workspace")
+	ErrForbidden      = errors.Error("your role in the workspace does not allow this")
+	ErrMemberNotFound = errors.Error("member not found")
+	ErrOwnMembership  = errors.Error("admins cannot change their own membership")
+)
+
+// roleRanks orders the roles, each one may do everything the previous ones
+// may.
+var roleRanks = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
+}
+
+type (
+	Role string
+
+	Workspace struct {
+		ID   string `json:"id" db:"id"`
+		Name string `json:"name" db:"name"`
+		// Role is the one of the member the workspace is listed for.
+		Role      Role      `json:"role,omitempty" db:"role"`
+		CreatedAt time.Time `json:"created_at" db:"created_at"`
+	}
+
+	// Member gives the keys of an owner a role in a workspace.
+	Member struct {
+		WorkspaceID string    `json:"workspace_id" db:"workspace_id"`
+		OwnerID     string    `json:"owner_id" db:"owner_id"`
+		Role        Role      `json:"role" db:"role"`
+		CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	}
+
+	WorkspaceRequest struct {
+		Name string `json:"name"`
+	}
+
+	MemberRequest struct {
+		OwnerID string `json:"owner_id"`
+		Role    Role   `json:"role"`
+	}
+
+	// Scope is the workspace a request acts in, with the role it has there.
+	// The zero Scope is outside every workspace.
+	Scope struct {
+		WorkspaceID string
+		Role        Role
+	}
+
+	scopeKey struct{}
+)
+
+// Allows tells whether the role may do what the required one may.
+func (r Role) Allows(required Role) bool {
+	return roleRanks[r] >= roleRanks[required]
+}
+
+func (r Role) Validate() error {
+	if _, ok := roleRanks[r]; !ok {
+		return ErrInvalidRole
+	}
+	return nil
+}
+
+func (r WorkspaceRequest) Validate() error {
+	if name := strings.TrimSpace(r.Name); name == "" || len(name) > maxNameLength {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+func (r MemberRequest) Validate() error {
+	if strings.TrimSpace(r.OwnerID) == "" {
+		return ErrInvalidMember
+	}
+	return r.Role.Validate()
+}
+
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+func FromContext(ctx context.Context) Scope {
+	scope, _ := ctx.Value(scopeKey{}).(Scope)
+	return scope
+}
+
+// ID returns the workspace of the request, empty outside every workspace.
+func ID(ctx context.Context) string {
+	return FromContext(ctx).WorkspaceID
+}
+
+// Check fails when the request acts in a workspace where its role does not
+// allow what the given one does. Requests outside workspaces pass.
+func Check(ctx context.Context, role Role) error {
+	scope := FromContext(ctx)
+	if scope.WorkspaceID == "" || scope.Role.Allows(role) {
+		return nil
+	}
+	return ErrForbidden
+}
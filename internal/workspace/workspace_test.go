@@ -0,0 +1,35 @@
+package workspace_test
+
+import (
+	"context"
+	"sketch/internal/workspace"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	t.Run("when the request is outside every workspace, should allow it", func(t *testing.T) {
+		assert.NoError(t, workspace.Check(context.Background(), workspace.RoleAdmin))
+	})
+
+	t.Run("when the role allows it, should allow it", func(t *testing.T) {
+		ctx := workspace.WithScope(context.Background(), workspace.Scope{WorkspaceID: "123", Role: workspace.RoleAdmin})
+
+		assert.NoError(t, workspace.Check(ctx, workspace.RoleEditor))
+	})
+
+	t.Run("when the role does not allow it, should forbid it", func(t *testing.T) {
+		ctx := workspace.WithScope(context.Background(), workspace.Scope{WorkspaceID: "123", Role: workspace.RoleViewer})
+
+		assert.ErrorIs(t, workspace.Check(ctx, workspace.RoleEditor), workspace.ErrForbidden)
+	})
+}
+
+func TestMemberRequest_Validate(t *testing.T) {
+	t.Run("when the role does not exist, should return invalid role", func(t *testing.T) {
+		err := workspace.MemberRequest{OwnerID: "owner", Role: "owner"}.Validate()
+
+		assert.ErrorIs(t, err, workspace.ErrInvalidRole)
+	})
+}